@@ -1,11 +1,19 @@
 package access
 
 import (
+	"database/sql"
 	"github.com/jinzhu/gorm"
 )
 
 type _default struct {
 	tx *gorm.DB
+
+	// account and RPC that requested writes in tx, recorded in audit event (add in v.1.2.0)
+	actorUUID string
+	rpc       string
+
+	// named locks of MySQL acquired in tx, which are held by session so must be released before tx end (add in v.1.2.0)
+	sessionLocks []string
 }
 
 func Default(tx *gorm.DB) *_default {
@@ -17,9 +25,48 @@ func (d *_default) BeginTx() {
 }
 
 func (d *_default) Commit() *gorm.DB {
+	d.releaseSessionLocks()
 	return d.tx.Commit()
 }
 
 func (d *_default) Rollback() *gorm.DB {
+	d.releaseSessionLocks()
 	return d.tx.Rollback()
+}
+
+// method that acquire named lock without waiting if it is not held by other tx, and hold it until tx end (add in v.1.2.0)
+// it is used for running job in only one of service nodes, SQLite always acquire lock because tx is serialized there
+func (d *_default) TryLockUntilTxEnd(lockName string) (acquired bool, err error) {
+	switch d.tx.Dialect().GetName() {
+	case "sqlite3":
+		acquired = true
+	case "postgres":
+		err = d.tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", lockName).Row().Scan(&acquired)
+	default:
+		var result sql.NullInt64
+		if err = d.tx.Raw("SELECT GET_LOCK(?, 0)", lockName).Row().Scan(&result); err != nil {
+			return
+		}
+		if acquired = result.Valid && result.Int64 == 1; acquired {
+			d.sessionLocks = append(d.sessionLocks, lockName)
+		}
+	}
+	return
+}
+
+// method that release named locks of MySQL in connection of tx, before the connection is returned to pool
+func (d *_default) releaseSessionLocks() {
+	for _, lockName := range d.sessionLocks {
+		d.tx.Exec("DO RELEASE_LOCK(?)", lockName)
+	}
+	d.sessionLocks = nil
+}
+
+// function that return tx locking selected rows until tx end (add in v.1.2.0)
+// SQLite doesn't support FOR UPDATE, but tx is serialized there because only one connection is opened (see db.Connect)
+func (d *_default) forUpdate() *gorm.DB {
+	if d.tx.Dialect().GetName() == "sqlite3" {
+		return d.tx
+	}
+	return d.tx.Set("gorm:query_option", "FOR UPDATE")
 }
\ No newline at end of file
@@ -0,0 +1,182 @@
+// add file in v.1.2.0
+// this file declare method that record audit event about write of business table (account, inform, unsigned student)
+// audit event is created in same tx with the write, so that the write and its record are committed or rolled back together
+
+package access
+
+import (
+	"auth/model"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+const (
+	auditActionCreate  = "create"
+	auditActionUpdate  = "update"
+	auditActionDelete  = "delete"
+	auditActionRestore = "restore"
+	auditActionPurge   = "purge"
+)
+
+// columns whose value must not be stored in audit event, only whether it is changed is recorded
+var maskedAuditColumns = map[string]bool{
+	"student_pw": true,
+	"teacher_pw": true,
+	"parent_pw":  true,
+	"admin_pw":   true,
+	"auth_code":  true,
+}
+
+const maskedAuditValue = "********"
+
+// columns managed by gorm are not recorded, because they are changed with every write
+var ignoredAuditColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+type auditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func (d *_default) SetAuditContext(actorUUID, rpc string) {
+	d.actorUUID = actorUUID
+	d.rpc = rpc
+}
+
+// method that run write on rows matched with query and record audit event with state of each row before and after write
+// target must be pointer of zero value of model, and nothing is recorded if no row matched
+func (d *_default) writeWithAudit(action string, target interface{}, write func() error, query string, args ...interface{}) (err error) {
+	rowType := reflect.TypeOf(target).Elem()
+
+	// rows to be restored or purged are soft deleted, so they must be selected including deleted rows
+	finder := d.tx
+	if action == auditActionRestore || action == auditActionPurge {
+		finder = d.tx.Unscoped()
+	}
+
+	beforeRows := reflect.New(reflect.SliceOf(reflect.PtrTo(rowType)))
+	if err = finder.Where(query, args...).Find(beforeRows.Interface()).Error; err != nil {
+		return
+	}
+
+	if err = write(); err != nil {
+		return
+	}
+
+	for i := 0; i < beforeRows.Elem().Len(); i++ {
+		before := beforeRows.Elem().Index(i).Interface()
+
+		var afterSnapshot map[string]interface{}
+		if action == auditActionUpdate || action == auditActionRestore {
+			after := reflect.New(rowType).Interface()
+			if err = d.tx.Unscoped().Where("id = ?", reflect.ValueOf(before).Elem().FieldByName("ID").Interface()).Find(after).Error; err != nil {
+				return
+			}
+			afterSnapshot = d.snapshotOf(after)
+		}
+
+		if err = d.recordAudit(action, before, d.snapshotOf(before), afterSnapshot); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// method that record audit event about row created with Create method
+func (d *_default) recordCreation(row interface{}) error {
+	return d.recordAudit(auditActionCreate, row, nil, d.snapshotOf(row))
+}
+
+func (d *_default) recordAudit(action string, row interface{}, before, after map[string]interface{}) (err error) {
+	diff := map[string]auditChange{}
+	for column := range mergedKeysOf(before, after) {
+		beforeValue, afterValue := before[column], after[column]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		if maskedAuditColumns[column] {
+			if beforeValue != nil { beforeValue = maskedAuditValue }
+			if afterValue != nil  { afterValue = maskedAuditValue }
+		}
+		diff[column] = auditChange{Before: beforeValue, After: afterValue}
+	}
+
+	// update which doesn't change any value is not recorded, but restore is recorded though only deleted_at is changed
+	if action == auditActionUpdate && len(diff) == 0 {
+		return
+	}
+
+	encodedDiff, err := json.Marshal(diff)
+	if err != nil {
+		return
+	}
+
+	err = d.tx.Create(&model.AuditEvent{
+		ActorUUID: d.actorUUID,
+		RPC:       d.rpc,
+		Entity:    d.tx.NewScope(row).TableName(),
+		TargetKey: auditTargetKeyOf(row),
+		Action:    action,
+		Diff:      string(encodedDiff),
+	}).Error
+	return
+}
+
+// method that return value of columns in row except ignored columns
+func (d *_default) snapshotOf(row interface{}) (snapshot map[string]interface{}) {
+	snapshot = map[string]interface{}{}
+	for _, field := range d.tx.NewScope(row).Fields() {
+		if field.IsIgnored || field.Relationship != nil || ignoredAuditColumns[field.DBName] {
+			continue
+		}
+		snapshot[field.DBName] = field.Field.Interface()
+	}
+	return
+}
+
+func mergedKeysOf(maps ...map[string]interface{}) (keys map[string]bool) {
+	keys = map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	return
+}
+
+// function that return value identifying row in audit event
+// auth code of unsigned student is secret, so grade, class and student number is used instead of it
+func auditTargetKeyOf(row interface{}) string {
+	switch row := row.(type) {
+	case *model.StudentAuth:
+		return string(row.UUID)
+	case *model.TeacherAuth:
+		return string(row.UUID)
+	case *model.ParentAuth:
+		return string(row.UUID)
+	case *model.AdminAuth:
+		return string(row.UUID)
+	case *model.StudentInform:
+		return string(row.StudentUUID)
+	case *model.TeacherInform:
+		return string(row.TeacherUUID)
+	case *model.ParentInform:
+		return string(row.ParentUUID)
+	case *model.GraduatedStudent:
+		return string(row.StudentUUID)
+	case *model.SchoolTerm:
+		return fmt.Sprintf("%d", row.AcademicYear)
+	case *model.MessageTemplate:
+		return fmt.Sprintf("%s/v%d", row.Name, row.Version)
+	case *model.ParentChildren:
+		return fmt.Sprintf("%s/%d-%d-%d", row.ParentUUID, row.Grade, row.Class, row.StudentNumber)
+	case *model.UnsignedStudent:
+		return fmt.Sprintf("%d-%d-%d", row.Grade, row.Class, row.StudentNumber)
+	}
+	return fmt.Sprintf("%v", reflect.ValueOf(row).Elem().FieldByName("ID"))
+}
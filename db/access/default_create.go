@@ -7,6 +7,9 @@ import (
 
 func (d *_default) CreateStudentAuth(auth *model.StudentAuth) (*model.StudentAuth, error) {
 	result := d.tx.Create(auth)
+	if result.Error == nil {
+		result.Error = d.recordCreation(auth)
+	}
 	if auth, ok := result.Value.(*model.StudentAuth); ok {
 		return auth, result.Error
 	}
@@ -18,6 +21,9 @@ func (d *_default) CreateStudentAuth(auth *model.StudentAuth) (*model.StudentAut
 
 func (d *_default) CreateTeacherAuth(auth *model.TeacherAuth) (*model.TeacherAuth, error) {
 	result := d.tx.Create(auth)
+	if result.Error == nil {
+		result.Error = d.recordCreation(auth)
+	}
 	if auth, ok := result.Value.(*model.TeacherAuth); ok {
 		return auth, result.Error
 	}
@@ -29,6 +35,9 @@ func (d *_default) CreateTeacherAuth(auth *model.TeacherAuth) (*model.TeacherAut
 
 func (d *_default) CreateParentAuth(auth *model.ParentAuth) (*model.ParentAuth, error) {
 	result := d.tx.Create(auth)
+	if result.Error == nil {
+		result.Error = d.recordCreation(auth)
+	}
 	if auth, ok := result.Value.(*model.ParentAuth); ok {
 		return auth, result.Error
 	}
@@ -40,6 +49,9 @@ func (d *_default) CreateParentAuth(auth *model.ParentAuth) (*model.ParentAuth,
 
 func (d *_default) CreateParentChildren(child *model.ParentChildren) (*model.ParentChildren, error) {
 	result := d.tx.Create(child)
+	if result.Error == nil {
+		result.Error = d.recordCreation(child)
+	}
 	if auth, ok := result.Value.(*model.ParentChildren); ok {
 		return auth, result.Error
 	}
@@ -51,6 +63,9 @@ func (d *_default) CreateParentChildren(child *model.ParentChildren) (*model.Par
 
 func (d *_default) CreateStudentInform(inform *model.StudentInform) (*model.StudentInform, error) {
 	result := d.tx.Create(inform)
+	if result.Error == nil {
+		result.Error = d.recordCreation(inform)
+	}
 	if inform, ok := result.Value.(*model.StudentInform); ok {
 		return inform, result.Error
 	}
@@ -62,6 +77,9 @@ func (d *_default) CreateStudentInform(inform *model.StudentInform) (*model.Stud
 
 func (d *_default) CreateTeacherInform(inform *model.TeacherInform) (*model.TeacherInform, error) {
 	result := d.tx.Create(inform)
+	if result.Error == nil {
+		result.Error = d.recordCreation(inform)
+	}
 	if inform, ok := result.Value.(*model.TeacherInform); ok {
 		return inform, result.Error
 	}
@@ -73,6 +91,9 @@ func (d *_default) CreateTeacherInform(inform *model.TeacherInform) (*model.Teac
 
 func (d *_default) CreateParentInform(inform *model.ParentInform) (*model.ParentInform, error) {
 	result := d.tx.Create(inform)
+	if result.Error == nil {
+		result.Error = d.recordCreation(inform)
+	}
 	if inform, ok := result.Value.(*model.ParentInform); ok {
 		return inform, result.Error
 	}
@@ -84,6 +105,9 @@ func (d *_default) CreateParentInform(inform *model.ParentInform) (*model.Parent
 
 func (d *_default) AddUnsignedStudent(student *model.UnsignedStudent) (*model.UnsignedStudent, error) {
 	result := d.tx.Create(student)
+	if result.Error == nil {
+		result.Error = d.recordCreation(student)
+	}
 	if inform, ok := result.Value.(*model.UnsignedStudent); ok {
 		return inform, result.Error
 	}
@@ -92,3 +116,167 @@ func (d *_default) AddUnsignedStudent(student *model.UnsignedStudent) (*model.Un
 	}
 	return nil, result.Error
 }
+
+func (d *_default) CreateRefreshToken(token *model.RefreshToken) (*model.RefreshToken, error) {
+	result := d.tx.Create(token)
+	if token, ok := result.Value.(*model.RefreshToken); ok {
+		return token, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.RefreshTokenAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateRevokedToken(token *model.RevokedToken) (*model.RevokedToken, error) {
+	result := d.tx.Create(token)
+	if token, ok := result.Value.(*model.RevokedToken); ok {
+		return token, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.RevokedTokenAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateSessionRevocation(revocation *model.SessionRevocation) (*model.SessionRevocation, error) {
+	result := d.tx.Create(revocation)
+	if revocation, ok := result.Value.(*model.SessionRevocation); ok {
+		return revocation, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.SessionRevocationAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateLoginThrottle(throttle *model.LoginThrottle) (*model.LoginThrottle, error) {
+	result := d.tx.Create(throttle)
+	if throttle, ok := result.Value.(*model.LoginThrottle); ok {
+		return throttle, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.LoginThrottleAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreatePasswordReset(reset *model.PasswordReset) (*model.PasswordReset, error) {
+	result := d.tx.Create(reset)
+	if reset, ok := result.Value.(*model.PasswordReset); ok {
+		return reset, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.PasswordResetAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreatePasswordHistory(history *model.PasswordHistory) (*model.PasswordHistory, error) {
+	result := d.tx.Create(history)
+	if history, ok := result.Value.(*model.PasswordHistory); ok {
+		return history, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.PasswordHistoryAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateSession(session *model.Session) (*model.Session, error) {
+	result := d.tx.Create(session)
+	if session, ok := result.Value.(*model.Session); ok {
+		return session, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.SessionAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateAuthLog(log *model.AuthLog) (*model.AuthLog, error) {
+	result := d.tx.Create(log)
+	if log, ok := result.Value.(*model.AuthLog); ok {
+		return log, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.AuthLogAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateTwoFactorAuth(auth *model.TwoFactorAuth) (*model.TwoFactorAuth, error) {
+	result := d.tx.Create(auth)
+	if auth, ok := result.Value.(*model.TwoFactorAuth); ok {
+		return auth, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.TwoFactorAuthAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateRecoveryCode(code *model.RecoveryCode) (*model.RecoveryCode, error) {
+	result := d.tx.Create(code)
+	if code, ok := result.Value.(*model.RecoveryCode); ok {
+		return code, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.RecoveryCodeAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateGraduatedStudent(student *model.GraduatedStudent) (*model.GraduatedStudent, error) {
+	result := d.tx.Create(student)
+	if result.Error == nil {
+		result.Error = d.recordCreation(student)
+	}
+	if student, ok := result.Value.(*model.GraduatedStudent); ok {
+		return student, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.GraduatedStudentAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateSchoolTerm(term *model.SchoolTerm) (*model.SchoolTerm, error) {
+	result := d.tx.Create(term)
+	if result.Error == nil {
+		result.Error = d.recordCreation(term)
+	}
+	if term, ok := result.Value.(*model.SchoolTerm); ok {
+		return term, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.SchoolTermAssertionError
+	}
+	return nil, result.Error
+}
+
+func (d *_default) CreateMessageTemplate(template *model.MessageTemplate) (*model.MessageTemplate, error) {
+	result := d.tx.Create(template)
+	if result.Error == nil {
+		result.Error = d.recordCreation(template)
+	}
+	if template, ok := result.Value.(*model.MessageTemplate); ok {
+		return template, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.MessageTemplateAssertionError
+	}
+	return nil, result.Error
+}
+
+// outbox message is delivery record of message, not business data, so that it is not recorded as audit event
+func (d *_default) CreateOutboxMessage(message *model.OutboxMessage) (*model.OutboxMessage, error) {
+	result := d.tx.Create(message)
+	if message, ok := result.Value.(*model.OutboxMessage); ok {
+		return message, result.Error
+	}
+	if result.Error == nil {
+		result.Error = errors.OutboxMessageAssertionError
+	}
+	return nil, result.Error
+}
@@ -2,39 +2,204 @@ package access
 
 import (
 	"auth/model"
+	"github.com/jinzhu/gorm"
+	"time"
 )
 
 func (d *_default) DeleteStudentAuth(uuid string) (err error) {
-	err = d.tx.Where("uuid = ?", uuid).Delete(&model.StudentAuth{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.StudentAuth{}, func() error {
+		return d.tx.Where("uuid = ?", uuid).Delete(&model.StudentAuth{}).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
 func (d *_default) DeleteTeacherAuth(uuid string) (err error) {
-	err = d.tx.Where("uuid = ?", uuid).Delete(&model.TeacherAuth{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.TeacherAuth{}, func() error {
+		return d.tx.Where("uuid = ?", uuid).Delete(&model.TeacherAuth{}).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
 func (d *_default) DeleteParentAuth(uuid string) (err error) {
-	err = d.tx.Where("uuid = ?", uuid).Delete(&model.ParentAuth{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.ParentAuth{}, func() error {
+		return d.tx.Where("uuid = ?", uuid).Delete(&model.ParentAuth{}).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
 func (d *_default) DeleteStudentInform(studentUUID string) (err error) {
-	err = d.tx.Where("student_uuid = ?", studentUUID).Delete(&model.StudentInform{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.StudentInform{}, func() error {
+		return d.tx.Where("student_uuid = ?", studentUUID).Delete(&model.StudentInform{}).Error
+	}, "student_uuid = ?", studentUUID)
 	return
 }
 
 func (d *_default) DeleteTeacherInform(teacherUUID string) (err error) {
-	err = d.tx.Where("teacher_uuid = ?", teacherUUID).Delete(&model.TeacherInform{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.TeacherInform{}, func() error {
+		return d.tx.Where("teacher_uuid = ?", teacherUUID).Delete(&model.TeacherInform{}).Error
+	}, "teacher_uuid = ?", teacherUUID)
 	return
 }
 
 func (d *_default) DeleteParentInform(parentUUID string) (err error) {
-	err = d.tx.Where("parent_uuid = ?", parentUUID).Delete(&model.ParentInform{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.ParentInform{}, func() error {
+		return d.tx.Where("parent_uuid = ?", parentUUID).Delete(&model.ParentInform{}).Error
+	}, "parent_uuid = ?", parentUUID)
 	return
 }
 
 func (d *_default) DeleteUnsignedStudent(authCode int64) (err error) {
-	err = d.tx.Where("auth_code = ?", authCode).Delete(&model.UnsignedStudent{}).Error
+	err = d.writeWithAudit(auditActionDelete, &model.UnsignedStudent{}, func() error {
+		return d.tx.Where("auth_code = ?", authCode).Delete(&model.UnsignedStudent{}).Error
+	}, "auth_code = ?", authCode)
+	return
+}
+
+func (d *_default) DeleteParentChildrenWithParentUUID(parentUUID string) (err error) {
+	err = d.writeWithAudit(auditActionDelete, &model.ParentChildren{}, func() error {
+		return d.tx.Where("parent_uuid = ?", parentUUID).Delete(&model.ParentChildren{}).Error
+	}, "parent_uuid = ?", parentUUID)
+	return
+}
+
+func (d *_default) DeleteParentChildrenWithStudentUUID(studentUUID string) (err error) {
+	err = d.writeWithAudit(auditActionDelete, &model.ParentChildren{}, func() error {
+		return d.tx.Where("student_uuid = ?", studentUUID).Delete(&model.ParentChildren{}).Error
+	}, "student_uuid = ?", studentUUID)
+	return
+}
+
+// it returns gorm.ErrRecordNotFound if token is already deleted, so that only one of concurrent rotations with the same token succeeds
+// (soft delete is UPDATE with deleted_at IS NULL, which waits for lock of the row and is evaluated again after other tx commits)
+func (d *_default) DeleteRefreshToken(tokenHash string) (err error) {
+	result := d.tx.Where("token_hash = ?", tokenHash).Delete(&model.RefreshToken{})
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	return
+}
+
+func (d *_default) DeleteRefreshTokensWithOwnerUUID(ownerUUID string) (err error) {
+	err = d.tx.Where("owner_uuid = ?", ownerUUID).Delete(&model.RefreshToken{}).Error
+	return
+}
+
+func (d *_default) DeleteRefreshTokensWithSessionID(sessionID string) (err error) {
+	err = d.tx.Where("session_id = ?", sessionID).Delete(&model.RefreshToken{}).Error
+	return
+}
+
+func (d *_default) DeleteSession(sessionID string) (err error) {
+	err = d.tx.Where("session_id = ?", sessionID).Delete(&model.Session{}).Error
+	return
+}
+
+func (d *_default) DeleteSessionsWithOwnerUUID(ownerUUID string) (err error) {
+	err = d.tx.Where("owner_uuid = ?", ownerUUID).Delete(&model.Session{}).Error
+	return
+}
+
+// login throttle is deleted permanently because it is only used for counting failure
+func (d *_default) DeleteLoginThrottle(throttleKey string) (err error) {
+	err = d.tx.Unscoped().Where("throttle_key = ?", throttleKey).Delete(&model.LoginThrottle{}).Error
+	return
+}
+
+// password reset is deleted permanently because code must not be used again
+func (d *_default) DeletePasswordReset(ownerUUID string) (err error) {
+	err = d.tx.Unscoped().Where("owner_uuid = ?", ownerUUID).Delete(&model.PasswordReset{}).Error
+	return
+}
+
+// password history over retention depth is deleted permanently because it is no longer checked
+func (d *_default) DeletePasswordHistoriesExceptRecent(ownerUUID string, keep int) (err error) {
+	recent := []*model.PasswordHistory{}
+	if err = d.tx.Where("owner_uuid = ?", ownerUUID).Order("id desc").Limit(keep).Find(&recent).Error; err != nil {
+		return
+	}
+
+	cascadeTx := d.tx.Unscoped().Where("owner_uuid = ?", ownerUUID)
+	if len(recent) != 0 {
+		cascadeTx = cascadeTx.Where("id < ?", recent[len(recent)-1].ID)
+	}
+	err = cascadeTx.Delete(&model.PasswordHistory{}).Error
+	return
+}
+
+// two factor auth is deleted permanently because secret must not be used again after disabled
+func (d *_default) DeleteTwoFactorAuth(ownerUUID string) (err error) {
+	err = d.tx.Unscoped().Where("owner_uuid = ?", ownerUUID).Delete(&model.TwoFactorAuth{}).Error
+	return
+}
+
+// recovery code is deleted permanently because it can be used only once
+// it returns gorm.ErrRecordNotFound if code not exists, so caller can know if code is valid
+func (d *_default) DeleteRecoveryCode(ownerUUID, codeHash string) (err error) {
+	result := d.tx.Unscoped().Where("owner_uuid = ? AND code_hash = ?", ownerUUID, codeHash).Delete(&model.RecoveryCode{})
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	return
+}
+
+func (d *_default) DeleteRecoveryCodesWithOwnerUUID(ownerUUID string) (err error) {
+	err = d.tx.Unscoped().Where("owner_uuid = ?", ownerUUID).Delete(&model.RecoveryCode{}).Error
+	return
+}
+
+// account rows soft deleted before deletedBefore are deleted permanently with data owned by the account
+// one audit event is recorded for each purged account, data owned by the account is deleted without its own event
+func (d *_default) PurgeDeletedAccounts(deletedBefore time.Time) (purgedCount int64, err error) {
+	var ownerUUIDs, parentUUIDs []string
+	for _, auth := range []interface{}{&model.StudentAuth{}, &model.TeacherAuth{}, &model.ParentAuth{}} {
+		var uuids []string
+		if err = d.tx.Unscoped().Model(auth).Where("deleted_at < ?", deletedBefore).Pluck("uuid", &uuids).Error; err != nil {
+			return
+		}
+		ownerUUIDs = append(ownerUUIDs, uuids...)
+		if _, ok := auth.(*model.ParentAuth); ok {
+			parentUUIDs = uuids
+		}
+	}
+
+	if len(ownerUUIDs) != 0 {
+		ownedData := []interface{}{&model.RefreshToken{}, &model.SessionRevocation{}, &model.Session{}, &model.PasswordReset{},
+			&model.PasswordHistory{}, &model.TwoFactorAuth{}, &model.RecoveryCode{}}
+		for _, data := range ownedData {
+			if err = d.tx.Unscoped().Where("owner_uuid IN (?)", ownerUUIDs).Delete(data).Error; err != nil {
+				return
+			}
+		}
+	}
+
+	// rows referencing account are deleted before the account, so that FK constraint is not violated
+	// (parent children -> informs -> parent uuid of student -> student auth -> parent & teacher auth)
+	for _, data := range []interface{}{&model.ParentChildren{}, &model.StudentInform{}, &model.TeacherInform{}, &model.ParentInform{}} {
+		if err = d.tx.Unscoped().Where("deleted_at < ?", deletedBefore).Delete(data).Error; err != nil {
+			return
+		}
+	}
+
+	// student of purged parent can be alive or deleted later than parent, so its parent uuid is cleared instead of purging it
+	if len(parentUUIDs) != 0 {
+		err = d.writeWithAudit(auditActionUpdate, &model.StudentAuth{}, func() error {
+			return d.tx.Unscoped().Model(&model.StudentAuth{}).Where("parent_uuid IN (?)", parentUUIDs).
+				UpdateColumn("parent_uuid", gorm.Expr("NULL")).Error
+		}, "parent_uuid IN (?)", parentUUIDs)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, auth := range []interface{}{&model.StudentAuth{}, &model.ParentAuth{}, &model.TeacherAuth{}} {
+		err = d.writeWithAudit(auditActionPurge, auth, func() error {
+			return d.tx.Unscoped().Where("deleted_at < ?", deletedBefore).Delete(auth).Error
+		}, "deleted_at < ?", deletedBefore)
+		if err != nil {
+			return
+		}
+	}
+
+	purgedCount = int64(len(ownerUUIDs))
 	return
 }
@@ -3,6 +3,7 @@ package access
 import (
 	"auth/model"
 	"github.com/jinzhu/gorm"
+	"time"
 )
 
 const (
@@ -80,8 +81,6 @@ func (d *_default) GetTeacherUUIDsWithInform(inform *model.TeacherInform) (uuidA
 	cascadeTx := d.tx.New()
 
 	if inform.TeacherUUID != emptyString { cascadeTx = cascadeTx.Where("teacher_uuid LIKE ?", "%"+inform.TeacherUUID+"%") }
-	if inform.Grade != emptyInt          { cascadeTx = cascadeTx.Where("grade = ?", inform.Grade) }
-	if inform.Class != emptyInt          { cascadeTx = cascadeTx.Where("class = ?", inform.Class) }
 	if inform.Name != emptyString        { cascadeTx = cascadeTx.Where("name LIKE ?", "%"+inform.Name+"%") }
 	if inform.PhoneNumber != emptyString { cascadeTx = cascadeTx.Where("phone_number LIKE ?", "%"+inform.PhoneNumber+"%") }
 
@@ -185,6 +184,72 @@ func (d *_default) GetUnsignedStudents(targetGrade, targetGroup, targetNumber in
 	return 
 }
 
+// rows are locked until tx end, so that student informs are not changed while academic year rollover
+func (d *_default) GetAllStudentInformsForUpdate() (informs []*model.StudentInform, err error) {
+	informs = []*model.StudentInform{}
+	err = d.forUpdate().Order("grade, class, student_number").Find(&informs).Error
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetCurrentSchoolTerm() (term *model.SchoolTerm, err error) {
+	term = new(model.SchoolTerm)
+	err = d.tx.Order("id desc").Limit(1).Find(term).Error
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetLatestMessageTemplateWithName(name string) (template *model.MessageTemplate, err error) {
+	template = new(model.MessageTemplate)
+	err = d.tx.Where("name = ?", name).Order("version desc").Limit(1).Find(template).Error
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetMessageTemplateWithNameAndVersion(name string, version int64) (template *model.MessageTemplate, err error) {
+	template = new(model.MessageTemplate)
+	err = d.tx.Where("name = ? AND version = ?", name, version).Find(template).Error
+	return
+}
+
+// rows are locked until tx end, so that same message is not claimed by worker of other service node
+func (d *_default) GetDueOutboxMessagesForUpdate(now time.Time, limit int) (messages []*model.OutboxMessage, err error) {
+	messages = []*model.OutboxMessage{}
+	err = d.forUpdate().Where("status = ? AND next_attempt_at <= ?", "pending", now).Order("id").Limit(limit).Find(&messages).Error
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetOutboxMessagesWithBatchID(batchID string) (messages []*model.OutboxMessage, err error) {
+	messages = []*model.OutboxMessage{}
+	err = d.tx.Where("batch_id = ?", batchID).Order("id").Find(&messages).Error
+	if err == nil && len(messages) == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetDeletedStudentAuthWithUUID(uuid string) (auth *model.StudentAuth, err error) {
+	auth = new(model.StudentAuth)
+	err = d.tx.Unscoped().Where("uuid = ? AND deleted_at IS NOT NULL", uuid).Find(auth).Error
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetDeletedTeacherAuthWithUUID(uuid string) (auth *model.TeacherAuth, err error) {
+	auth = new(model.TeacherAuth)
+	err = d.tx.Unscoped().Where("uuid = ? AND deleted_at IS NOT NULL", uuid).Find(auth).Error
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) GetDeletedParentAuthWithUUID(uuid string) (auth *model.ParentAuth, err error) {
+	auth = new(model.ParentAuth)
+	err = d.tx.Unscoped().Where("uuid = ? AND deleted_at IS NOT NULL", uuid).Find(auth).Error
+	return
+}
+
 func (d *_default) GetUnsignedStudentWithAuthCode(authCode int64) (student *model.UnsignedStudent, err error) {
 	student = new(model.UnsignedStudent)
 	err = d.tx.Where("auth_code = ?", authCode).Find(student).Error
@@ -196,3 +261,104 @@ func (d *_default) GetParentChildWithInform(grade, group, number int64, name str
 	err = d.tx.Where("grade = ? AND class = ? AND student_number = ? AND name = ?", grade, group, number, name).Find(child).Error
 	return
 }
+
+func (d *_default) GetRefreshTokenWithHash(tokenHash string) (token *model.RefreshToken, err error) {
+	token = new(model.RefreshToken)
+	err = d.tx.Where("token_hash = ?", tokenHash).Find(token).Error
+	return
+}
+
+// token soft deleted in rotation (or revocation) is returned, so that reuse of rotated token can be detected
+func (d *_default) GetDeletedRefreshTokenWithHash(tokenHash string) (token *model.RefreshToken, err error) {
+	token = new(model.RefreshToken)
+	err = d.tx.Unscoped().Where("token_hash = ? AND deleted_at IS NOT NULL", tokenHash).Find(token).Error
+	return
+}
+
+func (d *_default) GetRevokedTokenWithTokenID(tokenID string) (token *model.RevokedToken, err error) {
+	token = new(model.RevokedToken)
+	err = d.tx.Where("token_id = ?", tokenID).Find(token).Error
+	return
+}
+
+func (d *_default) GetLastSessionRevocationWithOwnerUUID(ownerUUID string) (revocation *model.SessionRevocation, err error) {
+	revocation = new(model.SessionRevocation)
+	err = d.tx.Where("owner_uuid = ?", ownerUUID).Order("revoked_before desc").First(revocation).Error
+	return
+}
+
+func (d *_default) GetSessionWithSessionID(sessionID string) (session *model.Session, err error) {
+	session = new(model.Session)
+	err = d.tx.Where("session_id = ?", sessionID).Find(session).Error
+	return
+}
+
+// sessions are returned in order of most recently seen first
+func (d *_default) GetSessionsWithOwnerUUID(ownerUUID string) (sessions []*model.Session, err error) {
+	sessions = []*model.Session{}
+	err = d.tx.Where("owner_uuid = ?", ownerUUID).Order("last_seen_at desc").Find(&sessions).Error
+	return
+}
+
+// logs are filtered with non-empty fields of criteria and created time in [since, until) if not zero value
+// logs are returned in order of most recently created first, up to limit
+func (d *_default) GetAuthLogs(criteria *model.AuthLog, since, until time.Time, limit int) (logs []*model.AuthLog, err error) {
+	cascadeTx := d.tx.New()
+
+	if criteria.Action != emptyString      { cascadeTx = cascadeTx.Where("action = ?", criteria.Action) }
+	if criteria.AccountType != emptyString { cascadeTx = cascadeTx.Where("account_type = ?", criteria.AccountType) }
+	if criteria.AccountID != emptyString   { cascadeTx = cascadeTx.Where("account_id = ?", criteria.AccountID) }
+	if criteria.OwnerUUID != emptyString   { cascadeTx = cascadeTx.Where("owner_uuid = ?", criteria.OwnerUUID) }
+	if criteria.Outcome != emptyString     { cascadeTx = cascadeTx.Where("outcome = ?", criteria.Outcome) }
+	if !since.IsZero()                     { cascadeTx = cascadeTx.Where("created_at >= ?", since) }
+	if !until.IsZero()                     { cascadeTx = cascadeTx.Where("created_at < ?", until) }
+
+	logs = []*model.AuthLog{}
+	err = cascadeTx.Order("id desc").Limit(limit).Find(&logs).Error
+	return
+}
+
+// events are filtered with non-empty fields of criteria and id less than beforeID if not zero value (keyset pagination)
+// events are returned in order of most recently created first, up to limit
+func (d *_default) GetAuditEvents(criteria *model.AuditEvent, beforeID uint, limit int) (events []*model.AuditEvent, err error) {
+	cascadeTx := d.tx.New()
+
+	if criteria.ActorUUID != emptyString { cascadeTx = cascadeTx.Where("actor_uuid = ?", criteria.ActorUUID) }
+	if criteria.RPC != emptyString       { cascadeTx = cascadeTx.Where("rpc = ?", criteria.RPC) }
+	if criteria.Entity != emptyString    { cascadeTx = cascadeTx.Where("entity = ?", criteria.Entity) }
+	if criteria.TargetKey != emptyString { cascadeTx = cascadeTx.Where("target_key = ?", criteria.TargetKey) }
+	if criteria.Action != emptyString    { cascadeTx = cascadeTx.Where("action = ?", criteria.Action) }
+	if beforeID != 0                     { cascadeTx = cascadeTx.Where("id < ?", beforeID) }
+
+	events = []*model.AuditEvent{}
+	err = cascadeTx.Order("id desc").Limit(limit).Find(&events).Error
+	return
+}
+
+// row is locked until tx end to count login failure correctly across replicas
+func (d *_default) GetLoginThrottleWithKey(throttleKey string) (throttle *model.LoginThrottle, err error) {
+	throttle = new(model.LoginThrottle)
+	err = d.forUpdate().Where("throttle_key = ?", throttleKey).Find(throttle).Error
+	return
+}
+
+// row is locked until tx end to count attempt and send count correctly across replicas
+func (d *_default) GetPasswordResetWithOwnerUUID(ownerUUID string) (reset *model.PasswordReset, err error) {
+	reset = new(model.PasswordReset)
+	err = d.forUpdate().Where("owner_uuid = ?", ownerUUID).Find(reset).Error
+	return
+}
+
+// histories are returned in order of most recently stored first
+func (d *_default) GetRecentPasswordHistoriesWithOwnerUUID(ownerUUID string, limit int) (histories []*model.PasswordHistory, err error) {
+	histories = []*model.PasswordHistory{}
+	err = d.tx.Where("owner_uuid = ?", ownerUUID).Order("id desc").Limit(limit).Find(&histories).Error
+	return
+}
+
+// row is locked until tx end to prevent same code from being used concurrently across replicas
+func (d *_default) GetTwoFactorAuthWithOwnerUUID(ownerUUID string) (auth *model.TwoFactorAuth, err error) {
+	auth = new(model.TwoFactorAuth)
+	err = d.forUpdate().Where("owner_uuid = ?", ownerUUID).Find(auth).Error
+	return
+}
@@ -3,6 +3,9 @@ package access
 import (
 	"auth/db/access/errors"
 	"auth/model"
+	"auth/tool/dberr"
+	"github.com/jinzhu/gorm"
+	"time"
 )
 
 func (d *_default) ModifyStudentInform(uuid string, revisionInform *model.StudentInform) (err error) {
@@ -21,7 +24,9 @@ func (d *_default) ModifyStudentInform(uuid string, revisionInform *model.Studen
 	if revisionInform.ProfileURI != emptyString   { contextForUpdate[revisionInform.ProfileURI.KeyName()] = revisionInform.ProfileURI }
 	if revisionInform.ParentStatus != emptyString { contextForUpdate[revisionInform.ParentStatus.KeyName()] = revisionInform.ParentStatus }
 
-	err = d.tx.Model(&model.StudentInform{}).Where("student_uuid = ?", uuid).Updates(contextForUpdate).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.StudentInform{}, func() error {
+		return d.tx.Model(&model.StudentInform{}).Where("student_uuid = ?", uuid).Updates(contextForUpdate).Error
+	}, "student_uuid = ?", uuid)
 	return
 }
 
@@ -52,7 +57,9 @@ func (d *_default) ModifyTeacherInform(uuid string, revisionInform *model.Teache
 		}
 	}
 
-	err = d.tx.Model(&model.TeacherInform{}).Where("teacher_uuid = ?", uuid).Updates(contextForUpdate).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.TeacherInform{}, func() error {
+		return d.tx.Model(&model.TeacherInform{}).Where("teacher_uuid = ?", uuid).Updates(contextForUpdate).Error
+	}, "teacher_uuid = ?", uuid)
 	return
 }
 
@@ -67,27 +74,45 @@ func (d *_default) ModifyParentInform(uuid string, revisionInform *model.ParentI
 	if revisionInform.Name != emptyString        { contextForUpdate[revisionInform.Name.KeyName()] = revisionInform.Name }
 	if revisionInform.PhoneNumber != emptyString { contextForUpdate[revisionInform.PhoneNumber.KeyName()] = revisionInform.PhoneNumber }
 
-	err = d.tx.Model(&model.ParentInform{}).Where("parent_uuid = ?", uuid).Updates(contextForUpdate).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.ParentInform{}, func() error {
+		return d.tx.Model(&model.ParentInform{}).Where("parent_uuid = ?", uuid).Updates(contextForUpdate).Error
+	}, "parent_uuid = ?", uuid)
 	return
 }
 
 func (d *_default) ChangeStudentPW(uuid string, studentPW string) (err error) {
-	err = d.tx.Model(&model.StudentAuth{}).Where("uuid = ?", uuid).Update("student_pw", studentPW).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.StudentAuth{}, func() error {
+		return d.tx.Model(&model.StudentAuth{}).Where("uuid = ?", uuid).Update("student_pw", studentPW).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
 func (d *_default) ChangeTeacherPW(uuid string, teacherPW string) (err error) {
-	err = d.tx.Model(&model.TeacherAuth{}).Where("uuid = ?", uuid).Update("teacher_pw", teacherPW).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.TeacherAuth{}, func() error {
+		return d.tx.Model(&model.TeacherAuth{}).Where("uuid = ?", uuid).Update("teacher_pw", teacherPW).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
 func (d *_default) ChangeParentPW(uuid string, parentPW string) (err error) {
-	err = d.tx.Model(&model.ParentAuth{}).Where("uuid = ?", uuid).Update("parent_pw", parentPW).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.ParentAuth{}, func() error {
+		return d.tx.Model(&model.ParentAuth{}).Where("uuid = ?", uuid).Update("parent_pw", parentPW).Error
+	}, "uuid = ?", uuid)
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) ChangeAdminPW(uuid string, adminPW string) (err error) {
+	err = d.writeWithAudit(auditActionUpdate, &model.AdminAuth{}, func() error {
+		return d.tx.Model(&model.AdminAuth{}).Where("uuid = ?", uuid).Update("admin_pw", adminPW).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
 func (d *_default) ChangeParentUUID(uuid string, parentUUID string) (err error) {
-	err = d.tx.Model(&model.StudentAuth{}).Where("uuid = ?", uuid).Update("parent_uuid", parentUUID).Error
+	err = d.writeWithAudit(auditActionUpdate, &model.StudentAuth{}, func() error {
+		return d.tx.Model(&model.StudentAuth{}).Where("uuid = ?", uuid).Update("parent_uuid", parentUUID).Error
+	}, "uuid = ?", uuid)
 	return
 }
 
@@ -103,8 +128,198 @@ func (d *_default) ModifyParentChildren(child *model.ParentChildren, revision *m
 		contextForUpdate[revision.StudentUUID.KeyName()] = revision.StudentUUID
 	}
 	
-	whereDB := d.tx.Model(&model.ParentChildren{}).Where("parent_uuid = ?", child.ParentUUID).
-		Where("grade = ? AND class = ? AND student_number = ?", child.Grade, child.Class, child.StudentNumber)
-	err = whereDB.Updates(contextForUpdate).Error
+	query := "parent_uuid = ? AND grade = ? AND class = ? AND student_number = ?"
+	args := []interface{}{child.ParentUUID, child.Grade, child.Class, child.StudentNumber}
+	err = d.writeWithAudit(auditActionUpdate, &model.ParentChildren{}, func() error {
+		return d.tx.Model(&model.ParentChildren{}).Where(query, args...).Updates(contextForUpdate).Error
+	}, query, args...)
+	return
+}
+
+// add in v.1.2.0
+// only grade, class and student number are changed, because they are changed with student inform in academic year rollover
+func (d *_default) ModifyParentChildrenWithStudentUUID(studentUUID string, revision *model.ParentChildren) (err error) {
+	contextForUpdate := make(map[string]interface{}, 3)
+
+	if revision.Grade != emptyInt         { contextForUpdate[revision.Grade.KeyName()] = revision.Grade }
+	if revision.Class != emptyInt         { contextForUpdate[revision.Class.KeyName()] = revision.Class }
+	if revision.StudentNumber != emptyInt { contextForUpdate[revision.StudentNumber.KeyName()] = revision.StudentNumber }
+
+	err = d.writeWithAudit(auditActionUpdate, &model.ParentChildren{}, func() error {
+		return d.tx.Model(&model.ParentChildren{}).Where("student_uuid = ?", studentUUID).Updates(contextForUpdate).Error
+	}, "student_uuid = ?", studentUUID)
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) ChangeUnsignedStudentAuthCode(authCode int64, newAuthCode int64, expiresAt time.Time) (err error) {
+	contextForUpdate := map[string]interface{}{
+		model.AuthCode(newAuthCode).KeyName(): newAuthCode,
+		"auth_code_expires_at":                expiresAt,
+	}
+	err = d.writeWithAudit(auditActionUpdate, &model.UnsignedStudent{}, func() error {
+		return d.tx.Model(&model.UnsignedStudent{}).Where("auth_code = ?", authCode).Updates(contextForUpdate).Error
+	}, "auth_code = ?", authCode)
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) ChangeUnsignedStudentAuthCodeExpiry(authCode int64, expiresAt time.Time) (err error) {
+	err = d.writeWithAudit(auditActionUpdate, &model.UnsignedStudent{}, func() error {
+		return d.tx.Model(&model.UnsignedStudent{}).Where("auth_code = ?", authCode).Update("auth_code_expires_at", expiresAt).Error
+	}, "auth_code = ?", authCode)
+	return
+}
+
+func (d *_default) RestoreStudentAuth(uuid string) (err error) {
+	query := "uuid = ? AND deleted_at IS NOT NULL"
+	err = d.writeWithAudit(auditActionRestore, &model.StudentAuth{}, func() error {
+		return d.tx.Unscoped().Model(&model.StudentAuth{}).Where(query, uuid).UpdateColumn("deleted_at", nil).Error
+	}, query, uuid)
+	return
+}
+
+func (d *_default) RestoreTeacherAuth(uuid string) (err error) {
+	query := "uuid = ? AND deleted_at IS NOT NULL"
+	err = d.writeWithAudit(auditActionRestore, &model.TeacherAuth{}, func() error {
+		return d.tx.Unscoped().Model(&model.TeacherAuth{}).Where(query, uuid).UpdateColumn("deleted_at", nil).Error
+	}, query, uuid)
+	return
+}
+
+func (d *_default) RestoreParentAuth(uuid string) (err error) {
+	query := "uuid = ? AND deleted_at IS NOT NULL"
+	err = d.writeWithAudit(auditActionRestore, &model.ParentAuth{}, func() error {
+		return d.tx.Unscoped().Model(&model.ParentAuth{}).Where(query, uuid).UpdateColumn("deleted_at", nil).Error
+	}, query, uuid)
+	return
+}
+
+func (d *_default) RestoreStudentInform(studentUUID string) (err error) {
+	query := "student_uuid = ? AND deleted_at IS NOT NULL"
+	err = d.writeWithAudit(auditActionRestore, &model.StudentInform{}, func() error {
+		return d.tx.Unscoped().Model(&model.StudentInform{}).Where(query, studentUUID).UpdateColumn("deleted_at", nil).Error
+	}, query, studentUUID)
+	return
+}
+
+func (d *_default) RestoreTeacherInform(teacherUUID string) (err error) {
+	query := "teacher_uuid = ? AND deleted_at IS NOT NULL"
+	err = d.writeWithAudit(auditActionRestore, &model.TeacherInform{}, func() error {
+		return d.tx.Unscoped().Model(&model.TeacherInform{}).Where(query, teacherUUID).UpdateColumn("deleted_at", nil).Error
+	}, query, teacherUUID)
+	return
+}
+
+func (d *_default) RestoreParentInform(parentUUID string) (err error) {
+	query := "parent_uuid = ? AND deleted_at IS NOT NULL"
+	err = d.writeWithAudit(auditActionRestore, &model.ParentInform{}, func() error {
+		return d.tx.Unscoped().Model(&model.ParentInform{}).Where(query, parentUUID).UpdateColumn("deleted_at", nil).Error
+	}, query, parentUUID)
+	return
+}
+
+// only links deleted with account (at or after deletedSince) are restored
+func (d *_default) RestoreParentChildrenWithParentUUID(parentUUID string, deletedSince time.Time) (err error) {
+	query := "parent_uuid = ? AND deleted_at >= ?"
+	err = d.writeWithAudit(auditActionRestore, &model.ParentChildren{}, func() error {
+		return d.tx.Unscoped().Model(&model.ParentChildren{}).Where(query, parentUUID, deletedSince).UpdateColumn("deleted_at", nil).Error
+	}, query, parentUUID, deletedSince)
+	return
+}
+
+// only links deleted with account (at or after deletedSince) are restored
+func (d *_default) RestoreParentChildrenWithStudentUUID(studentUUID string, deletedSince time.Time) (err error) {
+	query := "student_uuid = ? AND deleted_at >= ?"
+	err = d.writeWithAudit(auditActionRestore, &model.ParentChildren{}, func() error {
+		return d.tx.Unscoped().Model(&model.ParentChildren{}).Where(query, studentUUID, deletedSince).UpdateColumn("deleted_at", nil).Error
+	}, query, studentUUID, deletedSince)
+	return
+}
+
+// failure count is increased in UPDATE statement instead of writing count read before, so that concurrent failures are all counted
+// (UPDATE reads the latest committed row & waits for lock of it, while SELECT in REPEATABLE READ of MySQL reads snapshot of tx)
+// row is created if it is first failure, and increased again if other tx created it concurrently
+// count is reset to 1 if last failure is before resetBefore, and returned throttle has the count actually stored
+func (d *_default) IncreaseLoginThrottle(throttleKey string, resetBefore time.Time) (throttle *model.LoginThrottle, err error) {
+	increase := func() (int64, error) {
+		// columns are set in order of name in gorm, so updated_at in condition is the one before this update in MySQL too
+		result := d.tx.Model(&model.LoginThrottle{}).Where("throttle_key = ?", throttleKey).Updates(map[string]interface{}{
+			"failure_count": gorm.Expr("CASE WHEN updated_at < ? THEN 1 ELSE failure_count + 1 END", resetBefore),
+		})
+		return result.RowsAffected, result.Error
+	}
+
+	increased, err := increase()
+	if err == nil && increased == 0 {
+		_, err = d.CreateLoginThrottle(&model.LoginThrottle{ThrottleKey: model.ThrottleKey(throttleKey), FailureCount: 1})
+		if dberr.Is(err, dberr.Duplicate) {
+			_, err = increase()
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	throttle = new(model.LoginThrottle)
+	err = d.tx.Where("throttle_key = ?", throttleKey).Find(throttle).Error
+	return
+}
+
+func (d *_default) ModifyLoginThrottleLockedUntil(throttleKey string, lockedUntil *time.Time) (err error) {
+	err = d.tx.Model(&model.LoginThrottle{}).Where("throttle_key = ?", throttleKey).Update("locked_until", lockedUntil).Error
+	return
+}
+
+func (d *_default) ModifySessionLastSeenAt(sessionID string, lastSeenAt time.Time) (err error) {
+	err = d.tx.Model(&model.Session{}).Where("session_id = ?", sessionID).Update("last_seen_at", lastSeenAt).Error
+	return
+}
+
+func (d *_default) ModifyTwoFactorAuth(ownerUUID string, enabledAt *time.Time, lastUsedStep int64) (err error) {
+	contextForUpdate := map[string]interface{}{
+		"enabled_at":     enabledAt,
+		"last_used_step": lastUsedStep,
+	}
+	err = d.tx.Model(&model.TwoFactorAuth{}).Where("owner_uuid = ?", ownerUUID).Updates(contextForUpdate).Error
+	return
+}
+
+// every field except owner uuid is updated, even if it is zero value (ex: attempt count reset to 0)
+func (d *_default) ModifyPasswordReset(ownerUUID string, revision *model.PasswordReset) (err error) {
+	contextForUpdate := map[string]interface{}{
+		revision.CodeHash.KeyName(): revision.CodeHash,
+		"expires_at":                revision.ExpiresAt,
+		"attempt_count":             revision.AttemptCount,
+		"send_count":                revision.SendCount,
+		"last_sent_at":              revision.LastSentAt,
+	}
+	err = d.tx.Model(&model.PasswordReset{}).Where("owner_uuid = ?", ownerUUID).Updates(contextForUpdate).Error
+	return
+}
+
+// attempt count is increased in UPDATE statement only if it is under maxAttemptCount, so that concurrent attempts can't exceed it
+// it returns gorm.ErrRecordNotFound if attempt count already reached maxAttemptCount (or reset not exists)
+func (d *_default) IncreasePasswordResetAttemptCount(ownerUUID string, maxAttemptCount int64) (err error) {
+	result := d.tx.Model(&model.PasswordReset{}).Where("owner_uuid = ? AND attempt_count < ?", ownerUUID, maxAttemptCount).
+		Update("attempt_count", gorm.Expr("attempt_count + 1"))
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	return
+}
+
+// add in v.1.2.0
+func (d *_default) ModifyOutboxMessage(id uint, revisionMessage *model.OutboxMessage) (err error) {
+	contextForUpdate := map[string]interface{}{
+		"status":          revisionMessage.Status,
+		"attempt_count":   revisionMessage.AttemptCount,
+		"next_attempt_at": revisionMessage.NextAttemptAt,
+		"msg_id":          revisionMessage.MsgID,
+		"last_error":      revisionMessage.LastError,
+		"sent_at":         revisionMessage.SentAt,
+		"content":         revisionMessage.Content,
+	}
+	err = d.tx.Model(&model.OutboxMessage{}).Where("id = ?", id).Updates(contextForUpdate).Error
 	return
 }
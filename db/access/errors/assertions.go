@@ -18,4 +18,18 @@ var (
 	ParentInformAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.ParentInform"))
 	UnsignedStudentAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.UnsignedStudent"))
 	ParentChildrenAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.ParentChildren"))
+	RefreshTokenAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.RefreshToken"))
+	RevokedTokenAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.RevokedToken"))
+	SessionRevocationAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.SessionRevocation"))
+	SessionAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.Session"))
+	AuthLogAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.AuthLog"))
+	GraduatedStudentAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.GraduatedStudent"))
+	SchoolTermAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.SchoolTerm"))
+	MessageTemplateAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.MessageTemplate"))
+	OutboxMessageAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.OutboxMessage"))
+	LoginThrottleAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.LoginThrottle"))
+	PasswordResetAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.PasswordReset"))
+	PasswordHistoryAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.PasswordHistory"))
+	TwoFactorAuthAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.TwoFactorAuth"))
+	RecoveryCodeAssertionError = errors.New(fmt.Sprintf(InterfaceAssertionErrorFormat, "*model.RecoveryCode"))
 )
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"auth/model"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type _mock struct {
@@ -91,6 +92,12 @@ func (m _mock) ChangeParentPW(uuid string, parentPW string) error {
 	return m.mock.Called(uuid, parentPW).Error(0)
 }
 
+// add in v.1.2.0
+func (m _mock) ChangeAdminPW(uuid string, adminPW string) error {
+	adminPW = ""
+	return m.mock.Called(uuid, adminPW).Error(0)
+}
+
 func (m _mock) ChangeParentUUID(studentUUID string, parentUUID string) error {
 	return m.mock.Called(studentUUID, parentUUID).Error(0)
 }
@@ -228,6 +235,360 @@ func (m _mock) DeleteUnsignedStudent(authCode int64) error {
 	return m.mock.Called(authCode).Error(0)
 }
 
+func (m _mock) ChangeUnsignedStudentAuthCode(authCode int64, newAuthCode int64, expiresAt time.Time) error {
+	return m.mock.Called(authCode, newAuthCode, expiresAt).Error(0)
+}
+
+func (m _mock) ChangeUnsignedStudentAuthCodeExpiry(authCode int64, expiresAt time.Time) error {
+	return m.mock.Called(authCode, expiresAt).Error(0)
+}
+
+// ---
+
+// 리프레시 토큰 관련 메서드
+func (m _mock) CreateRefreshToken(token *model.RefreshToken) (*model.RefreshToken, error) {
+	args := m.mock.Called(token)
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m _mock) GetRefreshTokenWithHash(tokenHash string) (*model.RefreshToken, error) {
+	args := m.mock.Called(tokenHash)
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m _mock) GetDeletedRefreshTokenWithHash(tokenHash string) (*model.RefreshToken, error) {
+	args := m.mock.Called(tokenHash)
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m _mock) DeleteRefreshToken(tokenHash string) error {
+	return m.mock.Called(tokenHash).Error(0)
+}
+
+func (m _mock) DeleteRefreshTokensWithOwnerUUID(ownerUUID string) error {
+	return m.mock.Called(ownerUUID).Error(0)
+}
+
+// ---
+
+// 토큰 폐기 관련 메서드
+func (m _mock) CreateRevokedToken(token *model.RevokedToken) (*model.RevokedToken, error) {
+	args := m.mock.Called(token)
+	return args.Get(0).(*model.RevokedToken), args.Error(1)
+}
+
+func (m _mock) GetRevokedTokenWithTokenID(tokenID string) (*model.RevokedToken, error) {
+	args := m.mock.Called(tokenID)
+	return args.Get(0).(*model.RevokedToken), args.Error(1)
+}
+
+func (m _mock) CreateSessionRevocation(revocation *model.SessionRevocation) (*model.SessionRevocation, error) {
+	args := m.mock.Called(revocation)
+	return args.Get(0).(*model.SessionRevocation), args.Error(1)
+}
+
+func (m _mock) GetLastSessionRevocationWithOwnerUUID(ownerUUID string) (*model.SessionRevocation, error) {
+	args := m.mock.Called(ownerUUID)
+	return args.Get(0).(*model.SessionRevocation), args.Error(1)
+}
+
+// ---
+
+// 로그인 세션 관련 메서드
+func (m _mock) CreateSession(session *model.Session) (*model.Session, error) {
+	args := m.mock.Called(session)
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m _mock) GetSessionWithSessionID(sessionID string) (*model.Session, error) {
+	args := m.mock.Called(sessionID)
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m _mock) GetSessionsWithOwnerUUID(ownerUUID string) ([]*model.Session, error) {
+	args := m.mock.Called(ownerUUID)
+	return args.Get(0).([]*model.Session), args.Error(1)
+}
+
+func (m _mock) ModifySessionLastSeenAt(sessionID string, lastSeenAt time.Time) error {
+	return m.mock.Called(sessionID, lastSeenAt).Error(0)
+}
+
+func (m _mock) DeleteSession(sessionID string) error {
+	return m.mock.Called(sessionID).Error(0)
+}
+
+func (m _mock) DeleteSessionsWithOwnerUUID(ownerUUID string) error {
+	return m.mock.Called(ownerUUID).Error(0)
+}
+
+func (m _mock) DeleteRefreshTokensWithSessionID(sessionID string) error {
+	return m.mock.Called(sessionID).Error(0)
+}
+
+// ---
+
+// 계정 삭제 및 복구 관련 메서드
+func (m _mock) DeleteParentChildrenWithParentUUID(parentUUID string) error {
+	return m.mock.Called(parentUUID).Error(0)
+}
+
+func (m _mock) DeleteParentChildrenWithStudentUUID(studentUUID string) error {
+	return m.mock.Called(studentUUID).Error(0)
+}
+
+func (m _mock) GetDeletedStudentAuthWithUUID(uuid string) (*model.StudentAuth, error) {
+	args := m.mock.Called(uuid)
+	return args.Get(0).(*model.StudentAuth), args.Error(1)
+}
+
+func (m _mock) GetDeletedTeacherAuthWithUUID(uuid string) (*model.TeacherAuth, error) {
+	args := m.mock.Called(uuid)
+	return args.Get(0).(*model.TeacherAuth), args.Error(1)
+}
+
+func (m _mock) GetDeletedParentAuthWithUUID(uuid string) (*model.ParentAuth, error) {
+	args := m.mock.Called(uuid)
+	return args.Get(0).(*model.ParentAuth), args.Error(1)
+}
+
+func (m _mock) RestoreStudentAuth(uuid string) error {
+	return m.mock.Called(uuid).Error(0)
+}
+
+func (m _mock) RestoreTeacherAuth(uuid string) error {
+	return m.mock.Called(uuid).Error(0)
+}
+
+func (m _mock) RestoreParentAuth(uuid string) error {
+	return m.mock.Called(uuid).Error(0)
+}
+
+func (m _mock) RestoreStudentInform(studentUUID string) error {
+	return m.mock.Called(studentUUID).Error(0)
+}
+
+func (m _mock) RestoreTeacherInform(teacherUUID string) error {
+	return m.mock.Called(teacherUUID).Error(0)
+}
+
+func (m _mock) RestoreParentInform(parentUUID string) error {
+	return m.mock.Called(parentUUID).Error(0)
+}
+
+func (m _mock) RestoreParentChildrenWithParentUUID(parentUUID string, deletedSince time.Time) error {
+	return m.mock.Called(parentUUID, deletedSince).Error(0)
+}
+
+func (m _mock) RestoreParentChildrenWithStudentUUID(studentUUID string, deletedSince time.Time) error {
+	return m.mock.Called(studentUUID, deletedSince).Error(0)
+}
+
+func (m _mock) PurgeDeletedAccounts(deletedBefore time.Time) (int64, error) {
+	args := m.mock.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ---
+
+// 인증 감사 로그 관련 메서드
+func (m _mock) CreateAuthLog(log *model.AuthLog) (*model.AuthLog, error) {
+	args := m.mock.Called(log)
+	return args.Get(0).(*model.AuthLog), args.Error(1)
+}
+
+func (m _mock) GetAuthLogs(criteria *model.AuthLog, since, until time.Time, limit int) ([]*model.AuthLog, error) {
+	args := m.mock.Called(criteria, since, until, limit)
+	return args.Get(0).([]*model.AuthLog), args.Error(1)
+}
+
+// ---
+
+// 학년도 전환 관련 메서드
+func (m _mock) GetAllStudentInformsForUpdate() ([]*model.StudentInform, error) {
+	args := m.mock.Called()
+	return args.Get(0).([]*model.StudentInform), args.Error(1)
+}
+
+func (m _mock) CreateGraduatedStudent(student *model.GraduatedStudent) (*model.GraduatedStudent, error) {
+	args := m.mock.Called(student)
+	return args.Get(0).(*model.GraduatedStudent), args.Error(1)
+}
+
+func (m _mock) ModifyParentChildrenWithStudentUUID(studentUUID string, revision *model.ParentChildren) error {
+	return m.mock.Called(studentUUID, revision).Error(0)
+}
+
+// ---
+
+// 학사 일정 설정 관련 메서드
+func (m _mock) CreateSchoolTerm(term *model.SchoolTerm) (*model.SchoolTerm, error) {
+	args := m.mock.Called(term)
+	return args.Get(0).(*model.SchoolTerm), args.Error(1)
+}
+
+func (m _mock) GetCurrentSchoolTerm() (*model.SchoolTerm, error) {
+	args := m.mock.Called()
+	return args.Get(0).(*model.SchoolTerm), args.Error(1)
+}
+
+// ---
+
+// 문자 메시지 템플릿 관련 메서드
+func (m _mock) CreateMessageTemplate(template *model.MessageTemplate) (*model.MessageTemplate, error) {
+	args := m.mock.Called(template)
+	return args.Get(0).(*model.MessageTemplate), args.Error(1)
+}
+
+func (m _mock) GetLatestMessageTemplateWithName(name string) (*model.MessageTemplate, error) {
+	args := m.mock.Called(name)
+	return args.Get(0).(*model.MessageTemplate), args.Error(1)
+}
+
+func (m _mock) GetMessageTemplateWithNameAndVersion(name string, version int64) (*model.MessageTemplate, error) {
+	args := m.mock.Called(name, version)
+	return args.Get(0).(*model.MessageTemplate), args.Error(1)
+}
+
+// ---
+
+// 문자 발송 대기열 관련 메서드
+func (m _mock) CreateOutboxMessage(message *model.OutboxMessage) (*model.OutboxMessage, error) {
+	args := m.mock.Called(message)
+	return args.Get(0).(*model.OutboxMessage), args.Error(1)
+}
+
+func (m _mock) GetDueOutboxMessagesForUpdate(now time.Time, limit int) ([]*model.OutboxMessage, error) {
+	args := m.mock.Called(now, limit)
+	return args.Get(0).([]*model.OutboxMessage), args.Error(1)
+}
+
+func (m _mock) GetOutboxMessagesWithBatchID(batchID string) ([]*model.OutboxMessage, error) {
+	args := m.mock.Called(batchID)
+	return args.Get(0).([]*model.OutboxMessage), args.Error(1)
+}
+
+func (m _mock) ModifyOutboxMessage(id uint, revisionMessage *model.OutboxMessage) error {
+	args := m.mock.Called(id, revisionMessage)
+	return args.Error(0)
+}
+
+// ---
+
+// 관리 감사 로그 관련 메서드
+// audit context only affects recording in default accessor, so it is not registered as mock call
+func (m _mock) SetAuditContext(actorUUID, rpc string) {}
+
+func (m _mock) GetAuditEvents(criteria *model.AuditEvent, beforeID uint, limit int) ([]*model.AuditEvent, error) {
+	args := m.mock.Called(criteria, beforeID, limit)
+	return args.Get(0).([]*model.AuditEvent), args.Error(1)
+}
+
+// ---
+
+// 로그인 실패 기록 관련 메서드
+func (m _mock) CreateLoginThrottle(throttle *model.LoginThrottle) (*model.LoginThrottle, error) {
+	args := m.mock.Called(throttle)
+	return args.Get(0).(*model.LoginThrottle), args.Error(1)
+}
+
+func (m _mock) GetLoginThrottleWithKey(throttleKey string) (*model.LoginThrottle, error) {
+	args := m.mock.Called(throttleKey)
+	return args.Get(0).(*model.LoginThrottle), args.Error(1)
+}
+
+func (m _mock) IncreaseLoginThrottle(throttleKey string, resetBefore time.Time) (*model.LoginThrottle, error) {
+	args := m.mock.Called(throttleKey, resetBefore)
+	return args.Get(0).(*model.LoginThrottle), args.Error(1)
+}
+
+func (m _mock) ModifyLoginThrottleLockedUntil(throttleKey string, lockedUntil *time.Time) error {
+	return m.mock.Called(throttleKey, lockedUntil).Error(0)
+}
+
+func (m _mock) DeleteLoginThrottle(throttleKey string) error {
+	return m.mock.Called(throttleKey).Error(0)
+}
+
+// ---
+
+// 비밀번호 재설정 요청 관련 메서드
+func (m _mock) CreatePasswordReset(reset *model.PasswordReset) (*model.PasswordReset, error) {
+	args := m.mock.Called(reset)
+	return args.Get(0).(*model.PasswordReset), args.Error(1)
+}
+
+func (m _mock) GetPasswordResetWithOwnerUUID(ownerUUID string) (*model.PasswordReset, error) {
+	args := m.mock.Called(ownerUUID)
+	return args.Get(0).(*model.PasswordReset), args.Error(1)
+}
+
+func (m _mock) ModifyPasswordReset(ownerUUID string, revision *model.PasswordReset) error {
+	return m.mock.Called(ownerUUID, revision).Error(0)
+}
+
+func (m _mock) IncreasePasswordResetAttemptCount(ownerUUID string, maxAttemptCount int64) error {
+	return m.mock.Called(ownerUUID, maxAttemptCount).Error(0)
+}
+
+func (m _mock) DeletePasswordReset(ownerUUID string) error {
+	return m.mock.Called(ownerUUID).Error(0)
+}
+
+// ---
+
+// 비밀번호 변경 이력 관련 메서드
+func (m _mock) CreatePasswordHistory(history *model.PasswordHistory) (*model.PasswordHistory, error) {
+	history.HashedPW = ""
+	args := m.mock.Called(history)
+	return args.Get(0).(*model.PasswordHistory), args.Error(1)
+}
+
+func (m _mock) GetRecentPasswordHistoriesWithOwnerUUID(ownerUUID string, limit int) ([]*model.PasswordHistory, error) {
+	args := m.mock.Called(ownerUUID, limit)
+	return args.Get(0).([]*model.PasswordHistory), args.Error(1)
+}
+
+func (m _mock) DeletePasswordHistoriesExceptRecent(ownerUUID string, keep int) error {
+	return m.mock.Called(ownerUUID, keep).Error(0)
+}
+
+// ---
+
+// 2단계 인증 관련 메서드
+func (m _mock) CreateTwoFactorAuth(auth *model.TwoFactorAuth) (*model.TwoFactorAuth, error) {
+	auth.Secret = ""
+	args := m.mock.Called(auth)
+	return args.Get(0).(*model.TwoFactorAuth), args.Error(1)
+}
+
+func (m _mock) GetTwoFactorAuthWithOwnerUUID(ownerUUID string) (*model.TwoFactorAuth, error) {
+	args := m.mock.Called(ownerUUID)
+	return args.Get(0).(*model.TwoFactorAuth), args.Error(1)
+}
+
+func (m _mock) ModifyTwoFactorAuth(ownerUUID string, enabledAt *time.Time, lastUsedStep int64) error {
+	return m.mock.Called(ownerUUID, enabledAt, lastUsedStep).Error(0)
+}
+
+func (m _mock) DeleteTwoFactorAuth(ownerUUID string) error {
+	return m.mock.Called(ownerUUID).Error(0)
+}
+
+func (m _mock) CreateRecoveryCode(code *model.RecoveryCode) (*model.RecoveryCode, error) {
+	code.CodeHash = ""
+	args := m.mock.Called(code)
+	return args.Get(0).(*model.RecoveryCode), args.Error(1)
+}
+
+func (m _mock) DeleteRecoveryCode(ownerUUID, codeHash string) error {
+	return m.mock.Called(ownerUUID, codeHash).Error(0)
+}
+
+func (m _mock) DeleteRecoveryCodesWithOwnerUUID(ownerUUID string) error {
+	return m.mock.Called(ownerUUID).Error(0)
+}
+
 // ---
 
 // 트랜잭션 관련 메서드
@@ -242,3 +603,8 @@ func (m _mock) Commit() *gorm.DB {
 func (m _mock) Rollback() *gorm.DB {
 	return m.mock.Called().Get(0).(*gorm.DB)
 }
+
+func (m _mock) TryLockUntilTxEnd(lockName string) (bool, error) {
+	args := m.mock.Called(lockName)
+	return args.Bool(0), args.Error(1)
+}
@@ -3,6 +3,7 @@ package access
 import (
 	"auth/model"
 	"github.com/jinzhu/gorm"
+	"time"
 )
 
 type None struct {}
@@ -26,6 +27,7 @@ func (t None) GetParentAuthWithUUID(uuid string) (auth *model.ParentAuth, err er
 func (t None) ChangeStudentPW(uuid string, studentPW string) error { return nil }
 func (t None) ChangeTeacherPW(uuid string, teacherPW string) error { return nil }
 func (t None) ChangeParentPW(uuid string, parentPW string) error { return nil }
+func (t None) ChangeAdminPW(uuid string, adminPW string) error { return nil }
 
 // 계성 삭제 메서드 (Soft Delete)
 func (t None) DeleteStudentAuth(uuid string) error { return nil }
@@ -59,7 +61,102 @@ func (t None) DeleteStudentInform(studentUUID string) error { return nil }
 func (t None) DeleteTeacherInform(teacherUUID string) error { return nil }
 func (t None) DeleteParentInform(parentUUID string) error { return nil }
 
+// 리프레시 토큰 관련 메서드
+func (t None) CreateRefreshToken(token *model.RefreshToken) (result *model.RefreshToken, err error) { return }
+func (t None) GetRefreshTokenWithHash(tokenHash string) (token *model.RefreshToken, err error) { return }
+func (t None) GetDeletedRefreshTokenWithHash(tokenHash string) (token *model.RefreshToken, err error) { return }
+func (t None) DeleteRefreshToken(tokenHash string) error { return nil }
+func (t None) DeleteRefreshTokensWithOwnerUUID(ownerUUID string) error { return nil }
+
+// 토큰 폐기 관련 메서드
+func (t None) CreateRevokedToken(token *model.RevokedToken) (result *model.RevokedToken, err error) { return }
+func (t None) GetRevokedTokenWithTokenID(tokenID string) (token *model.RevokedToken, err error) { return }
+func (t None) CreateSessionRevocation(revocation *model.SessionRevocation) (result *model.SessionRevocation, err error) { return }
+func (t None) GetLastSessionRevocationWithOwnerUUID(ownerUUID string) (revocation *model.SessionRevocation, err error) { return }
+
+// 로그인 세션 관련 메서드
+func (t None) CreateSession(session *model.Session) (result *model.Session, err error) { return }
+func (t None) GetSessionWithSessionID(sessionID string) (session *model.Session, err error) { return }
+func (t None) GetSessionsWithOwnerUUID(ownerUUID string) (sessions []*model.Session, err error) { return }
+func (t None) ModifySessionLastSeenAt(sessionID string, lastSeenAt time.Time) error { return nil }
+func (t None) DeleteSession(sessionID string) error { return nil }
+func (t None) DeleteSessionsWithOwnerUUID(ownerUUID string) error { return nil }
+func (t None) DeleteRefreshTokensWithSessionID(sessionID string) error { return nil }
+
+// 계정 삭제 및 복구 관련 메서드
+func (t None) DeleteParentChildrenWithParentUUID(parentUUID string) error { return nil }
+func (t None) DeleteParentChildrenWithStudentUUID(studentUUID string) error { return nil }
+func (t None) GetDeletedStudentAuthWithUUID(uuid string) (auth *model.StudentAuth, err error) { return }
+func (t None) GetDeletedTeacherAuthWithUUID(uuid string) (auth *model.TeacherAuth, err error) { return }
+func (t None) GetDeletedParentAuthWithUUID(uuid string) (auth *model.ParentAuth, err error) { return }
+func (t None) RestoreStudentAuth(uuid string) error { return nil }
+func (t None) RestoreTeacherAuth(uuid string) error { return nil }
+func (t None) RestoreParentAuth(uuid string) error { return nil }
+func (t None) RestoreStudentInform(studentUUID string) error { return nil }
+func (t None) RestoreTeacherInform(teacherUUID string) error { return nil }
+func (t None) RestoreParentInform(parentUUID string) error { return nil }
+func (t None) RestoreParentChildrenWithParentUUID(parentUUID string, deletedSince time.Time) error { return nil }
+func (t None) RestoreParentChildrenWithStudentUUID(studentUUID string, deletedSince time.Time) error { return nil }
+func (t None) PurgeDeletedAccounts(deletedBefore time.Time) (purgedCount int64, err error) { return }
+
+// 인증 감사 로그 관련 메서드
+func (t None) CreateAuthLog(log *model.AuthLog) (result *model.AuthLog, err error) { return }
+func (t None) GetAuthLogs(criteria *model.AuthLog, since, until time.Time, limit int) (logs []*model.AuthLog, err error) { return }
+
+// 학년도 전환 관련 메서드
+func (t None) GetAllStudentInformsForUpdate() (informs []*model.StudentInform, err error) { return }
+func (t None) CreateGraduatedStudent(student *model.GraduatedStudent) (result *model.GraduatedStudent, err error) { return }
+func (t None) ModifyParentChildrenWithStudentUUID(studentUUID string, revision *model.ParentChildren) error { return nil }
+
+// 학사 일정 설정 관련 메서드
+func (t None) CreateSchoolTerm(term *model.SchoolTerm) (result *model.SchoolTerm, err error) { return }
+func (t None) GetCurrentSchoolTerm() (term *model.SchoolTerm, err error) { return }
+
+// 문자 메시지 템플릿 관련 메서드
+func (t None) CreateMessageTemplate(template *model.MessageTemplate) (result *model.MessageTemplate, err error) { return }
+func (t None) GetLatestMessageTemplateWithName(name string) (template *model.MessageTemplate, err error) { return }
+func (t None) GetMessageTemplateWithNameAndVersion(name string, version int64) (template *model.MessageTemplate, err error) { return }
+
+// 문자 발송 대기열 관련 메서드
+func (t None) CreateOutboxMessage(message *model.OutboxMessage) (result *model.OutboxMessage, err error) { return }
+func (t None) GetDueOutboxMessagesForUpdate(now time.Time, limit int) (messages []*model.OutboxMessage, err error) { return }
+func (t None) GetOutboxMessagesWithBatchID(batchID string) (messages []*model.OutboxMessage, err error) { return }
+func (t None) ModifyOutboxMessage(id uint, revisionMessage *model.OutboxMessage) (err error) { return }
+
+// 관리 감사 로그 관련 메서드
+func (t None) SetAuditContext(actorUUID, rpc string) {}
+func (t None) GetAuditEvents(criteria *model.AuditEvent, beforeID uint, limit int) (events []*model.AuditEvent, err error) { return }
+
+// 로그인 실패 기록 관련 메서드
+func (t None) CreateLoginThrottle(throttle *model.LoginThrottle) (result *model.LoginThrottle, err error) { return }
+func (t None) GetLoginThrottleWithKey(throttleKey string) (throttle *model.LoginThrottle, err error) { return }
+func (t None) IncreaseLoginThrottle(throttleKey string, resetBefore time.Time) (throttle *model.LoginThrottle, err error) { return }
+func (t None) ModifyLoginThrottleLockedUntil(throttleKey string, lockedUntil *time.Time) error { return nil }
+func (t None) DeleteLoginThrottle(throttleKey string) error { return nil }
+
+// 비밀번호 재설정 요청 관련 메서드
+func (t None) CreatePasswordReset(reset *model.PasswordReset) (result *model.PasswordReset, err error) { return }
+func (t None) GetPasswordResetWithOwnerUUID(ownerUUID string) (reset *model.PasswordReset, err error) { return }
+func (t None) ModifyPasswordReset(ownerUUID string, revision *model.PasswordReset) error { return nil }
+func (t None) IncreasePasswordResetAttemptCount(ownerUUID string, maxAttemptCount int64) error { return nil }
+func (t None) DeletePasswordReset(ownerUUID string) error { return nil }
+
+// 비밀번호 변경 이력 관련 메서드
+func (t None) CreatePasswordHistory(history *model.PasswordHistory) (result *model.PasswordHistory, err error) { return }
+func (t None) GetRecentPasswordHistoriesWithOwnerUUID(ownerUUID string, limit int) (histories []*model.PasswordHistory, err error) { return }
+func (t None) DeletePasswordHistoriesExceptRecent(ownerUUID string, keep int) error { return nil }
+
+// 2단계 인증 관련 메서드
+func (t None) CreateTwoFactorAuth(auth *model.TwoFactorAuth) (result *model.TwoFactorAuth, err error) { return }
+func (t None) GetTwoFactorAuthWithOwnerUUID(ownerUUID string) (auth *model.TwoFactorAuth, err error) { return }
+func (t None) ModifyTwoFactorAuth(ownerUUID string, enabledAt *time.Time, lastUsedStep int64) error { return nil }
+func (t None) DeleteTwoFactorAuth(ownerUUID string) error { return nil }
+func (t None) CreateRecoveryCode(code *model.RecoveryCode) (result *model.RecoveryCode, err error) { return }
+func (t None) DeleteRecoveryCode(ownerUUID, codeHash string) error { return nil }
+func (t None) DeleteRecoveryCodesWithOwnerUUID(ownerUUID string) error { return nil }
+
 // 트랜잭션 관련 메서드
 func (t None) BeginTx() {}
 func (t None) Commit() *gorm.DB { return nil }
-func (t None) Rollback() *gorm.DB { return nil }
\ No newline at end of file
+func (t None) Rollback() *gorm.DB { return nil }
+func (t None) TryLockUntilTxEnd(lockName string) (acquired bool, err error) { return }
\ No newline at end of file
@@ -3,6 +3,7 @@ package db
 import (
 	"auth/model"
 	"github.com/jinzhu/gorm"
+	"time"
 )
 
 type Accessor interface {
@@ -27,6 +28,7 @@ type Accessor interface {
 	ChangeStudentPW(uuid string, studentPW string) error
 	ChangeTeacherPW(uuid string, teacherPW string) error
 	ChangeParentPW(uuid string, parentPW string) error
+	ChangeAdminPW(uuid string, adminPW string) error // add in v.1.2.0
 	ChangeParentUUID(studentUUID string, parentUUID string) error
 
 	// 계성 삭제 메서드 (Soft Delete)
@@ -72,6 +74,130 @@ type Accessor interface {
 	GetParentChildWithInform(grade, group, number int64, name string) (*model.ParentChildren, error)
 	ModifyParentChildren(child *model.ParentChildren, revision *model.ParentChildren) error
 	DeleteUnsignedStudent(authCode int64) error
+	ChangeUnsignedStudentAuthCode(authCode int64, newAuthCode int64, expiresAt time.Time) error // add in v.1.2.0
+	ChangeUnsignedStudentAuthCodeExpiry(authCode int64, expiresAt time.Time) error             // add in v.1.2.0
+
+	// ---
+
+	// 계정 삭제 및 복구 관련 메서드 (add in v.1.2.0)
+	DeleteParentChildrenWithParentUUID(parentUUID string) error
+	DeleteParentChildrenWithStudentUUID(studentUUID string) error
+	GetDeletedStudentAuthWithUUID(uuid string) (*model.StudentAuth, error)
+	GetDeletedTeacherAuthWithUUID(uuid string) (*model.TeacherAuth, error)
+	GetDeletedParentAuthWithUUID(uuid string) (*model.ParentAuth, error)
+	RestoreStudentAuth(uuid string) error
+	RestoreTeacherAuth(uuid string) error
+	RestoreParentAuth(uuid string) error
+	RestoreStudentInform(studentUUID string) error
+	RestoreTeacherInform(teacherUUID string) error
+	RestoreParentInform(parentUUID string) error
+	RestoreParentChildrenWithParentUUID(parentUUID string, deletedSince time.Time) error
+	RestoreParentChildrenWithStudentUUID(studentUUID string, deletedSince time.Time) error
+	PurgeDeletedAccounts(deletedBefore time.Time) (purgedCount int64, err error) // 보관 기간이 지난 삭제 계정 영구 삭제
+
+	// ---
+
+	// 리프레시 토큰 관련 메서드 (add in v.1.2.0)
+	CreateRefreshToken(token *model.RefreshToken) (result *model.RefreshToken, err error)
+	GetRefreshTokenWithHash(tokenHash string) (*model.RefreshToken, error)
+	GetDeletedRefreshTokenWithHash(tokenHash string) (*model.RefreshToken, error) // 이미 사용(폐기)된 토큰 조회, 재사용 감지에 사용
+	DeleteRefreshToken(tokenHash string) error                                     // 삭제된 토큰이 없으면 gorm.ErrRecordNotFound 반환
+	DeleteRefreshTokensWithOwnerUUID(ownerUUID string) error
+
+	// ---
+
+	// 토큰 폐기 관련 메서드 (add in v.1.2.0)
+	CreateRevokedToken(token *model.RevokedToken) (result *model.RevokedToken, err error)
+	GetRevokedTokenWithTokenID(tokenID string) (*model.RevokedToken, error)
+	CreateSessionRevocation(revocation *model.SessionRevocation) (result *model.SessionRevocation, err error)
+	GetLastSessionRevocationWithOwnerUUID(ownerUUID string) (*model.SessionRevocation, error)
+
+	// ---
+
+	// 로그인 세션 관련 메서드 (add in v.1.2.0)
+	CreateSession(session *model.Session) (result *model.Session, err error)
+	GetSessionWithSessionID(sessionID string) (*model.Session, error)
+	GetSessionsWithOwnerUUID(ownerUUID string) ([]*model.Session, error)
+	ModifySessionLastSeenAt(sessionID string, lastSeenAt time.Time) error
+	DeleteSession(sessionID string) error
+	DeleteSessionsWithOwnerUUID(ownerUUID string) error
+	DeleteRefreshTokensWithSessionID(sessionID string) error
+
+	// ---
+
+	// 인증 감사 로그 관련 메서드 (add in v.1.2.0)
+	CreateAuthLog(log *model.AuthLog) (result *model.AuthLog, err error)
+	GetAuthLogs(criteria *model.AuthLog, since, until time.Time, limit int) ([]*model.AuthLog, error)
+
+	// ---
+
+	// 학년도 전환 관련 메서드 (add in v.1.2.0)
+	GetAllStudentInformsForUpdate() ([]*model.StudentInform, error)
+	CreateGraduatedStudent(student *model.GraduatedStudent) (result *model.GraduatedStudent, err error)
+	ModifyParentChildrenWithStudentUUID(studentUUID string, revision *model.ParentChildren) error // 진급한 학생과 연결된 자녀 정보의 학년, 반, 번호 변경
+
+	// ---
+
+	// 학사 일정 설정 관련 메서드 (add in v.1.2.0)
+	CreateSchoolTerm(term *model.SchoolTerm) (result *model.SchoolTerm, err error) // 새로 생성된 설정이 현재 설정이 됨
+	GetCurrentSchoolTerm() (*model.SchoolTerm, error)
+
+	// ---
+
+	// 문자 메시지 템플릿 관련 메서드 (add in v.1.2.0)
+	CreateMessageTemplate(template *model.MessageTemplate) (result *model.MessageTemplate, err error)
+	GetLatestMessageTemplateWithName(name string) (*model.MessageTemplate, error)
+	GetMessageTemplateWithNameAndVersion(name string, version int64) (*model.MessageTemplate, error)
+
+	// ---
+
+	// 문자 발송 대기열 관련 메서드 (add in v.1.2.0)
+	CreateOutboxMessage(message *model.OutboxMessage) (result *model.OutboxMessage, err error)
+	GetDueOutboxMessagesForUpdate(now time.Time, limit int) ([]*model.OutboxMessage, error) // 발송 대기 중이고 NextAttemptAt 이 지난 메시지 조회
+	GetOutboxMessagesWithBatchID(batchID string) ([]*model.OutboxMessage, error)
+	ModifyOutboxMessage(id uint, revisionMessage *model.OutboxMessage) error
+
+	// ---
+
+	// 관리 감사 로그 관련 메서드 (add in v.1.2.0)
+	SetAuditContext(actorUUID, rpc string) // 이후 tx 에서 일어나는 업무 데이터 변경을 요청한 계정과 RPC 설정
+	GetAuditEvents(criteria *model.AuditEvent, beforeID uint, limit int) ([]*model.AuditEvent, error)
+
+	// ---
+
+	// 로그인 실패 기록 관련 메서드 (add in v.1.2.0)
+	CreateLoginThrottle(throttle *model.LoginThrottle) (result *model.LoginThrottle, err error)
+	GetLoginThrottleWithKey(throttleKey string) (*model.LoginThrottle, error)
+	IncreaseLoginThrottle(throttleKey string, resetBefore time.Time) (*model.LoginThrottle, error) // 실패 횟수를 원자적으로 1 증가 (없으면 생성)
+	ModifyLoginThrottleLockedUntil(throttleKey string, lockedUntil *time.Time) error
+	DeleteLoginThrottle(throttleKey string) error
+
+	// ---
+
+	// 비밀번호 재설정 요청 관련 메서드 (add in v.1.2.0)
+	CreatePasswordReset(reset *model.PasswordReset) (result *model.PasswordReset, err error)
+	GetPasswordResetWithOwnerUUID(ownerUUID string) (*model.PasswordReset, error)
+	ModifyPasswordReset(ownerUUID string, revision *model.PasswordReset) error
+	IncreasePasswordResetAttemptCount(ownerUUID string, maxAttemptCount int64) error // 최대 횟수에 도달했다면 gorm.ErrRecordNotFound 반환
+	DeletePasswordReset(ownerUUID string) error
+
+	// ---
+
+	// 비밀번호 변경 이력 관련 메서드 (add in v.1.2.0)
+	CreatePasswordHistory(history *model.PasswordHistory) (result *model.PasswordHistory, err error)
+	GetRecentPasswordHistoriesWithOwnerUUID(ownerUUID string, limit int) ([]*model.PasswordHistory, error)
+	DeletePasswordHistoriesExceptRecent(ownerUUID string, keep int) error
+
+	// ---
+
+	// 2단계 인증 관련 메서드 (add in v.1.2.0)
+	CreateTwoFactorAuth(auth *model.TwoFactorAuth) (result *model.TwoFactorAuth, err error)
+	GetTwoFactorAuthWithOwnerUUID(ownerUUID string) (*model.TwoFactorAuth, error)
+	ModifyTwoFactorAuth(ownerUUID string, enabledAt *time.Time, lastUsedStep int64) error
+	DeleteTwoFactorAuth(ownerUUID string) error
+	CreateRecoveryCode(code *model.RecoveryCode) (result *model.RecoveryCode, err error)
+	DeleteRecoveryCode(ownerUUID, codeHash string) error
+	DeleteRecoveryCodesWithOwnerUUID(ownerUUID string) error
 
 	// ---
 
@@ -79,4 +205,5 @@ type Accessor interface {
 	BeginTx()
 	Commit() *gorm.DB
 	Rollback() *gorm.DB
+	TryLockUntilTxEnd(lockName string) (acquired bool, err error) // 다른 tx 에서 잡지 않은 이름 잠금을 tx 종료 시까지 획득 (add in v.1.2.0)
 }
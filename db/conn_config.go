@@ -0,0 +1,157 @@
+// add file in v.1.2.0
+// this file declare options of ConnConfig read from db/auth KV of consul (pool limits, timeouts, charset, timezone, TLS)
+// and method that fill default value in omitted option & validate conf before connecting to DB
+
+package db
+
+import (
+	"auth/model/validate"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// duration written as string parsed in time.ParseDuration in KV value (ex: "30s", "1h")
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.New(fmt.Sprintf("duration must be string like \"30s\", value: %s", string(b)))
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+type TLSConfig struct {
+	Mode       string `json:"mode" validate:"oneof=disable require verify-full"` // default of driver if empty
+	CACert     string `json:"ca_cert" validate:"omitempty,file"`                 // system root CA is used if empty
+	ClientCert string `json:"client_cert" validate:"required_with=ClientKey,omitempty,file"`
+	ClientKey  string `json:"client_key" validate:"required_with=ClientCert,omitempty,file"`
+}
+
+const (
+	tlsModeDisable    = "disable"
+	tlsModeRequire    = "require"     // connection is encrypted, but server certificate is not verified
+	tlsModeVerifyFull = "verify-full" // server certificate & host name are verified with CA
+)
+
+// name of TLS config registered in mysql driver, referred in DSN
+const mysqlTLSConfigName = "auth"
+
+// function that return copy of conf with normalized dialect & default values, or error if conf is invalid
+func (conf ConnConfig) validated() (ConnConfig, error) {
+	conf.Dialect = strings.ToLower(conf.Dialect)
+	switch conf.Dialect {
+	case "sqlite":
+		conf.Dialect = sqliteDialect
+	case "postgresql":
+		conf.Dialect = postgresDialect
+	}
+
+	if conf.Charset == "" { conf.Charset = "utf8mb4" }
+	if conf.Timezone == "" { conf.Timezone = time.Local.String() }
+
+	// default of TLS mode is kept as default of each driver (not used in mysql, required in lib/pq)
+	if conf.TLS.Mode == "" && conf.Dialect == postgresDialect {
+		conf.TLS.Mode = tlsModeRequire
+	} else if conf.TLS.Mode == "" {
+		conf.TLS.Mode = tlsModeDisable
+	}
+
+	var exceptFields []string
+	if conf.Dialect == sqliteDialect {
+		exceptFields = embeddedDBExceptFields
+	}
+	if err := validate.DBValidator.StructExcept(&conf, exceptFields...); err != nil {
+		return conf, errors.New(fmt.Sprintf("invalid db/auth KV value, err: %v", err.Error()))
+	}
+	if conf.MaxOpenConns > 0 && conf.MaxIdleConns > conf.MaxOpenConns {
+		return conf, errors.New(fmt.Sprintf("invalid db/auth KV value, err: max_idle_conns(%d) is greater than max_open_conns(%d)", conf.MaxIdleConns, conf.MaxOpenConns))
+	}
+	// migration lock is held in its own connection while migrations run in other connection of pool (see withMigrationLock)
+	if conf.MaxOpenConns == 1 && conf.Dialect != sqliteDialect {
+		return conf, errors.New("invalid db/auth KV value, err: max_open_conns must be greater than 1, because migration lock holds a connection while migrating")
+	}
+	return conf, nil
+}
+
+// host may be written with port in KV value of previous version (ex: localhost:3306), so it is used as it is in that case
+func (conf ConnConfig) address() string {
+	if _, _, err := net.SplitHostPort(conf.Host); err == nil {
+		return conf.Host
+	}
+	return net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port))
+}
+
+// String return summary of conf reported in start of service, password is not included
+func (conf ConnConfig) String() string {
+	if conf.Dialect == sqliteDialect {
+		return fmt.Sprintf("dialect: %s, db: %s", conf.Dialect, conf.DB)
+	}
+	return fmt.Sprintf("dialect: %s, address: %s, db: %s, user: %s, max open conns: %d, max idle conns: %d, "+
+		"conn max lifetime: %s, connect timeout: %s, read timeout: %s, write timeout: %s, charset: %s, timezone: %s, tls: %s",
+		conf.Dialect, conf.address(), conf.DB, conf.User, conf.MaxOpenConns, conf.MaxIdleConns, conf.ConnMaxLifetime,
+		conf.ConnectTimeout, conf.ReadTimeout, conf.WriteTimeout, conf.Charset, conf.Timezone, conf.TLS.Mode)
+}
+
+// function that set limits of connection pool in conf, zero value is left as default of database/sql
+func setConnPool(db *gorm.DB, conf ConnConfig) {
+	if conf.MaxOpenConns > 0 { db.DB().SetMaxOpenConns(conf.MaxOpenConns) }
+	if conf.MaxIdleConns > 0 { db.DB().SetMaxIdleConns(conf.MaxIdleConns) }
+	if conf.ConnMaxLifetime > 0 { db.DB().SetConnMaxLifetime(time.Duration(conf.ConnMaxLifetime)) }
+}
+
+// function that return value of tls param in mysql DSN, TLS config is registered in driver if certificate is verified
+func registerMysqlTLSConfig(conf ConnConfig) (string, error) {
+	switch conf.TLS.Mode {
+	case tlsModeDisable:
+		return "false", nil
+	case tlsModeRequire:
+		return "skip-verify", nil
+	}
+
+	host, _, err := net.SplitHostPort(conf.address())
+	if err != nil {
+		return "", err
+	}
+	tlsConf := &tls.Config{ServerName: host}
+
+	if conf.TLS.CACert != "" {
+		pem, err := ioutil.ReadFile(conf.TLS.CACert)
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("unable to read CA certificate, err: %v", err.Error()))
+		}
+		tlsConf.RootCAs = x509.NewCertPool()
+		if !tlsConf.RootCAs.AppendCertsFromPEM(pem) {
+			return "", errors.New(fmt.Sprintf("no certificate is parsed from %s", conf.TLS.CACert))
+		}
+	}
+	if conf.TLS.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(conf.TLS.ClientCert, conf.TLS.ClientKey)
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("unable to load client certificate, err: %v", err.Error()))
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	if err = mysql.RegisterTLSConfig(mysqlTLSConfigName, tlsConf); err != nil {
+		return "", err
+	}
+	return mysqlTLSConfigName, nil
+}
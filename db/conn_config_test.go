@@ -0,0 +1,172 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_ConnConfig_validated(t *testing.T) {
+	tests := []struct {
+		Description  string
+		Conf         ConnConfig
+		ExpectError  bool
+		ExpectedConf ConnConfig
+	}{
+		{
+			Description:  "default values in MySQL",
+			Conf:         ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth"},
+			ExpectedConf: ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth",
+				Charset: "utf8mb4", Timezone: time.Local.String(), TLS: TLSConfig{Mode: tlsModeDisable}},
+		}, {
+			Description:  "TLS is required in PostgreSQL by default, and dialect alias is normalized",
+			Conf:         ConnConfig{Dialect: "PostgreSQL", Host: "localhost", Port: 5432, User: "postgres", DB: "auth"},
+			ExpectedConf: ConnConfig{Dialect: postgresDialect, Host: "localhost", Port: 5432, User: "postgres", DB: "auth",
+				Charset: "utf8mb4", Timezone: time.Local.String(), TLS: TLSConfig{Mode: tlsModeRequire}},
+		}, {
+			Description:  "values written in conf are kept",
+			Conf:         ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth",
+				Charset: "utf8", Timezone: "Asia/Seoul", TLS: TLSConfig{Mode: tlsModeRequire}},
+			ExpectedConf: ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth",
+				Charset: "utf8", Timezone: "Asia/Seoul", TLS: TLSConfig{Mode: tlsModeRequire}},
+		}, {
+			Description:  "host, port & user are not required in SQLite",
+			Conf:         ConnConfig{Dialect: "sqlite", DB: ":memory:"},
+			ExpectedConf: ConnConfig{Dialect: sqliteDialect, DB: ":memory:",
+				Charset: "utf8mb4", Timezone: time.Local.String(), TLS: TLSConfig{Mode: tlsModeDisable}},
+		}, {
+			Description: "host, port & user are required in MySQL",
+			Conf:        ConnConfig{Dialect: "mysql", DB: "auth"},
+			ExpectError: true,
+		}, {
+			Description: "max idle conns greater than max open conns",
+			Conf:        ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth", MaxOpenConns: 5, MaxIdleConns: 10},
+			ExpectError: true,
+		}, {
+			Description: "only one open conn in MySQL, which is held by migration lock",
+			Conf:        ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth", MaxOpenConns: 1},
+			ExpectError: true,
+		}, {
+			Description: "only one open conn in PostgreSQL, which is held by migration lock",
+			Conf:        ConnConfig{Dialect: "postgres", Host: "localhost", Port: 5432, User: "postgres", DB: "auth", MaxOpenConns: 1},
+			ExpectError: true,
+		}, {
+			Description:  "only one open conn in SQLite, which doesn't use migration lock",
+			Conf:         ConnConfig{Dialect: "sqlite", DB: ":memory:", MaxOpenConns: 1},
+			ExpectedConf: ConnConfig{Dialect: sqliteDialect, DB: ":memory:", MaxOpenConns: 1,
+				Charset: "utf8mb4", Timezone: time.Local.String(), TLS: TLSConfig{Mode: tlsModeDisable}},
+		}, {
+			Description:  "max idle conns without limit of open conns",
+			Conf:         ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth", MaxIdleConns: 10},
+			ExpectedConf: ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth", MaxIdleConns: 10,
+				Charset: "utf8mb4", Timezone: time.Local.String(), TLS: TLSConfig{Mode: tlsModeDisable}},
+		}, {
+			Description: "unknown timezone",
+			Conf:        ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth", Timezone: "Mars/Olympus"},
+			ExpectError: true,
+		}, {
+			Description: "unknown TLS mode",
+			Conf:        ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, User: "root", DB: "auth", TLS: TLSConfig{Mode: "prefer"}},
+			ExpectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		conf, err := test.Conf.validated()
+		assert.Equalf(t, test.ExpectError, err != nil, "error assertion error (test case: %s, err: %v)", test.Description, err)
+		if !test.ExpectError {
+			assert.Equalf(t, test.ExpectedConf, conf, "conf assertion error (test case: %s)", test.Description)
+		}
+	}
+}
+
+func Test_registerMysqlTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth-tls-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	caCert := filepath.Join(dir, "ca.pem")
+	if err = ioutil.WriteFile(caCert, generateCertPEMForTest(t), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalidCACert := filepath.Join(dir, "invalid.pem")
+	if err = ioutil.WriteFile(invalidCACert, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Description    string
+		TLS            TLSConfig
+		ExpectedParam  string
+		ExpectError    bool
+	}{
+		{
+			Description:   "TLS is not used",
+			TLS:           TLSConfig{Mode: tlsModeDisable},
+			ExpectedParam: "false",
+		}, {
+			Description:   "server certificate is not verified",
+			TLS:           TLSConfig{Mode: tlsModeRequire},
+			ExpectedParam: "skip-verify",
+		}, {
+			Description:   "server certificate is verified with CA",
+			TLS:           TLSConfig{Mode: tlsModeVerifyFull, CACert: caCert},
+			ExpectedParam: mysqlTLSConfigName,
+		}, {
+			Description: "CA certificate doesn't exist",
+			TLS:         TLSConfig{Mode: tlsModeVerifyFull, CACert: filepath.Join(dir, "none.pem")},
+			ExpectError: true,
+		}, {
+			Description: "no certificate in CA file",
+			TLS:         TLSConfig{Mode: tlsModeVerifyFull, CACert: invalidCACert},
+			ExpectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		mysql.DeregisterTLSConfig(mysqlTLSConfigName)
+		conf := ConnConfig{Dialect: "mysql", Host: "localhost", Port: 3306, TLS: test.TLS}
+		param, err := registerMysqlTLSConfig(conf)
+		assert.Equalf(t, test.ExpectError, err != nil, "error assertion error (test case: %s, err: %v)", test.Description, err)
+		assert.Equalf(t, test.ExpectedParam, param, "tls param assertion error (test case: %s)", test.Description)
+
+		// DSN referring TLS config is parsed only if config is registered in driver
+		_, err = mysql.ParseDSN("root@tcp(localhost:3306)/auth?tls=" + mysqlTLSConfigName)
+		assert.Equalf(t, test.ExpectedParam == mysqlTLSConfigName, err == nil, "tls config registration assertion error (test case: %s)", test.Description)
+	}
+	mysql.DeregisterTLSConfig(mysqlTLSConfigName)
+}
+
+// function that return self-signed certificate in PEM format, used as CA certificate in test
+func generateCertPEMForTest(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "auth test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/go-playground/validator/v10"
+	"github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/consul/api"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"math"
+	"net/url"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 )
 
 type ConnConfig struct {
@@ -17,30 +22,73 @@ type ConnConfig struct {
 	Host    string `json:"host" validate:"required"`
 	Port 	int	   `json:"port" validate:"required"`
 	User    string `json:"user" validate:"required"`
-	DB		string `json:"db" validate:"required"`
+	DB		string `json:"db" validate:"required"` // file path or :memory: in sqlite3
+
+	// limits of connection pool, zero value means default of database/sql (not used in sqlite3) (add in v.1.2.0)
+	MaxOpenConns    int      `json:"max_open_conns" validate:"min=0"`
+	MaxIdleConns    int      `json:"max_idle_conns" validate:"min=0"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime" validate:"min=0"`
+
+	// timeouts of connecting, reading & writing, zero value means no timeout (read & write timeouts are used in mysql only) (add in v.1.2.0)
+	ConnectTimeout Duration `json:"connect_timeout" validate:"min=0"`
+	ReadTimeout    Duration `json:"read_timeout" validate:"min=0"`
+	WriteTimeout   Duration `json:"write_timeout" validate:"min=0"`
+
+	Charset  string    `json:"charset" validate:"oneof=utf8 utf8mb4"` // used in mysql only, utf8mb4 if empty (add in v.1.2.0)
+	Timezone string    `json:"timezone" validate:"timezone"`          // name of location parsed in time.LoadLocation, Local if empty (add in v.1.2.0)
+	TLS      TLSConfig `json:"tls"`                                   // add in v.1.2.0
 }
 
+// dialect names of SQLite & PostgreSQL registered in gorm (add in v.1.2.0)
+const (
+	sqliteDialect   = "sqlite3"
+	postgresDialect = "postgres"
+)
+
+// fields of ConnConfig not used in embedded DB (add in v.1.2.0)
+var embeddedDBExceptFields = []string{"Host", "Port", "User"}
+
 func ConnectWithConsul(cli *api.Client, key string) (db *gorm.DB, conf ConnConfig, err error) {
 	kv, _, err := cli.KV().Get(key, nil)
 	if err != nil {
 		err = errors.New(fmt.Sprintf("unable to get db/auth KV from consul, err: %v", err.Error()))
 		return
 	}
+	if kv == nil {
+		err = errors.New(fmt.Sprintf("%s KV doesn't exist in consul", key))
+		return
+	}
 
 	if err = json.Unmarshal(kv.Value, &conf); err != nil {
 		err = errors.New(fmt.Sprintf("error occurs while unmarshal KV value into struct, err: %v", err.Error()))
 		return
 	}
 
-	if err = validator.New().Struct(&conf); err != nil {
-		err = errors.New(fmt.Sprintf("invalid db/auth KV value, err: %v", err.Error()))
+	// conf with default values is returned, so that it can be reported in start of service (add in v.1.2.0)
+	if conf, err = conf.validated(); err != nil {
 		return
 	}
+	db, err = connect(conf)
+	return
+}
 
-	conf.Dialect = strings.ToLower(conf.Dialect)
+// function that connect to DB with conf, used directly in test without consul (add in v.1.2.0)
+func Connect(conf ConnConfig) (db *gorm.DB, err error) {
+	if conf, err = conf.validated(); err != nil {
+		return
+	}
+	return connect(conf)
+}
+
+// function that connect to DB with conf returned from validated method
+func connect(conf ConnConfig) (db *gorm.DB, err error) {
 	switch conf.Dialect {
 	case "mysql":
 		db, err = connectToMysql(conf)
+	case postgresDialect:
+		db, err = connectToPostgres(conf)
+	case sqliteDialect:
+		db, err = connectToSQLite(conf)
 	default:
 		err = errors.New(fmt.Sprintf("%s is not supported db in this service.", conf.Dialect))
 	}
@@ -53,7 +101,81 @@ func connectToMysql(conf ConnConfig) (db *gorm.DB, err error) {
 		err = errors.New("please set DB_PASSWORD environment variable")
 		return
 	}
-	args := fmt.Sprintf("%s:%s@(%s)/%s?charset=utf8&parseTime=True&loc=Local", conf.User, pwd, conf.Host, conf.DB)
-	db, err = gorm.Open(conf.Dialect, args)
+
+	// DSN is built with config of driver instead of fixed format, so that options in conf are escaped (add in v.1.2.0)
+	args := mysql.NewConfig()
+	args.User, args.Passwd = conf.User, pwd
+	args.Net, args.Addr = "tcp", conf.address()
+	args.DBName = conf.DB
+	args.Params = map[string]string{"charset": conf.Charset}
+	args.ParseTime = true
+	args.Timeout = time.Duration(conf.ConnectTimeout)
+	args.ReadTimeout = time.Duration(conf.ReadTimeout)
+	args.WriteTimeout = time.Duration(conf.WriteTimeout)
+	if args.Loc, err = time.LoadLocation(conf.Timezone); err != nil {
+		return
+	}
+	if args.TLSConfig, err = registerMysqlTLSConfig(conf); err != nil {
+		return
+	}
+
+	if db, err = gorm.Open(conf.Dialect, args.FormatDSN()); err != nil {
+		return
+	}
+
+	setConnPool(db, conf)
+	return
+}
+
+// failed statement aborts tx in PostgreSQL, so statements are written in savepoint (add in v.1.2.0)
+func connectToPostgres(conf ConnConfig) (db *gorm.DB, err error) {
+	pwd := os.Getenv("DB_PASSWORD")
+	if pwd == "" {
+		err = errors.New("please set DB_PASSWORD environment variable")
+		return
+	}
+
+	params := url.Values{}
+	params.Set("sslmode", conf.TLS.Mode)
+	if conf.TLS.CACert != "" { params.Set("sslrootcert", conf.TLS.CACert) }
+	if conf.TLS.ClientCert != "" { params.Set("sslcert", conf.TLS.ClientCert) }
+	if conf.TLS.ClientKey != "" { params.Set("sslkey", conf.TLS.ClientKey) }
+	if conf.ConnectTimeout > 0 {
+		// connect_timeout of lib/pq is in seconds, so timeout less than a second is rounded up
+		params.Set("connect_timeout", strconv.Itoa(int(math.Ceil(time.Duration(conf.ConnectTimeout).Seconds()))))
+	}
+	if conf.Timezone != time.Local.String() {
+		// parameter not known by lib/pq is sent as run-time parameter of session, Local is left as timezone of server
+		params.Set("timezone", conf.Timezone)
+	}
+	args := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(conf.User, pwd),
+		Host:     conf.address(),
+		Path:     conf.DB,
+		RawQuery: params.Encode(),
+	}
+	if db, err = gorm.Open(conf.Dialect, args.String()); err != nil {
+		return
+	}
+
+	setConnPool(db, conf)
+	registerPostgresSavepoint(db)
 	return
-}
\ No newline at end of file
+}
+
+// in-memory DB is shared by connections in pool, and FK constraint is enforced as in MySQL (add in v.1.2.0)
+func connectToSQLite(conf ConnConfig) (db *gorm.DB, err error) {
+	args := fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000", conf.DB)
+	if conf.DB == ":memory:" {
+		args = "file::memory:?cache=shared&_foreign_keys=1"
+	}
+	if db, err = gorm.Open(conf.Dialect, args); err != nil {
+		return
+	}
+
+	// SQLite allows only one writer, so connection is not opened more than one to wait for lock instead of failing
+	db.DB().SetMaxOpenConns(1)
+	registerSQLiteErrorTranslator(db)
+	return
+}
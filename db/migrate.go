@@ -1,56 +1,213 @@
 package db
 
 import (
-	"auth/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/jinzhu/gorm"
+	"sort"
+	"time"
 )
 
-func Migrate(db *gorm.DB) {
+// 스키마 마이그레이션 (add in v.1.2.0)
+type migration struct {
+	version uint
+	name    string
+	up      func(db *gorm.DB) error
+	down    func(db *gorm.DB) error
+}
+
+// 적용된 마이그레이션 기록 테이블
+type schemaMigration struct {
+	Version   uint      `gorm:"primary_key;auto_increment:false"`
+	Name      string    `gorm:"Type:varchar(100);NOT NULL"`
+	AppliedAt time.Time `gorm:"NOT NULL"`
+}
+
+func (sm *schemaMigration) TableName() string { return "schema_migrations" }
+
+// status of migration returned from MigrationStatuses, AppliedAt is nil if migration is not applied yet
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+}
+
+// timeout of waiting for lock held by other replica running migration
+const migrationLockTimeout = time.Minute
+
+// function that apply all pending migrations, called in start of service
+func Migrate(db *gorm.DB) error {
 	db.LogMode(false)
+	return MigrateUp(db, 0)
+}
 
-	//db.DropTableIfExists(&model.AdminAuth{})
-	//db.DropTableIfExists(&model.StudentInform{})
-	//db.DropTableIfExists(&model.ParentInform{})
-	//db.DropTableIfExists(&model.TeacherInform{})
-	//db.DropTableIfExists(&model.StudentAuth{})
-	//db.DropTableIfExists(&model.ParentAuth{})
-	//db.DropTableIfExists(&model.TeacherAuth{})
+// function that apply pending migrations in order of version, all pending migrations are applied if steps <= 0
+func MigrateUp(db *gorm.DB, steps int) error {
+	return withMigrationLock(db, func(applied map[uint]schemaMigration) error {
+		for _, m := range sortedMigrations() {
+			if _, ok := applied[m.version]; ok {
+				continue
+			}
+			if err := m.up(db); err != nil {
+				return fmt.Errorf("unable to apply migration %d(%s), err: %v", m.version, m.name, err)
+			}
+			record := schemaMigration{Version: m.version, Name: m.name, AppliedAt: time.Now()}
+			if err := db.Create(&record).Error; err != nil {
+				return fmt.Errorf("unable to record migration %d(%s), err: %v", m.version, m.name, err)
+			}
+			if steps--; steps == 0 { // steps <= 0 never reach 0, so all pending migrations are applied
+				break
+			}
+		}
+		return nil
+	})
+}
 
-	if !db.HasTable(&model.AdminAuth{}) {
-		db.CreateTable(&model.AdminAuth{})
+// function that roll back applied migrations in reverse order of version as many as steps
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps <= 0 {
+		return errors.New("steps to roll back must be positive")
 	}
-	if !db.HasTable(&model.StudentAuth{}) {
-		db.CreateTable(&model.StudentAuth{})
+	return withMigrationLock(db, func(applied map[uint]schemaMigration) error {
+		sorted := sortedMigrations()
+		for i := len(sorted) - 1; i >= 0 && steps > 0; i-- {
+			m := sorted[i]
+			if _, ok := applied[m.version]; !ok {
+				continue
+			}
+			if err := m.down(db); err != nil {
+				return fmt.Errorf("unable to roll back migration %d(%s), err: %v", m.version, m.name, err)
+			}
+			if err := db.Delete(&schemaMigration{Version: m.version}).Error; err != nil {
+				return fmt.Errorf("unable to delete record of migration %d(%s), err: %v", m.version, m.name, err)
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// function that return status of all migrations in order of version
+func MigrationStatuses(db *gorm.DB) (statuses []MigrationStatus, err error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return
 	}
-	if !db.HasTable(&model.StudentInform{}) {
-		db.CreateTable(&model.StudentInform{})
+
+	for _, m := range sortedMigrations() {
+		status := MigrationStatus{Version: m.version, Name: m.name}
+		if record, ok := applied[m.version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
 	}
-	if !db.HasTable(&model.ParentAuth{}) {
-		db.CreateTable(&model.ParentAuth{})
+	return
+}
+
+// function that call migrate while holding named lock of DB, so that replicas started at the same time don't run migration concurrently
+// applied migrations passed to migrate are read after lock is acquired
+// migrations run in other connection of pool than one holding lock, so pool must allow more than one connection (see ConnConfig.validated)
+func withMigrationLock(db *gorm.DB, migrate func(applied map[uint]schemaMigration) error) (err error) {
+	// SQLite is embedded in one process & allows only one writer, so named lock is not needed (add in v.1.2.0)
+	if db.Dialect().GetName() == sqliteDialect {
+		return migrateWithAppliedMigrations(db, migrate)
 	}
-	if !db.HasTable(&model.ParentInform{}) {
-		db.CreateTable(&model.ParentInform{})
+
+	ctx := context.Background()
+	conn, err := db.DB().Conn(ctx)
+	if err != nil {
+		err = fmt.Errorf("unable to get connection for migration lock, err: %v", err)
+		return
 	}
-	if !db.HasTable(&model.TeacherAuth{}) {
-		db.CreateTable(&model.TeacherAuth{})
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// named lock is held by session, so it is acquired & released in the same connection
+	lockName := fmt.Sprintf("%s.%s", db.Dialect().CurrentDatabase(), (&schemaMigration{}).TableName())
+	if err = acquireMigrationLock(ctx, conn, db.Dialect().GetName(), lockName); err != nil {
+		return
 	}
-	if !db.HasTable(&model.TeacherInform{}) {
-		db.CreateTable(&model.TeacherInform{})
+	defer func() {
+		releaseQuery := "SELECT RELEASE_LOCK(?)"
+		if db.Dialect().GetName() == postgresDialect {
+			releaseQuery = "SELECT pg_advisory_unlock(hashtext($1))"
+		}
+		_, _ = conn.ExecContext(ctx, releaseQuery, lockName)
+	}()
+
+	err = migrateWithAppliedMigrations(db, migrate)
+	return
+}
+
+// function that acquire named lock in conn, it fails if lock is not acquired in migrationLockTimeout
+func acquireMigrationLock(ctx context.Context, conn *sql.Conn, dialect, lockName string) (err error) {
+	// advisory lock of PostgreSQL is identified by integer and waits for lock until lock_timeout of session (add in v.1.2.0)
+	if dialect == postgresDialect {
+		if _, err = conn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d", migrationLockTimeout.Milliseconds())); err != nil {
+			err = fmt.Errorf("unable to set timeout of migration lock, err: %v", err)
+			return
+		}
+		defer func() {
+			_, _ = conn.ExecContext(ctx, "RESET lock_timeout")
+		}()
+		if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", lockName); err != nil {
+			err = fmt.Errorf("unable to acquire migration lock in %s, err: %v", migrationLockTimeout, err)
+		}
+		return
 	}
-	if !db.HasTable(&model.UnsignedStudent{}) {
-		db.CreateTable(&model.UnsignedStudent{})
+
+	var acquired sql.NullInt64
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(migrationLockTimeout.Seconds())).Scan(&acquired); err != nil {
+		err = fmt.Errorf("unable to acquire migration lock, err: %v", err)
+		return
 	}
-	if !db.HasTable(&model.ParentChildren{}) {
-		db.CreateTable(&model.ParentChildren{})
+	if !acquired.Valid || acquired.Int64 != 1 {
+		err = fmt.Errorf("migration lock is held by other process more than %s", migrationLockTimeout)
+		return
 	}
+	return
+}
 
-	//db.AutoMigrate(&model.AdminAuth{}, &model.StudentAuth{}, &model.StudentInform{}, &model.ParentAuth{}, &model.ParentInform{}, &model.TeacherAuth{}, &model.TeacherInform{})
-	db.Model(&model.StudentAuth{}).AddForeignKey("parent_uuid", "parent_auths(uuid)", "RESTRICT", "RESTRICT")
-	db.Model(&model.StudentInform{}).AddForeignKey("student_uuid", "student_auths(uuid)", "RESTRICT", "RESTRICT")
-	db.Model(&model.TeacherInform{}).AddForeignKey("teacher_uuid", "teacher_auths(uuid)", "RESTRICT", "RESTRICT")
-	db.Model(&model.ParentInform{}).AddForeignKey("parent_uuid", "parent_auths(uuid)", "RESTRICT", "RESTRICT")
-	db.Model(&model.ParentChildren{}).AddForeignKey("parent_uuid", "parent_auths(uuid)", "RESTRICT", "RESTRICT")
-	db.Model(&model.ParentChildren{}).AddForeignKey("student_uuid", "student_auths(uuid)", "RESTRICT", "RESTRICT")
+// function that call migrate with applied migrations, bookkeeping table is created if not exist
+func migrateWithAppliedMigrations(db *gorm.DB, migrate func(applied map[uint]schemaMigration) error) (err error) {
+	if err = createTablesIfNotExist(db, &schemaMigration{}); err != nil {
+		err = fmt.Errorf("unable to create %s table, err: %v", (&schemaMigration{}).TableName(), err)
+		return
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return
+	}
+	err = migrate(applied)
+	return
+}
+
+// function that return applied migrations with version as key
+func appliedMigrations(db *gorm.DB) (applied map[uint]schemaMigration, err error) {
+	applied = map[uint]schemaMigration{}
+	if !db.HasTable(&schemaMigration{}) {
+		return
+	}
+
+	var records []schemaMigration
+	if err = db.Find(&records).Error; err != nil {
+		err = fmt.Errorf("unable to read applied migrations, err: %v", err)
+		return
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return
+}
 
-	// 데이터 무결성 제약조건 추가 필요
+// function that return migrations sorted by version
+func sortedMigrations() []migration {
+	sorted := make([]migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].version < sorted[j].version })
+	return sorted
 }
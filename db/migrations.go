@@ -0,0 +1,324 @@
+// add file in v.1.2.0
+// this file declare ordered schema migrations applied by Migrate, MigrateUp and rolled back by MigrateDown
+// version of migration must not be changed or reused once it is released, add new migration at the end of list instead
+// DDL is not transactional in MySQL, so up & down steps are written to be re-runnable after they fail in the middle
+
+package db
+
+import (
+	"auth/model"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"strings"
+)
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create_account_tables",
+		up: func(db *gorm.DB) (err error) {
+			// tables may already exist in DB created before versioned migration, so only missing tables are created
+			if err = createTablesIfNotExist(db, &model.AdminAuth{}, &model.StudentAuth{}, &model.StudentInform{},
+				&model.ParentAuth{}, &model.ParentInform{}, &model.TeacherAuth{}, &model.TeacherInform{},
+				&model.UnsignedStudent{}, &model.ParentChildren{}); err != nil {
+				return
+			}
+			for _, fk := range foreignKeys {
+				if err = fk.add(db); err != nil {
+					return
+				}
+			}
+			return
+		},
+		down: func(db *gorm.DB) error {
+			// tables referencing other table are dropped first
+			return dropTablesIfExist(db, &model.ParentChildren{}, &model.UnsignedStudent{}, &model.TeacherInform{},
+				&model.TeacherAuth{}, &model.ParentInform{}, &model.StudentInform{}, &model.StudentAuth{},
+				&model.ParentAuth{}, &model.AdminAuth{})
+		},
+	}, {
+		version: 2,
+		name:    "create_token_and_session_tables",
+		up: func(db *gorm.DB) error {
+			return createTablesIfNotExist(db, &model.RefreshToken{}, &model.RevokedToken{}, &model.SessionRevocation{}, &model.Session{})
+		},
+		down: func(db *gorm.DB) error {
+			return dropTablesIfExist(db, &model.Session{}, &model.SessionRevocation{}, &model.RevokedToken{}, &model.RefreshToken{})
+		},
+	}, {
+		version: 3,
+		name:    "create_auth_log_and_audit_event_tables",
+		up: func(db *gorm.DB) error {
+			return createTablesIfNotExist(db, &model.AuthLog{}, &model.AuditEvent{})
+		},
+		down: func(db *gorm.DB) error {
+			return dropTablesIfExist(db, &model.AuditEvent{}, &model.AuthLog{})
+		},
+	}, {
+		version: 4,
+		name:    "create_school_term_and_graduated_student_tables",
+		up: func(db *gorm.DB) error {
+			return createTablesIfNotExist(db, &model.GraduatedStudent{}, &model.SchoolTerm{})
+		},
+		down: func(db *gorm.DB) error {
+			return dropTablesIfExist(db, &model.SchoolTerm{}, &model.GraduatedStudent{})
+		},
+	}, {
+		version: 5,
+		name:    "create_message_template_and_outbox_tables",
+		up: func(db *gorm.DB) error {
+			return createTablesIfNotExist(db, &model.MessageTemplate{}, &model.OutboxMessage{})
+		},
+		down: func(db *gorm.DB) error {
+			return dropTablesIfExist(db, &model.OutboxMessage{}, &model.MessageTemplate{})
+		},
+	}, {
+		version: 6,
+		name:    "create_password_and_two_factor_auth_tables",
+		up: func(db *gorm.DB) error {
+			return createTablesIfNotExist(db, &model.LoginThrottle{}, &model.PasswordReset{}, &model.PasswordHistory{},
+				&model.TwoFactorAuth{}, &model.RecoveryCode{})
+		},
+		down: func(db *gorm.DB) error {
+			return dropTablesIfExist(db, &model.RecoveryCode{}, &model.TwoFactorAuth{}, &model.PasswordHistory{},
+				&model.PasswordReset{}, &model.LoginThrottle{})
+		},
+	}, {
+		version: 7,
+		name:    "add_auth_code_expires_at_to_unsigned_students",
+		up: func(db *gorm.DB) error {
+			if db.Dialect().HasColumn(model.UnsignedStudentInstance.TableName(), "auth_code_expires_at") {
+				return nil
+			}
+			return db.AutoMigrate(&model.UnsignedStudent{}).Error
+		},
+		down: func(db *gorm.DB) error {
+			// SQLite bundled in driver doesn't support DROP COLUMN, and nullable column left in table doesn't break previous version
+			if db.Dialect().GetName() == sqliteDialect {
+				return nil
+			}
+			if !db.Dialect().HasColumn(model.UnsignedStudentInstance.TableName(), "auth_code_expires_at") {
+				return nil
+			}
+			return db.Model(&model.UnsignedStudent{}).DropColumn("auth_code_expires_at").Error
+		},
+	}, {
+		version: 8,
+		name:    "add_unique_index_to_unsigned_students_auth_code",
+		up: func(db *gorm.DB) error {
+			if db.Dialect().HasIndex(model.UnsignedStudentInstance.TableName(), "auth_code") {
+				return nil
+			}
+			return db.Model(&model.UnsignedStudent{}).AddUniqueIndex("auth_code", "auth_code").Error
+		},
+		down: func(db *gorm.DB) error {
+			if !db.Dialect().HasIndex(model.UnsignedStudentInstance.TableName(), "auth_code") {
+				return nil
+			}
+			return db.Model(&model.UnsignedStudent{}).RemoveIndex("auth_code").Error
+		},
+	}, {
+		version: 9,
+		name:    "add_unique_keys_of_alive_rows",
+		up: func(db *gorm.DB) error {
+			return addUniqueKeys(db, aliveUniqueKeys)
+		},
+		down: func(db *gorm.DB) error {
+			return removeUniqueKeys(db, aliveUniqueKeys)
+		},
+	}, {
+		version: 10,
+		name:    "add_unique_keys_of_optional_columns",
+		up: func(db *gorm.DB) error {
+			return addUniqueKeys(db, optionalAliveUniqueKeys)
+		},
+		down: func(db *gorm.DB) error {
+			return removeUniqueKeys(db, optionalAliveUniqueKeys)
+		},
+	},
+}
+
+// FK constraints of tables, in order that referenced table is handled before referencing table
+var foreignKeys = []foreignKey{
+	{table: model.StudentAuthInstance.TableName(), column: "parent_uuid", refTable: model.ParentAuthInstance.TableName(), refColumn: "uuid"},
+	{table: model.StudentInformInstance.TableName(), column: "student_uuid", refTable: model.StudentAuthInstance.TableName(), refColumn: "uuid"},
+	{table: model.TeacherInformInstance.TableName(), column: "teacher_uuid", refTable: model.TeacherAuthInstance.TableName(), refColumn: "uuid"},
+	{table: model.ParentInformInstance.TableName(), column: "parent_uuid", refTable: model.ParentAuthInstance.TableName(), refColumn: "uuid"},
+	{table: model.ParentChildrenInstance.TableName(), column: "parent_uuid", refTable: model.ParentAuthInstance.TableName(), refColumn: "uuid"},
+	{table: model.ParentChildrenInstance.TableName(), column: "student_uuid", refTable: model.StudentAuthInstance.TableName(), refColumn: "uuid"},
+}
+
+type foreignKey struct {
+	table, column       string
+	refTable, refColumn string
+}
+
+// FK can't be added to existing table in SQLite, so table is created again with FK constraint (add in v.1.2.0)
+func (fk foreignKey) add(db *gorm.DB) error {
+	if db.Dialect().GetName() == sqliteDialect {
+		return recreateSQLiteTableWithForeignKey(db, fk)
+	}
+	return db.Table(fk.table).AddForeignKey(fk.column, fk.dest(), "RESTRICT", "RESTRICT").Error
+}
+
+func (fk foreignKey) dest() string {
+	return fmt.Sprintf("%s(%s)", fk.refTable, fk.refColumn)
+}
+
+// constraint name is the same with one generated in AddForeignKey of gorm (ex: student_auths_parent_uuid_parent_auths_uuid_foreign)
+func (fk foreignKey) constraintName(db *gorm.DB) string {
+	return db.Dialect().BuildKeyName(fk.table, fk.column, fk.dest(), "foreign")
+}
+
+// unique keys checked by SELECT in BeforeCreate hook of model before v.1.2.0
+// name of key is used as index name, so it is reported as key of duplicate entry error handled in handler
+var aliveUniqueKeys = []aliveUniqueKey{
+	{table: model.StudentAuthInstance.TableName(), name: "student_id", columns: []string{"student_id"}},
+	{table: model.TeacherAuthInstance.TableName(), name: "teacher_id", columns: []string{"teacher_id"}},
+	{table: model.ParentAuthInstance.TableName(), name: "parent_id", columns: []string{"parent_id"}},
+	{table: model.StudentInformInstance.TableName(), name: "phone_number", columns: []string{"phone_number"}},
+	{table: model.StudentInformInstance.TableName(), name: "profile_uri", columns: []string{"profile_uri"}},
+	{table: model.StudentInformInstance.TableName(), name: "student_number", columns: []string{"grade", "class", "student_number"}},
+	{table: model.UnsignedStudentInstance.TableName(), name: "phone_number", columns: []string{"phone_number"}},
+	{table: model.UnsignedStudentInstance.TableName(), name: "pre_profile_uri", columns: []string{"pre_profile_uri"}},
+	{table: model.UnsignedStudentInstance.TableName(), name: "student_number", columns: []string{"grade", "class", "student_number"}},
+	{table: model.ParentChildrenInstance.TableName(), name: "student_number", columns: []string{"grade", "class", "student_number", "name"}},
+}
+
+// unique keys of optional columns checked by SELECT in BeforeCreate hook of model before v.1.2.0
+// phone number not entered is stored as empty string, so it is excluded from key
+// grade & class of teacher not in charge are stored as NULL (which is never duplicate in unique key), and 0 is excluded just in case it is stored
+var optionalAliveUniqueKeys = []aliveUniqueKey{
+	{table: model.TeacherInformInstance.TableName(), name: "phone_number", columns: []string{"phone_number"}, emptyValue: "''"},
+	{table: model.TeacherInformInstance.TableName(), name: "class", columns: []string{"grade", "class"}, emptyValue: "0"},
+	{table: model.ParentInformInstance.TableName(), name: "phone_number", columns: []string{"phone_number"}, emptyValue: "''"},
+}
+
+// unique key applied only to rows not soft deleted, so that deleted account doesn't hold its id, phone number or seat
+// (ex: seat of graduate deleted in academic year rollover) until it is purged, and restoring it fails if they are taken
+// MySQL doesn't support partial index, so index is added on generated column which is NULL in soft deleted row
+type aliveUniqueKey struct {
+	table   string
+	name    string
+	columns []string
+
+	// value of optional column that is not regarded as key (SQL literal), empty if columns are required
+	emptyValue string
+}
+
+func (k aliveUniqueKey) add(db *gorm.DB) error {
+	if hasIndex(db, k.table, k.indexName(db)) {
+		return nil
+	}
+	quote := db.Dialect().Quote
+	if supportsPartialIndex(db) {
+		columns := make([]string, len(k.columns))
+		for i, column := range k.columns {
+			columns[i] = quote(column)
+		}
+		return db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s) WHERE %s",
+			quote(k.indexName(db)), quote(k.table), strings.Join(columns, ", "), k.condition(quote))).Error
+	}
+	value := "`" + strings.Join(k.columns, "`, `") + "`"
+	if len(k.columns) > 1 {
+		value = fmt.Sprintf("CONCAT_WS('-', %s)", value)
+	}
+	return db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` VARCHAR(255) AS (IF(%s, %s, NULL)) VIRTUAL, ADD UNIQUE INDEX `%s` (`%s`)",
+		k.table, k.generatedColumn(), k.condition(quote), value, k.name, k.generatedColumn())).Error
+}
+
+// condition of row that key is applied to, row soft deleted or having empty value in optional column is excluded
+func (k aliveUniqueKey) condition(quote func(string) string) string {
+	conditions := []string{quote("deleted_at") + " IS NULL"}
+	if k.emptyValue != "" {
+		for _, column := range k.columns {
+			conditions = append(conditions, fmt.Sprintf("%s <> %s", quote(column), k.emptyValue))
+		}
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func (k aliveUniqueKey) remove(db *gorm.DB) error {
+	if !hasIndex(db, k.table, k.indexName(db)) {
+		return nil
+	}
+	if supportsPartialIndex(db) {
+		return db.Exec(fmt.Sprintf("DROP INDEX %s", db.Dialect().Quote(k.indexName(db)))).Error
+	}
+	return db.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP INDEX `%s`, DROP COLUMN `%s`", k.table, k.name, k.generatedColumn())).Error
+}
+
+// index name is unique in whole DB (or schema) in SQLite & PostgreSQL, so table name is prefixed to it
+func (k aliveUniqueKey) indexName(db *gorm.DB) string {
+	if supportsPartialIndex(db) {
+		return k.table + "_" + k.name
+	}
+	return k.name
+}
+
+func (k aliveUniqueKey) generatedColumn() string {
+	return "alive_" + k.name
+}
+
+// function that add unique keys in order of parameter
+func addUniqueKeys(db *gorm.DB, keys []aliveUniqueKey) error {
+	for _, key := range keys {
+		if err := key.add(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// function that remove unique keys in reverse order of parameter
+func removeUniqueKeys(db *gorm.DB, keys []aliveUniqueKey) error {
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := keys[i].remove(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// function that return if partial index is supported in dialect of db, MySQL doesn't support it
+func supportsPartialIndex(db *gorm.DB) bool {
+	switch db.Dialect().GetName() {
+	case sqliteDialect, postgresDialect:
+		return true
+	}
+	return false
+}
+
+// function that return if index exists in table, HasIndex of gorm in SQLite searches name in SQL of index,
+// so index created with quoted name is not found with it and looked up with name in sqlite_master instead
+func hasIndex(db *gorm.DB, table, index string) bool {
+	if db.Dialect().GetName() != sqliteDialect {
+		return db.Dialect().HasIndex(table, index)
+	}
+	var count int
+	db.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND name = ?", table, index).Row().Scan(&count)
+	return count > 0
+}
+
+// function that create tables of models that don't exist yet
+func createTablesIfNotExist(db *gorm.DB, models ...interface{}) error {
+	for _, m := range models {
+		if db.HasTable(m) {
+			continue
+		}
+		if err := db.CreateTable(m).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// function that drop tables of models in order of parameter
+func dropTablesIfExist(db *gorm.DB, models ...interface{}) error {
+	for _, m := range models {
+		if err := db.DropTableIfExists(m).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// add file in v.1.2.0
+// this file declare savepoint callbacks registered in connection to PostgreSQL
+// failed statement aborts whole tx in PostgreSQL, but handler continues tx after some errors as it does with MySQL
+// (ex: duplicate entry in AddUnsignedStudent, or id collision in writeWithUniqueID), so statement is rolled back to savepoint
+
+package db
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+const writeSavepoint = "auth_write"
+
+// function that register callbacks wrapping statement of create, update & delete in savepoint
+func registerPostgresSavepoint(db *gorm.DB) {
+	const set, release = "auth:set_savepoint", "auth:release_savepoint"
+	db.Callback().Create().Before("gorm:create").Register(set, setWriteSavepoint)
+	db.Callback().Create().Before("gorm:commit_or_rollback_transaction").Register(release, releaseWriteSavepoint)
+	db.Callback().Update().Before("gorm:update").Register(set, setWriteSavepoint)
+	db.Callback().Update().Before("gorm:commit_or_rollback_transaction").Register(release, releaseWriteSavepoint)
+	db.Callback().Delete().Before("gorm:delete").Register(set, setWriteSavepoint)
+	db.Callback().Delete().Before("gorm:commit_or_rollback_transaction").Register(release, releaseWriteSavepoint)
+}
+
+// statement is executed in tx begun by caller or gorm:begin_transaction callback, so savepoint can be set in SQLDB of scope
+func setWriteSavepoint(scope *gorm.Scope) {
+	if scope.HasError() {
+		return
+	}
+	if _, err := scope.SQLDB().Exec("SAVEPOINT " + writeSavepoint); err != nil {
+		scope.Err(err)
+		return
+	}
+	scope.InstanceSet(writeSavepoint, true)
+}
+
+// tx is rolled back to savepoint if statement failed, so that error is returned but tx is still usable
+func releaseWriteSavepoint(scope *gorm.Scope) {
+	if _, ok := scope.InstanceGet(writeSavepoint); !ok {
+		return
+	}
+	if scope.HasError() {
+		_, _ = scope.SQLDB().Exec("ROLLBACK TO SAVEPOINT " + writeSavepoint)
+	}
+	if _, err := scope.SQLDB().Exec("RELEASE SAVEPOINT " + writeSavepoint); err != nil {
+		scope.Err(err)
+	}
+}
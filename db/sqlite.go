@@ -0,0 +1,166 @@
+// +build cgo
+
+// add file in v.1.2.0
+// this file declare functions that make SQLite behave like MySQL used in production, so that accessor works on both
+// constraint error of SQLite is translated into MySQL error, because handler handles error with number & key of MySQL error
+// SQLite driver requires cgo, so functions in sqlite_nocgo.go are used instead in binary built without cgo (ex: make build)
+
+package db
+
+import (
+	"auth/tool/mysqlerr"
+	"fmt"
+	mysqlcode "github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/mattn/go-sqlite3"
+	"strings"
+)
+
+// function that create empty table again with FK constraint, because SQLite doesn't support adding FK to existing table
+// indexes of table are dropped with table, so they are created again too
+func recreateSQLiteTableWithForeignKey(db *gorm.DB, fk foreignKey) (err error) {
+	var createTableSQL string
+	if err = db.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", fk.table).Row().Scan(&createTableSQL); err != nil {
+		return
+	}
+	if strings.Contains(createTableSQL, fk.constraintName(db)) {
+		return
+	}
+
+	var count int
+	if err = db.Table(fk.table).Count(&count).Error; err != nil {
+		return
+	}
+	if count != 0 {
+		return fmt.Errorf("unable to add FK constraint to %s table which is not empty", fk.table)
+	}
+
+	rows, err := db.Raw("SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", fk.table).Rows()
+	if err != nil {
+		return
+	}
+	var createIndexSQLs []string
+	for rows.Next() {
+		var createIndexSQL string
+		if err = rows.Scan(&createIndexSQL); err != nil {
+			_ = rows.Close()
+			return
+		}
+		createIndexSQLs = append(createIndexSQLs, createIndexSQL)
+	}
+	if err = rows.Close(); err != nil {
+		return
+	}
+
+	constraint := fmt.Sprintf(", CONSTRAINT `%s` FOREIGN KEY (`%s`) REFERENCES `%s` (`%s`) ON DELETE RESTRICT ON UPDATE RESTRICT)",
+		fk.constraintName(db), fk.column, fk.refTable, fk.refColumn)
+	createTableSQL = strings.TrimSuffix(strings.TrimSpace(createTableSQL), ")") + constraint
+
+	if err = db.Exec(fmt.Sprintf("DROP TABLE `%s`", fk.table)).Error; err != nil {
+		return
+	}
+	for _, query := range append([]string{createTableSQL}, createIndexSQLs...) {
+		if err = db.Exec(query).Error; err != nil {
+			return
+		}
+	}
+	return
+}
+
+// function that register callback translating constraint error of SQLite returned from create, update & delete
+func registerSQLiteErrorTranslator(db *gorm.DB) {
+	const name = "auth:translate_sqlite_error"
+	db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register(name, translateSQLiteError)
+	db.Callback().Update().After("gorm:commit_or_rollback_transaction").Register(name, translateSQLiteError)
+	db.Callback().Delete().After("gorm:commit_or_rollback_transaction").Register(name, translateSQLiteError)
+}
+
+func translateSQLiteError(scope *gorm.Scope) {
+	sqliteErr, ok := scope.DB().Error.(sqlite3.Error)
+	if !ok || sqliteErr.Code != sqlite3.ErrConstraint {
+		return
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		scope.DB().Error = duplicateEntryErrorOf(scope, sqliteErr)
+	case sqlite3.ErrConstraintForeignKey:
+		scope.DB().Error = fkConstraintFailErrorOf(scope)
+	}
+}
+
+// message of SQLite error has columns of unique key (ex: UNIQUE constraint failed: student_informs.grade, student_informs.class, ...)
+// key name of MySQL index is found with columns, and entry is made with values of columns in written row
+func duplicateEntryErrorOf(scope *gorm.Scope, sqliteErr sqlite3.Error) error {
+	message := sqliteErr.Error()
+	message = message[strings.LastIndex(message, ":")+1:]
+
+	var table string
+	var columns, values []string
+	for _, column := range strings.Split(message, ",") {
+		column = strings.TrimSpace(column)
+		table, column = column[:strings.LastIndex(column, ".")], column[strings.LastIndex(column, ".")+1:]
+		columns = append(columns, column)
+		values = append(values, fmt.Sprint(writtenValueOf(scope, column)))
+	}
+
+	key := strings.Join(columns, "_")
+	if len(columns) == 1 && columns[0] == "id" {
+		key = "PRIMARY"
+	}
+	for _, uniqueKey := range append(aliveUniqueKeys, optionalAliveUniqueKeys...) {
+		if uniqueKey.table == table && strings.Join(uniqueKey.columns, ",") == strings.Join(columns, ",") {
+			key = uniqueKey.name
+		}
+	}
+	return mysqlerr.DuplicateEntry(key, strings.Join(values, "-"))
+}
+
+// message of SQLite error doesn't have which FK failed, so FK is found by checking if referenced row of each FK exists
+func fkConstraintFailErrorOf(scope *gorm.Scope) error {
+	table := scope.TableName()
+	db := scope.NewDB()
+	db.Error = nil // error of scope is copied to new DB
+	for _, fk := range foreignKeys {
+		if fk.table != table {
+			continue
+		}
+		value := writtenValueOf(scope, fk.column)
+		if value == nil || fmt.Sprint(value) == "" {
+			continue
+		}
+		var count int
+		if err := db.Table(fk.refTable).Where(fmt.Sprintf("`%s` = ?", fk.refColumn), value).Count(&count).Error; err != nil || count != 0 {
+			continue
+		}
+		return mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
+			DBName:         scope.Dialect().CurrentDatabase(),
+			TableName:      fk.table,
+			ConstraintName: fk.constraintName(db),
+			AttrName:       fk.column,
+		}, mysqlerr.RefInform{
+			TableName: fk.refTable,
+			AttrName:  fk.refColumn,
+		})
+	}
+
+	// row referenced by other table is deleted
+	return &mysql.MySQLError{
+		Number:  mysqlcode.ER_ROW_IS_REFERENCED_2,
+		Message: fmt.Sprintf("Cannot delete or update a parent row: a foreign key constraint fails (table: %s)", table),
+	}
+}
+
+// function that return value of column written in create or update of scope
+func writtenValueOf(scope *gorm.Scope, column string) interface{} {
+	if attrs, ok := scope.InstanceGet("gorm:update_attrs"); ok {
+		if value, ok := attrs.(map[string]interface{})[column]; ok {
+			return value
+		}
+	}
+	if field, ok := scope.FieldByName(column); ok {
+		return field.Field.Interface()
+	}
+	return nil
+}
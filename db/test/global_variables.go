@@ -5,6 +5,7 @@ import (
 	"auth/model"
 	"auth/tool/mysqlerr"
 	"github.com/jinzhu/gorm"
+	"os"
 	"strings"
 	"sync"
 )
@@ -15,7 +16,18 @@ var (
 	waitForFinish sync.WaitGroup
 )
 
-const numberOfTestFunc = 28
+const numberOfTestFunc = 34
+
+// parent status filled with default value of column if it is not set while creating student inform (add in v.1.2.0)
+var defaultParentStatus = model.ParentStatus("OK_CONN_OK_NOTIFY")
+
+// name of DB reported in FK constraint fail error, SQLite reports name of schema instead of DB (add in v.1.2.0)
+var testDBName = func() string {
+	if os.Getenv("TEST_DB_DIALECT") == "sqlite3" {
+		return "main"
+	}
+	return strings.ToLower("SMS_Auth_Test_DB")
+}()
 
 // Hashed Passwords
 var passwords = map[string]string{
@@ -28,7 +40,7 @@ var passwords = map[string]string{
 var (
 	// StudentAuth 테이블의 ParentUUID 속성의 FK 제약조건 위반에 대한 에러 변수
 	studentAuthParentUUIDFKConstraintFailError = mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
-		DBName:         strings.ToLower("SMS_Auth_Test_DB"),
+		DBName:         testDBName,
 		TableName:      model.StudentAuthInstance.TableName(),
 		ConstraintName: model.StudentAuthInstance.ParentUUIDConstraintName(),
 		AttrName:       model.StudentAuthInstance.ParentUUID.KeyName(),
@@ -39,7 +51,7 @@ var (
 
 	// StudentInform 테이블의 StudentUUID 속성의 FK 제약조건 위반에 대한 에러 변수
 	studentInformStudentUUIDFKConstraintFailError = mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
-		DBName:         strings.ToLower("SMS_Auth_Test_DB"),
+		DBName:         testDBName,
 		TableName:      model.StudentInformInstance.TableName(),
 		ConstraintName: model.StudentInformInstance.StudentUUIDConstraintName(),
 		AttrName:       model.StudentInformInstance.StudentUUID.KeyName(),
@@ -50,7 +62,7 @@ var (
 
 	// TeacherInform 테이블의 TeacherUUID 속성의 FK 제약조건 위반에 대한 에러 변수
 	teacherInformTeacherUUIDFKConstraintFailError = mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
-		DBName:         strings.ToLower("SMS_Auth_Test_DB"),
+		DBName:         testDBName,
 		TableName:      model.TeacherInformInstance.TableName(),
 		ConstraintName: model.TeacherInformInstance.TeacherUUIDConstraintName(),
 		AttrName:       model.TeacherInformInstance.TeacherUUID.KeyName(),
@@ -61,7 +73,7 @@ var (
 
 	// TeacherInform 테이블의 TeacherUUID 속성의 FK 제약조건 위반에 대한 에러 변수
 	parentInformParentUUIDFKConstraintFailError = mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
-		DBName:         strings.ToLower("SMS_Auth_Test_DB"),
+		DBName:         testDBName,
 		TableName:      model.ParentInformInstance.TableName(),
 		ConstraintName: model.ParentInformInstance.ParentUUIDConstraintName(),
 		AttrName:       model.ParentInformInstance.ParentUUID.KeyName(),
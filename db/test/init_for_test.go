@@ -5,29 +5,37 @@ import (
 	"auth/db/access"
 	"github.com/hashicorp/consul/api"
 	"log"
+	"os"
 	"sync"
 )
 
 func init() {
-	cli, err := api.NewClient(api.DefaultConfig())
+	var err error
+	if os.Getenv("TEST_DB_DIALECT") == "sqlite3" {
+		// in-memory SQLite is used to run test without consul & MySQL (add in v.1.2.0)
+		dbc, err = db.Connect(db.ConnConfig{Dialect: "sqlite3", DB: ":memory:"})
+	} else {
+		var cli *api.Client
+		if cli, err = api.NewClient(api.DefaultConfig()); err != nil {
+			log.Fatal(err)
+		}
+		dbc, _, err = db.ConnectWithConsul(cli, "db/auth/local_test")
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	dbc, _, err = db.ConnectWithConsul(cli, "db/auth/local_test")
-	if err != nil {
+	if err = db.Migrate(dbc); err != nil {
 		log.Fatal(err)
 	}
-	db.Migrate(dbc)
 
 	manager, err = db.NewAccessorManage(access.Default(dbc))
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	waitForFinish = sync.WaitGroup{}
+	waitForFinish.Add(numberOfTestFunc)
 	go func() {
-		waitForFinish = sync.WaitGroup{}
-		waitForFinish.Add(numberOfTestFunc)
 		waitForFinish.Wait()
 		_ = dbc.Close()
 	}()
@@ -3,10 +3,8 @@ package test
 import (
 	"auth/model"
 	"auth/tool/mysqlerr"
-	"fmt"
 	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
-	"log"
 	"testing"
 )
 
@@ -14,7 +12,7 @@ func Test_Accessor_CreateStudentAuth(t *testing.T) {
 	// Tx 시작
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -44,7 +42,7 @@ func Test_Accessor_CreateStudentAuth(t *testing.T) {
 		}
 		if _, err := access.CreateParentAuth(auth); err != nil {
 			access.Rollback()
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -103,7 +101,7 @@ func Test_Accessor_CreateStudentAuth(t *testing.T) {
 func Test_Accessor_CreateParentAuth(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -151,7 +149,7 @@ func Test_Accessor_CreateParentAuth(t *testing.T) {
 func Test_Accessor_CreateTeacherAuth(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -199,7 +197,7 @@ func Test_Accessor_CreateTeacherAuth(t *testing.T) {
 func Test_Accessor_CreateStudentInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -223,7 +221,7 @@ func Test_Accessor_CreateStudentInform(t *testing.T) {
 		})
 		if err != nil {
 			access.Rollback()
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -251,7 +249,7 @@ func Test_Accessor_CreateStudentInform(t *testing.T) {
 		})
 		if err != nil {
 			access.Rollback()
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -287,7 +285,7 @@ func Test_Accessor_CreateStudentInform(t *testing.T) {
 			Name:          "빡진홍",
 			PhoneNumber:   "01012341234",
 			ProfileURI:    "example.com/profiles/student-222222222222",
-			ExpectError:   mysqlerr.DuplicateEntry(model.StudentInformInstance.StudentNumber.KeyName(), "2207"),
+			ExpectError:   mysqlerr.DuplicateEntry(model.StudentInformInstance.StudentNumber.KeyName(), "2-2-7"),
 		}, { // phone number duplicate
 			StudentUUID:   "student-222222222222",
 			Grade:         1,
@@ -330,6 +328,9 @@ func Test_Accessor_CreateStudentInform(t *testing.T) {
 		}
 
 		test.ExpectResult = inform.DeepCopy()
+		if test.ExpectError == nil {
+			test.ExpectResult.ParentStatus = defaultParentStatus
+		}
 		result, err := access.CreateStudentInform(inform)
 
 		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
@@ -344,7 +345,7 @@ func Test_Accessor_CreateStudentInform(t *testing.T) {
 func Test_Accessor_CreateTeacherInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -371,7 +372,7 @@ func Test_Accessor_CreateTeacherInform(t *testing.T) {
 		})
 		if err != nil {
 			access.Rollback()
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth. err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth. err: %v", err)
 		}
 	}
 
@@ -436,7 +437,7 @@ func Test_Accessor_CreateTeacherInform(t *testing.T) {
 func Test_Accessor_CreateParentInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -463,7 +464,7 @@ func Test_Accessor_CreateParentInform(t *testing.T) {
 		})
 		if err != nil {
 			access.Rollback()
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
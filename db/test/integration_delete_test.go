@@ -2,16 +2,16 @@ package test
 
 import (
 	"auth/model"
-	"fmt"
+	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
-	"log"
 	"testing"
+	"time"
 )
 
 func Test_Access_DeleteStudentAuth(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -34,7 +34,7 @@ func Test_Access_DeleteStudentAuth(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -56,7 +56,7 @@ func Test_Access_DeleteStudentAuth(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -92,7 +92,7 @@ func Test_Access_DeleteStudentAuth(t *testing.T) {
 func Test_Access_DeleteTeacherAuth(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -115,7 +115,7 @@ func Test_Access_DeleteTeacherAuth(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -141,7 +141,7 @@ func Test_Access_DeleteTeacherAuth(t *testing.T) {
 func Test_Access_DeleteParentAuth(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -164,7 +164,7 @@ func Test_Access_DeleteParentAuth(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -186,3 +186,254 @@ func Test_Access_DeleteParentAuth(t *testing.T) {
 		assert.Equalf(t, test.ExpectError, err, "error assertion error (test case: %v)", test)
 	}
 }
+
+func Test_Accessor_PurgeDeletedAccounts(t *testing.T) {
+	access, err := manager.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		access.Rollback()
+		waitForFinish.Done()
+	}()
+
+	// 학부모 계정 및 정보 생성
+	if _, err := access.CreateParentAuth(&model.ParentAuth{
+		UUID:     "parent-111111111111",
+		ParentID: "jinhong07191",
+		ParentPW: model.ParentPW(passwords["testPW1"]),
+	}); err != nil {
+		t.Fatalf("error occurs while creating parent auth, err: %v", err)
+	}
+	if _, err := access.CreateParentInform(&model.ParentInform{
+		ParentUUID:  "parent-111111111111",
+		Name:        "박진홍",
+		PhoneNumber: "01011111111",
+	}); err != nil {
+		t.Fatalf("error occurs while creating parent inform, err: %v", err)
+	}
+
+	// 학생 계정 및 정보, 자녀 정보 생성
+	if _, err := access.CreateStudentAuth(&model.StudentAuth{
+		UUID:       "student-111111111111",
+		StudentID:  "jinhong07191",
+		StudentPW:  model.StudentPW(passwords["testPW1"]),
+		ParentUUID: "parent-111111111111",
+	}); err != nil {
+		t.Fatalf("error occurs while creating student auth, err: %v", err)
+	}
+	if _, err := access.CreateStudentInform(&model.StudentInform{
+		StudentUUID:   "student-111111111111",
+		Grade:         2,
+		Class:         2,
+		StudentNumber: 7,
+		Name:          "박진홍",
+		PhoneNumber:   "01022222222",
+		ProfileURI:    "example.com/profiles/student-111111111111",
+	}); err != nil {
+		t.Fatalf("error occurs while creating student inform, err: %v", err)
+	}
+	if _, err := access.CreateParentChildren(&model.ParentChildren{
+		ParentUUID:    "parent-111111111111",
+		Grade:         2,
+		Class:         2,
+		StudentNumber: 7,
+		Name:          "박진홍",
+		StudentUUID:   "student-111111111111",
+	}); err != nil {
+		t.Fatalf("error occurs while creating parent children, err: %v", err)
+	}
+
+	// 선생님 계정 및 정보 생성
+	if _, err := access.CreateTeacherAuth(&model.TeacherAuth{
+		UUID:      "teacher-111111111111",
+		TeacherID: "jinhong07191",
+		TeacherPW: model.TeacherPW(passwords["testPW1"]),
+	}); err != nil {
+		t.Fatalf("error occurs while creating teacher auth, err: %v", err)
+	}
+	if _, err := access.CreateTeacherInform(&model.TeacherInform{
+		TeacherUUID: "teacher-111111111111",
+		Grade:       2,
+		Class:       2,
+		Name:        "오준상",
+		PhoneNumber: "01033333333",
+	}); err != nil {
+		t.Fatalf("error occurs while creating teacher inform, err: %v", err)
+	}
+
+	// 계정 삭제 (soft delete)
+	for _, deleteFunc := range []func() error{
+		func() error { return access.DeleteParentChildrenWithStudentUUID("student-111111111111") },
+		func() error { return access.DeleteStudentInform("student-111111111111") },
+		func() error { return access.DeleteStudentAuth("student-111111111111") },
+		func() error { return access.DeleteParentInform("parent-111111111111") },
+		func() error { return access.DeleteParentAuth("parent-111111111111") },
+		func() error { return access.DeleteTeacherInform("teacher-111111111111") },
+		func() error { return access.DeleteTeacherAuth("teacher-111111111111") },
+	} {
+		if err := deleteFunc(); err != nil {
+			t.Fatalf("error occurs while deleting account, err: %v", err)
+		}
+	}
+
+	tests := []struct {
+		DeletedBefore     time.Time
+		ExpectPurgedCount int64
+		ExpectError       error
+	} {
+		{ // accounts deleted after deletedBefore -> not purged
+			DeletedBefore:     time.Now().Add(-time.Hour),
+			ExpectPurgedCount: 0,
+			ExpectError:       nil,
+		}, { // success case
+			DeletedBefore:     time.Now().Add(time.Hour),
+			ExpectPurgedCount: 3,
+			ExpectError:       nil,
+		},
+	}
+
+	for _, test := range tests {
+		purgedCount, err := access.PurgeDeletedAccounts(test.DeletedBefore)
+		assert.Equalf(t, test.ExpectError, err, "error assertion error (test case: %v)", test)
+		assert.Equalf(t, test.ExpectPurgedCount, purgedCount, "purged count assertion error (test case: %v)", test)
+	}
+
+	testsForConfirmPurge := []struct {
+		GetDeletedFunc func() error
+		ExpectError    error
+	} {
+		{
+			GetDeletedFunc: func() (err error) { _, err = access.GetDeletedStudentAuthWithUUID("student-111111111111"); return },
+			ExpectError:    gorm.ErrRecordNotFound,
+		}, {
+			GetDeletedFunc: func() (err error) { _, err = access.GetDeletedTeacherAuthWithUUID("teacher-111111111111"); return },
+			ExpectError:    gorm.ErrRecordNotFound,
+		}, {
+			GetDeletedFunc: func() (err error) { _, err = access.GetDeletedParentAuthWithUUID("parent-111111111111"); return },
+			ExpectError:    gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range testsForConfirmPurge {
+		err := test.GetDeletedFunc()
+		assert.Equalf(t, test.ExpectError, err, "error assertion error (test case: %v)", test)
+	}
+
+	// 영구 삭제된 계정 마다 감사 로그 기록 확인
+	events, err := access.GetAuditEvents(&model.AuditEvent{Action: "purge"}, 0, 10)
+	var targetKeys []string
+	for _, event := range events {
+		targetKeys = append(targetKeys, event.TargetKey)
+	}
+	assert.Equalf(t, nil, err, "error assertion error while getting audit events")
+	assert.ElementsMatchf(t, []string{"student-111111111111", "parent-111111111111", "teacher-111111111111"}, targetKeys, "purge audit event assertion error")
+}
+
+// add in v.1.2.0
+func Test_Accessor_PurgeDeletedParentOfAliveStudent(t *testing.T) {
+	access, err := manager.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		access.Rollback()
+		waitForFinish.Done()
+	}()
+
+	// 학부모 계정 및 자녀 정보, 학생 계정 생성
+	if _, err := access.CreateParentAuth(&model.ParentAuth{
+		UUID:     "parent-111111111111",
+		ParentID: "jinhong07191",
+		ParentPW: model.ParentPW(passwords["testPW1"]),
+	}); err != nil {
+		t.Fatalf("error occurs while creating parent auth, err: %v", err)
+	}
+	if _, err := access.CreateStudentAuth(&model.StudentAuth{
+		UUID:       "student-111111111111",
+		StudentID:  "jinhong07191",
+		StudentPW:  model.StudentPW(passwords["testPW1"]),
+		ParentUUID: "parent-111111111111",
+	}); err != nil {
+		t.Fatalf("error occurs while creating student auth, err: %v", err)
+	}
+	if _, err := access.CreateParentChildren(&model.ParentChildren{
+		ParentUUID:    "parent-111111111111",
+		Grade:         2,
+		Class:         2,
+		StudentNumber: 7,
+		Name:          "박진홍",
+		StudentUUID:   "student-111111111111",
+	}); err != nil {
+		t.Fatalf("error occurs while creating parent children, err: %v", err)
+	}
+
+	// 학부모 계정만 삭제 (soft delete)
+	for _, deleteFunc := range []func() error{
+		func() error { return access.DeleteParentChildrenWithParentUUID("parent-111111111111") },
+		func() error { return access.DeleteParentAuth("parent-111111111111") },
+	} {
+		if err := deleteFunc(); err != nil {
+			t.Fatalf("error occurs while deleting account, err: %v", err)
+		}
+	}
+
+	purgedCount, err := access.PurgeDeletedAccounts(time.Now().Add(time.Hour))
+	assert.Equalf(t, nil, err, "error assertion error while purging parent referenced by alive student")
+	assert.Equalf(t, int64(1), purgedCount, "purged count assertion error")
+
+	_, err = access.GetDeletedParentAuthWithUUID("parent-111111111111")
+	assert.Equalf(t, gorm.ErrRecordNotFound, err, "purged parent assertion error")
+	student, err := access.GetStudentAuthWithUUID("student-111111111111")
+	assert.Equalf(t, nil, err, "error assertion error while getting alive student")
+	assert.Equalf(t, model.ParentUUID(""), student.ParentUUID, "parent uuid of alive student assertion error")
+}
+
+// add in v.1.2.0
+func Test_Accessor_DeleteRefreshToken(t *testing.T) {
+	access, err := manager.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		access.Rollback()
+		waitForFinish.Done()
+	}()
+
+	const tokenHash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	if _, err := access.CreateRefreshToken(&model.RefreshToken{
+		TokenHash: tokenHash,
+		OwnerUUID: "student-111111111111",
+		SessionID: "8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("error occurs while creating refresh token, err: %v", err)
+	}
+
+	// 같은 토큰은 한 번만 삭제(회전)될 수 있음
+	for _, test := range []struct {
+		TokenHash   string
+		ExpectError error
+	} {
+		{
+			TokenHash:   tokenHash,
+			ExpectError: nil,
+		}, {
+			TokenHash:   tokenHash,
+			ExpectError: gorm.ErrRecordNotFound,
+		}, {
+			TokenHash:   "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752",
+			ExpectError: gorm.ErrRecordNotFound,
+		},
+	} {
+		err := access.DeleteRefreshToken(test.TokenHash)
+		assert.Equalf(t, test.ExpectError, err, "error assertion error (test case: %v)", test)
+	}
+
+	_, err = access.GetRefreshTokenWithHash(tokenHash)
+	assert.Equalf(t, gorm.ErrRecordNotFound, err, "error assertion error while getting deleted refresh token")
+
+	deletedToken, err := access.GetDeletedRefreshTokenWithHash(tokenHash)
+	assert.Equalf(t, nil, err, "error assertion error while getting deleted refresh token with unscoped query")
+	assert.Equalf(t, model.SessionID("8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c"), deletedToken.SessionID, "session id assertion error of deleted refresh token")
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"auth/db"
+	"auth/model"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// migrations are run on SQLite file DB of its own, because rolling back drops tables used by other tests (add in v.1.2.0)
+func connectToMigrationTestDB(t *testing.T) (conn *gorm.DB, closeFunc func()) {
+	dir, err := ioutil.TempDir("", "auth-migration-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err = db.Connect(db.ConnConfig{Dialect: "sqlite3", DB: filepath.Join(dir, "migration.db")})
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	conn.LogMode(false)
+
+	closeFunc = func() {
+		_ = conn.Close()
+		_ = os.RemoveAll(dir)
+	}
+	return
+}
+
+// function that return number of applied migrations, and fail test if applied migrations are not first ones in order of version
+func appliedMigrationCount(t *testing.T, conn *gorm.DB) (count int) {
+	statuses, err := db.MigrationStatuses(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, status := range statuses {
+		if status.AppliedAt == nil {
+			continue
+		}
+		if i != count {
+			t.Errorf("migration %d(%s) is applied before previous migration", status.Version, status.Name)
+		}
+		count++
+	}
+	return
+}
+
+func Test_MigrateUpAndDown(t *testing.T) {
+	conn, closeFunc := connectToMigrationTestDB(t)
+	defer closeFunc()
+
+	statuses, err := db.MigrationStatuses(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrationCount := len(statuses)
+	for i, status := range statuses {
+		assert.Equalf(t, uint(i + 1), status.Version, "version assertion error (status: %v)", status)
+		assert.Nilf(t, status.AppliedAt, "applied at assertion error (status: %v)", status)
+	}
+
+	tests := []struct {
+		Description        string
+		Migrate            func() error
+		ExpectError        bool
+		ExpectAppliedCount int
+		ExpectTables       map[interface{}]bool // model -> whether table of model exists
+	} {
+		{
+			Description:        "apply first 3 migrations",
+			Migrate:            func() error { return db.MigrateUp(conn, 3) },
+			ExpectAppliedCount: 3,
+			ExpectTables:       map[interface{}]bool{&model.StudentAuth{}: true, &model.AuditEvent{}: true, &model.SchoolTerm{}: false},
+		}, {
+			Description:        "apply all pending migrations",
+			Migrate:            func() error { return db.MigrateUp(conn, 0) },
+			ExpectAppliedCount: migrationCount,
+			ExpectTables:       map[interface{}]bool{&model.SchoolTerm{}: true, &model.RecoveryCode{}: true},
+		}, {
+			Description:        "nothing is applied if every migration is applied",
+			Migrate:            func() error { return db.MigrateUp(conn, 0) },
+			ExpectAppliedCount: migrationCount,
+		}, {
+			Description:        "roll back last 5 migrations",
+			Migrate:            func() error { return db.MigrateDown(conn, 5) },
+			ExpectAppliedCount: migrationCount - 5,
+			ExpectTables:       map[interface{}]bool{&model.MessageTemplate{}: true, &model.RecoveryCode{}: false},
+		}, {
+			Description:        "steps to roll back must be positive",
+			Migrate:            func() error { return db.MigrateDown(conn, 0) },
+			ExpectError:        true,
+			ExpectAppliedCount: migrationCount - 5,
+		}, {
+			Description:        "roll back more than applied migrations",
+			Migrate:            func() error { return db.MigrateDown(conn, migrationCount) },
+			ExpectAppliedCount: 0,
+			ExpectTables:       map[interface{}]bool{&model.StudentAuth{}: false, &model.AuditEvent{}: false},
+		}, {
+			Description:        "apply all migrations again after rolled back",
+			Migrate:            func() error { return db.MigrateUp(conn, 0) },
+			ExpectAppliedCount: migrationCount,
+			ExpectTables:       map[interface{}]bool{&model.StudentAuth{}: true, &model.RecoveryCode{}: true},
+		},
+	}
+
+	for _, test := range tests {
+		err := test.Migrate()
+		assert.Equalf(t, test.ExpectError, err != nil, "error assertion error (test case: %s, err: %v)", test.Description, err)
+		assert.Equalf(t, test.ExpectAppliedCount, appliedMigrationCount(t, conn), "applied count assertion error (test case: %s)", test.Description)
+		for table, exists := range test.ExpectTables {
+			assert.Equalf(t, exists, conn.HasTable(table), "table existence assertion error (test case: %s, table: %T)", test.Description, table)
+		}
+	}
+}
+
+// DDL is not transactional in MySQL, so migration failed in the middle leaves some of its changes without record
+func Test_MigrateUpAfterHalfApplied(t *testing.T) {
+	conn, closeFunc := connectToMigrationTestDB(t)
+	defer closeFunc()
+
+	statuses, err := db.MigrationStatuses(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrationCount := len(statuses)
+
+	// add_alive_unique_keys migration failed after adding first key
+	if err = db.MigrateUp(conn, 8); err != nil {
+		t.Fatal(err)
+	}
+	halfApplied := "CREATE UNIQUE INDEX student_auths_student_id ON student_auths (student_id) WHERE deleted_at IS NULL"
+	if err = conn.Exec(halfApplied).Error; err != nil {
+		t.Fatal(err)
+	}
+	assert.Equalf(t, 8, appliedMigrationCount(t, conn), "applied count assertion error before re-running migration")
+
+	err = db.MigrateUp(conn, 0)
+	assert.Equalf(t, nil, err, "error assertion error while re-running half applied migration")
+	assert.Equalf(t, migrationCount, appliedMigrationCount(t, conn), "applied count assertion error after re-running migration")
+
+	indexes := []struct {
+		Table, Name string
+	} {
+		{Table: model.StudentAuthInstance.TableName(), Name: model.StudentAuthInstance.TableName() + "_student_id"},
+		{Table: model.ParentChildrenInstance.TableName(), Name: model.ParentChildrenInstance.TableName() + "_student_number"},
+		{Table: model.TeacherInformInstance.TableName(), Name: model.TeacherInformInstance.TableName() + "_class"},
+	}
+	for _, index := range indexes {
+		assert.Truef(t, sqliteIndexExists(t, conn, index.Table, index.Name), "index existence assertion error after re-running migration (index: %v)", index)
+	}
+
+	// unique keys added in re-run migration must be removed in rolling back
+	if err = db.MigrateDown(conn, migrationCount - 8); err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range indexes {
+		assert.Falsef(t, sqliteIndexExists(t, conn, index.Table, index.Name), "index existence assertion error after rolling back (index: %v)", index)
+	}
+}
+
+// HasIndex of gorm dialect doesn't find index created with quoted name in SQLite, so sqlite_master is searched with name
+func sqliteIndexExists(t *testing.T, conn *gorm.DB, table, index string) bool {
+	var count int
+	if err := conn.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND name = ?", table, index).Row().Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
@@ -2,17 +2,15 @@ package test
 
 import (
 	"auth/model"
-	"fmt"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
-	"log"
 	"testing"
 )
 
 func Test_Accessor_GetStudentAuthWithID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -34,7 +32,7 @@ func Test_Accessor_GetStudentAuthWithID(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -55,7 +53,7 @@ func Test_Accessor_GetStudentAuthWithID(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -96,7 +94,7 @@ func Test_Accessor_GetStudentAuthWithID(t *testing.T) {
 func Test_Accessor_GetTeacherAuthWithID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -118,7 +116,7 @@ func Test_Accessor_GetTeacherAuthWithID(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -156,7 +154,7 @@ func Test_Accessor_GetTeacherAuthWithID(t *testing.T) {
 func Test_Accessor_GetParentAuthWithID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -178,7 +176,7 @@ func Test_Accessor_GetParentAuthWithID(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -216,7 +214,7 @@ func Test_Accessor_GetParentAuthWithID(t *testing.T) {
 func Test_Accessor_GetStudentAuthWithUUID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -238,7 +236,7 @@ func Test_Accessor_GetStudentAuthWithUUID(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -259,7 +257,7 @@ func Test_Accessor_GetStudentAuthWithUUID(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -300,7 +298,7 @@ func Test_Accessor_GetStudentAuthWithUUID(t *testing.T) {
 func Test_Accessor_GetTeacherAuthWithUUID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -322,7 +320,7 @@ func Test_Accessor_GetTeacherAuthWithUUID(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -360,7 +358,7 @@ func Test_Accessor_GetTeacherAuthWithUUID(t *testing.T) {
 func Test_Accessor_GetParentAuthWithUUID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -382,7 +380,7 @@ func Test_Accessor_GetParentAuthWithUUID(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -420,7 +418,7 @@ func Test_Accessor_GetParentAuthWithUUID(t *testing.T) {
 func Test_Accessor_GetStudentUUIDsWithInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -451,7 +449,7 @@ func Test_Accessor_GetStudentUUIDsWithInform(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -483,7 +481,7 @@ func Test_Accessor_GetStudentUUIDsWithInform(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -529,7 +527,7 @@ func Test_Accessor_GetStudentUUIDsWithInform(t *testing.T) {
 			ProfileURI:    model.ProfileURI(init.ProfileURI),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student inform, err: %v", err))
+			t.Fatalf("error occurs while creating student inform, err: %v", err)
 		}
 	}
 
@@ -574,7 +572,7 @@ func Test_Accessor_GetStudentUUIDsWithInform(t *testing.T) {
 func Test_Accessor_GetTeacherUUIDsWithInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -605,7 +603,7 @@ func Test_Accessor_GetTeacherUUIDsWithInform(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -623,7 +621,7 @@ func Test_Accessor_GetTeacherUUIDsWithInform(t *testing.T) {
 		}, {
 			TeacherUUID: "teacher-222222222222",
 			Grade:       2,
-			Class:       2,
+			Class:       3,
 			Name:        "윤석준",
 			PhoneNumber: "01022222222",
 		}, {
@@ -640,7 +638,7 @@ func Test_Accessor_GetTeacherUUIDsWithInform(t *testing.T) {
 			PhoneNumber: model.PhoneNumber(init.PhoneNumber),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student inform, err: %v", err))
+			t.Fatalf("error occurs while creating student inform, err: %v", err)
 		}
 	}
 
@@ -656,9 +654,13 @@ func Test_Accessor_GetTeacherUUIDsWithInform(t *testing.T) {
 			ExpectError:   nil,
 		}, {
 			Grade:         2,
-			Class:         2,
 			ExpectUUIDArr: []string{"teacher-111111111111", "teacher-222222222222"},
 			ExpectError:   nil,
+		}, {
+			Grade:         2,
+			Class:         2,
+			ExpectUUIDArr: []string{"teacher-111111111111"},
+			ExpectError:   nil,
 		}, {
 			Grade:         2,
 			Class:         2,
@@ -694,7 +696,7 @@ func Test_Accessor_GetTeacherUUIDsWithInform(t *testing.T) {
 func Test_Accessor_GetParentUUIDsWithInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -725,7 +727,7 @@ func Test_Accessor_GetParentUUIDsWithInform(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -753,7 +755,7 @@ func Test_Accessor_GetParentUUIDsWithInform(t *testing.T) {
 			PhoneNumber: model.PhoneNumber(init.PhoneNumber),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student inform, err: %v", err))
+			t.Fatalf("error occurs while creating student inform, err: %v", err)
 		}
 	}
 
@@ -798,7 +800,7 @@ func Test_Accessor_GetParentUUIDsWithInform(t *testing.T) {
 func Test_Accessor_GetStudentInformWithUUID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -821,7 +823,7 @@ func Test_Accessor_GetStudentInformWithUUID(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -843,7 +845,7 @@ func Test_Accessor_GetStudentInformWithUUID(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -873,7 +875,7 @@ func Test_Accessor_GetStudentInformWithUUID(t *testing.T) {
 			ProfileURI:    model.ProfileURI(init.ProfileURI),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student inform, err: %v", err))
+			t.Fatalf("error occurs while creating student inform, err: %v", err)
 		}
 	}
 
@@ -910,6 +912,9 @@ func Test_Accessor_GetStudentInformWithUUID(t *testing.T) {
 			PhoneNumber:   model.PhoneNumber(test.PhoneNumber),
 			ProfileURI:    model.ProfileURI(test.ProfileURI),
 		}
+		if test.ExpectError == nil {
+			expectResult.ParentStatus = defaultParentStatus
+		}
 		result, err := access.GetStudentInformWithUUID(test.StudentUUIDForArgs)
 
 		assert.Equalf(t, test.ExpectError, err, "error assertion error (test case: %v)", test)
@@ -920,7 +925,7 @@ func Test_Accessor_GetStudentInformWithUUID(t *testing.T) {
 func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -951,7 +956,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -983,7 +988,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -1029,7 +1034,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 			ProfileURI:    model.ProfileURI(init.ProfileURI),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student inform, err: %v", err))
+			t.Fatalf("error occurs while creating student inform, err: %v", err)
 		}
 	}
 
@@ -1052,6 +1057,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 					Name:          "박진홍",
 					PhoneNumber:   "01011111111",
 					ProfileURI:    "example.com/profiles/student-111111111111",
+					ParentStatus:  defaultParentStatus,
 				}, {
 					StudentUUID:   "student-222222222222",
 					Grade:         1,
@@ -1060,6 +1066,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 					Name:          "진홍박",
 					PhoneNumber:   "01022222222",
 					ProfileURI:    "example.com/profiles/student-222222222222",
+					ParentStatus:  defaultParentStatus,
 				},
 			},
 			ExpectError: nil,
@@ -1074,6 +1081,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 					Name:          "진홍박",
 					PhoneNumber:   "01022222222",
 					ProfileURI:    "example.com/profiles/student-222222222222",
+					ParentStatus:  defaultParentStatus,
 				}, { }, {
 					StudentUUID:   "student-111111111111",
 					Grade:         2,
@@ -1082,6 +1090,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 					Name:          "박진홍",
 					PhoneNumber:   "01011111111",
 					ProfileURI:    "example.com/profiles/student-111111111111",
+					ParentStatus:  defaultParentStatus,
 				}, {
 					StudentUUID:   "student-222222222222",
 					Grade:         1,
@@ -1090,6 +1099,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 					Name:          "진홍박",
 					PhoneNumber:   "01022222222",
 					ProfileURI:    "example.com/profiles/student-222222222222",
+					ParentStatus:  defaultParentStatus,
 				},
 			},
 			ExpectError: gorm.ErrRecordNotFound,
@@ -1115,7 +1125,7 @@ func Test_Accessor_GetStudentInformsWithUUIDs(t *testing.T) {
 func Test_Accessor_GetTeacherInformWithUUID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -1138,7 +1148,7 @@ func Test_Accessor_GetTeacherInformWithUUID(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -1164,7 +1174,7 @@ func Test_Accessor_GetTeacherInformWithUUID(t *testing.T) {
 			PhoneNumber: model.PhoneNumber(init.PhoneNumber),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher inform, err: %v", err))
+			t.Fatalf("error occurs while creating teacher inform, err: %v", err)
 		}
 	}
 
@@ -1207,7 +1217,7 @@ func Test_Accessor_GetTeacherInformWithUUID(t *testing.T) {
 func Test_Accessor_GetParentInformWithUUID(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		access.Rollback()
@@ -1230,7 +1240,7 @@ func Test_Accessor_GetParentInformWithUUID(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -1250,7 +1260,7 @@ func Test_Accessor_GetParentInformWithUUID(t *testing.T) {
 			PhoneNumber: model.PhoneNumber(init.PhoneNumber),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent inform, err: %v", err))
+			t.Fatalf("error occurs while creating parent inform, err: %v", err)
 		}
 	}
 
@@ -4,16 +4,16 @@ import (
 	"auth/db/access/errors"
 	"auth/model"
 	"auth/tool/mysqlerr"
-	"fmt"
+	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
-	"log"
 	"testing"
+	"time"
 )
 
 func Test_Access_ModifyStudentInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -44,7 +44,7 @@ func Test_Access_ModifyStudentInform(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -76,7 +76,7 @@ func Test_Access_ModifyStudentInform(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -122,7 +122,7 @@ func Test_Access_ModifyStudentInform(t *testing.T) {
 			ProfileURI:    model.ProfileURI(init.ProfileURI),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student inform, err: %v", err))
+			t.Fatalf("error occurs while creating student inform, err: %v", err)
 		}
 	}
 
@@ -148,8 +148,8 @@ func Test_Access_ModifyStudentInform(t *testing.T) {
 			StudentUUIDForArgs: "student-333333333333",
 			Grade:              2,
 			Class:              2,
-			StudentNumber:      14,
-			ExpectError:        mysqlerr.DuplicateEntry(model.StudentInformInstance.StudentNumber.KeyName(), "2214"),
+			StudentNumber:      12,
+			ExpectError:        mysqlerr.DuplicateEntry(model.StudentInformInstance.StudentNumber.KeyName(), "2-2-12"),
 		}, { // student number duplicate error
 			StudentUUIDForArgs: "student-333333333333",
 			PhoneNumber:        "01011111111",
@@ -229,6 +229,9 @@ func Test_Access_ModifyStudentInform(t *testing.T) {
 			PhoneNumber:   model.PhoneNumber(test.PhoneNumber),
 			ProfileURI:    model.ProfileURI(test.ProfileURI),
 		}
+		if test.ExpectError == nil {
+			expectResult.ParentStatus = defaultParentStatus
+		}
 		resultInform, err := access.GetStudentInformWithUUID(test.StudentUUIDArgs)
 
 
@@ -240,7 +243,7 @@ func Test_Access_ModifyStudentInform(t *testing.T) {
 func Test_Access_ModifyTeacherInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -271,7 +274,7 @@ func Test_Access_ModifyTeacherInform(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -309,7 +312,7 @@ func Test_Access_ModifyTeacherInform(t *testing.T) {
 			PhoneNumber:   model.PhoneNumber(init.PhoneNumber),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher inform, err: %v", err))
+			t.Fatalf("error occurs while creating teacher inform, err: %v", err)
 		}
 	}
 
@@ -320,10 +323,10 @@ func Test_Access_ModifyTeacherInform(t *testing.T) {
 		Name, PhoneNumber  string
 		ExpectError        error
 	} {
-		{ // success case 1 (about int64 field) (class duplicate allow)
+		{ // success case 1 (about int64 field)
 			TeacherUUIDForArgs: "teacher-111111111111",
 			Grade:              1,
-			Class:              2,
+			Class:              3,
 			ExpectError:        nil,
 		}, { // success case 2 (about string field)
 			TeacherUUIDForArgs: "teacher-222222222222",
@@ -335,6 +338,11 @@ func Test_Access_ModifyTeacherInform(t *testing.T) {
 			Grade:              model.TeacherInformInstance.Grade.NullReplaceValue(),
 			Class:              model.TeacherInformInstance.Class.NullReplaceValue(),
 			ExpectError:        nil,
+		}, { // class duplicate error
+			TeacherUUIDForArgs: "teacher-333333333333",
+			Grade:              1,
+			Class:              3,
+			ExpectError:        mysqlerr.DuplicateEntry(model.TeacherInformInstance.Class.KeyName(), "1-3"),
 		}, { // phone number duplicate error
 			TeacherUUIDForArgs: "teacher-333333333333",
 			PhoneNumber:        "01011111111",
@@ -374,7 +382,7 @@ func Test_Access_ModifyTeacherInform(t *testing.T) {
 			TeacherUUIDArgs: "teacher-111111111111",
 			TeacherUUID:     "teacher-111111111111",
 			Grade:           1,
-			Class:           2,
+			Class:           3,
 			Name:            "박진홍",
 			PhoneNumber:     "01011111111",
 			ExpectError:     nil,
@@ -413,7 +421,7 @@ func Test_Access_ModifyTeacherInform(t *testing.T) {
 func Test_Access_ModifyParentInform(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -440,7 +448,7 @@ func Test_Access_ModifyParentInform(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -464,7 +472,7 @@ func Test_Access_ModifyParentInform(t *testing.T) {
 			PhoneNumber: model.PhoneNumber(init.PhoneNumber),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent inform, err: %v", err))
+			t.Fatalf("error occurs while creating parent inform, err: %v", err)
 		}
 	}
 
@@ -540,7 +548,7 @@ func Test_Access_ModifyParentInform(t *testing.T) {
 func Test_Access_ChangeStudentPW(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -563,7 +571,7 @@ func Test_Access_ChangeStudentPW(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating parent auth, err: %v", err))
+			t.Fatalf("error occurs while creating parent auth, err: %v", err)
 		}
 	}
 
@@ -585,7 +593,7 @@ func Test_Access_ChangeStudentPW(t *testing.T) {
 			ParentUUID: model.ParentUUID(init.ParentUUID),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating student auth, err: %v", err))
+			t.Fatalf("error occurs while creating student auth, err: %v", err)
 		}
 	}
 
@@ -641,7 +649,7 @@ func Test_Access_ChangeStudentPW(t *testing.T) {
 func Test_Access_ChangeTeacherPW(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -664,7 +672,7 @@ func Test_Access_ChangeTeacherPW(t *testing.T) {
 			TeacherPW: model.TeacherPW(init.TeacherPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -718,7 +726,7 @@ func Test_Access_ChangeTeacherPW(t *testing.T) {
 func Test_Access_ChangeParentPW(t *testing.T) {
 	access, err := manager.BeginTx()
 	if err != nil {
-		log.Fatal(err)
+		t.Fatal(err)
 	}
 	defer func() {
 		_ = access.Rollback()
@@ -741,7 +749,7 @@ func Test_Access_ChangeParentPW(t *testing.T) {
 			ParentPW: model.ParentPW(init.ParentPW),
 		})
 		if err != nil {
-			log.Fatal(fmt.Sprintf("error occurs while creating teacher auth, err: %v", err))
+			t.Fatalf("error occurs while creating teacher auth, err: %v", err)
 		}
 	}
 
@@ -792,4 +800,140 @@ func Test_Access_ChangeParentPW(t *testing.T) {
 	}
 }
 
-// CheckIfStudentExistsWithUUID 추가 필요
\ No newline at end of file
+// add in v.1.2.0
+func Test_Access_IncreaseLoginThrottle(t *testing.T) {
+	access, err := manager.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		access.Rollback()
+		waitForFinish.Done()
+	}()
+
+	const throttleKey = "student:jinhong0719"
+	for _, test := range []struct {
+		ResetBefore        time.Time
+		ExpectFailureCount int64
+	} {
+		{ // 첫 실패 -> 생성
+			ResetBefore:        time.Now().Add(-time.Hour),
+			ExpectFailureCount: 1,
+		}, {
+			ResetBefore:        time.Now().Add(-time.Hour),
+			ExpectFailureCount: 2,
+		}, { // 마지막 실패가 초기화 기준 이전 -> 1로 초기화
+			ResetBefore:        time.Now().Add(time.Hour),
+			ExpectFailureCount: 1,
+		},
+	} {
+		throttle, err := access.IncreaseLoginThrottle(throttleKey, test.ResetBefore)
+		assert.Equalf(t, nil, err, "error assertion error (test case: %v)", test)
+		assert.Equalf(t, test.ExpectFailureCount, throttle.FailureCount, "failure count assertion error (test case: %v)", test)
+	}
+
+	lockedUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+	err = access.ModifyLoginThrottleLockedUntil(throttleKey, &lockedUntil)
+	assert.Equalf(t, nil, err, "error assertion error while locking throttle")
+	throttle, err := access.GetLoginThrottleWithKey(throttleKey)
+	assert.Equalf(t, nil, err, "error assertion error while getting locked throttle")
+	assert.Truef(t, throttle.LockedUntil != nil && throttle.LockedUntil.Equal(lockedUntil), "locked until assertion error (locked until: %v)", throttle.LockedUntil)
+}
+
+// failures of concurrent requests are counted in their own tx committed, so that no failure is lost (add in v.1.2.0)
+func Test_Access_IncreaseLoginThrottleConcurrently(t *testing.T) {
+	defer waitForFinish.Done()
+
+	const throttleKey = "source:127.0.0.1"
+	const numberOfFailures = 5
+	defer func() {
+		access, err := manager.BeginTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = access.DeleteLoginThrottle(throttleKey); err != nil {
+			access.Rollback()
+			t.Fatal(err)
+		}
+		access.Commit()
+	}()
+
+	counts := make(chan int64, numberOfFailures)
+	errs := make(chan error, numberOfFailures)
+	for i := 0; i < numberOfFailures; i++ {
+		go func() {
+			access, err := manager.BeginTx()
+			if err != nil {
+				errs <- err
+				return
+			}
+			// lock is checked before counting failure in login, as in checkLoginThrottle of handler
+			if _, err = access.GetLoginThrottleWithKey(throttleKey); err != nil && err != gorm.ErrRecordNotFound {
+				access.Rollback()
+				errs <- err
+				return
+			}
+			throttle, err := access.IncreaseLoginThrottle(throttleKey, time.Now().Add(-time.Hour))
+			if err != nil {
+				access.Rollback()
+				errs <- err
+				return
+			}
+			if err = access.Commit().Error; err != nil {
+				errs <- err
+				return
+			}
+			counts <- throttle.FailureCount
+		}()
+	}
+
+	var storedCounts []int64
+	for i := 0; i < numberOfFailures; i++ {
+		select {
+		case count := <-counts:
+			storedCounts = append(storedCounts, count)
+		case err := <-errs:
+			t.Errorf("error occurs while increasing login throttle concurrently, err: %v", err)
+		}
+	}
+	assert.ElementsMatchf(t, []int64{1, 2, 3, 4, 5}, storedCounts, "stored failure counts assertion error")
+}
+
+func Test_Access_IncreasePasswordResetAttemptCount(t *testing.T) {
+	access, err := manager.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		access.Rollback()
+		waitForFinish.Done()
+	}()
+
+	const maxAttemptCount = 3
+	if _, err = access.CreatePasswordReset(&model.PasswordReset{
+		OwnerUUID:  model.OwnerUUID("student-111111111111"),
+		CodeHash:   model.CodeHash("5994471abb01112afcc18159f6cc74b4f511b99806da59b3caf5a9c173cacfc5"),
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastSentAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		OwnerUUID     string
+		ExpectedError error
+	} {
+		{OwnerUUID: "student-111111111111"},
+		{OwnerUUID: "student-111111111111"},
+		{OwnerUUID: "student-111111111111"},
+		{OwnerUUID: "student-111111111111", ExpectedError: gorm.ErrRecordNotFound}, // 최대 횟수 도달
+		{OwnerUUID: "student-222222222222", ExpectedError: gorm.ErrRecordNotFound}, // 재설정 요청 없음
+	} {
+		err := access.IncreasePasswordResetAttemptCount(test.OwnerUUID, maxAttemptCount)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %v)", test)
+	}
+
+	reset, err := access.GetPasswordResetWithOwnerUUID("student-111111111111")
+	assert.Equalf(t, nil, err, "error assertion error while getting password reset")
+	assert.Equalf(t, int64(maxAttemptCount), reset.AttemptCount, "attempt count assertion error")
+}
@@ -18,6 +18,8 @@ require (
 	github.com/google/uuid v1.1.1
 	github.com/hashicorp/consul/api v1.6.0
 	github.com/jinzhu/gorm v1.9.16
+	github.com/lib/pq v1.3.0
+	github.com/mattn/go-sqlite3 v1.14.0
 	github.com/micro/go-micro/v2 v2.9.1
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/stretchr/testify v1.4.0
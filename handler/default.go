@@ -3,16 +3,18 @@ package handler
 import (
 	"auth/consul"
 	"auth/db"
+	"auth/tool/message"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/opentracing/opentracing-go"
 )
 
 type _default struct {
-	accessManage db.AccessorManage
-	tracer       opentracing.Tracer
-	awsSession   *session.Session
-	consulAgent  consul.Agent
+	accessManage  db.AccessorManage
+	tracer        opentracing.Tracer
+	awsSession    *session.Session
+	consulAgent   consul.Agent
+	messageSender message.Sender // add in v.1.2.0
 }
 
 // function signature used in subscriber (add in v.1.1.6)
@@ -55,3 +57,10 @@ func ConsulAgent(a consul.Agent) FieldSetter {
 		h.consulAgent = a
 	}
 }
+
+// add in v.1.2.0
+func MessageSender(s message.Sender) FieldSetter {
+	return func(h *_default) {
+		h.messageSender = s
+	}
+}
@@ -0,0 +1,68 @@
+// add file in v.1.2.0
+// this file declare method that record result of authentication RPC (Login*, Change*PW) in auth_logs table
+// log is stored with tx separated from tx of RPC, so that failed attempt is recorded even if tx of RPC is rolled back
+
+package handler
+
+import (
+	"auth/model"
+	"context"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+)
+
+const (
+	authLogOutcomeSuccess = "success"
+	authLogOutcomeFailure = "failure"
+)
+
+// information of authentication attempt to be recorded, response fields are read when RPC returns
+type authAttempt struct {
+	action      string // RPC name
+	accountType role
+	accountID   string
+	ownerUUID   string
+}
+
+// method that store authentication attempt with status and code of response in auth log
+// it must be called with defer after context is parsed from metadata, ex) defer func() { h.recordAuthLog(ctx, attempt, resp.Status, resp.Code) }()
+// failure of recording is only logged in span, because it must not change result of RPC
+func (h _default) recordAuthLog(ctx context.Context, attempt authAttempt, status uint32, _code int32) {
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+	userAgent, _ := ctx.Value("UserAgent").(string)
+	sourceIP, _ := ctx.Value("SourceIP").(string)
+
+	outcome := authLogOutcomeFailure
+	if status >= 200 && status < 300 {
+		outcome = authLogOutcomeSuccess
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("CreateAuthLog", opentracing.ChildOf(parentSpan))
+	createdLog, err := access.CreateAuthLog(&model.AuthLog{
+		Action:      attempt.action,
+		AccountType: string(attempt.accountType),
+		AccountID:   truncate(attempt.accountID, 20),
+		OwnerUUID:   attempt.ownerUUID,
+		Outcome:     outcome,
+		Status:      status,
+		Code:        _code,
+		RequestID:   reqID,
+		SourceIP:    truncate(sourceIP, 45),
+		UserAgent:   truncate(userAgent, 500),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuthLog", createdLog), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		return
+	}
+	access.Commit()
+}
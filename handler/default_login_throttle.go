@@ -0,0 +1,161 @@
+// add file in v.1.2.0
+// this file declare method that count login failures of account and source of request, and lock them with exponential backoff
+// failure counts are stored in DB (login_throttles table), so lock survives restart and is shared between replicas
+
+package handler
+
+import (
+	"auth/db"
+	code "auth/utils/code/golang"
+	"context"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"time"
+)
+
+const (
+	accountFailureLimit    = 5              // 계정 별 잠금 없이 허용되는 연속 로그인 실패 횟수
+	sourceFailureLimit     = 20             // 요청 출처(IP) 별 잠금 없이 허용되는 연속 로그인 실패 횟수
+	lockBaseDuration       = time.Minute    // 허용 횟수 초과 시 최초 잠금 시간, 이후 실패할 때 마다 두 배로 증가
+	lockMaxDuration        = time.Hour * 24 // 최대 잠금 시간
+	failureCountExpiration = time.Hour * 24 // 마지막 실패 후 이 시간이 지나면 실패 횟수 초기화
+)
+
+func accountThrottleKey(accountType, id string) string { return fmt.Sprintf("%s:%s", accountType, id) }
+func sourceThrottleKey(sourceIP string) string         { return fmt.Sprintf("source:%s", sourceIP) }
+
+// function that return lock duration doubled for every failure over limit
+func lockDurationOf(failureCount, limit int64) (duration time.Duration) {
+	if failureCount < limit {
+		return
+	}
+	duration = lockBaseDuration
+	for i := limit; i < failureCount && duration < lockMaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > lockMaxDuration {
+		duration = lockMaxDuration
+	}
+	return
+}
+
+type throttleTarget struct {
+	key   string
+	limit int64
+}
+
+// function that return throttle target of account and source of request (if source is forwarded by gateway in metadata)
+func throttleTargetsOf(ctx context.Context, accountKey string) (targets []throttleTarget) {
+	targets = append(targets, throttleTarget{key: accountKey, limit: accountFailureLimit})
+	if sourceIP, ok := ctx.Value("SourceIP").(string); ok && sourceIP != "" {
+		targets = append(targets, throttleTarget{key: sourceThrottleKey(sourceIP), limit: sourceFailureLimit})
+	}
+	return
+}
+
+// method that check if account or source of request is locked for login
+// it returns status, code and message to set in response if login is not allowed
+func (h _default) checkLoginThrottle(ctx context.Context, access db.Accessor, accountKey string) (allowed bool, status uint32, _code int32, message string) {
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	for _, target := range throttleTargetsOf(ctx, accountKey) {
+		spanForDB := h.tracer.StartSpan("GetLoginThrottleWithKey", opentracing.ChildOf(parentSpan))
+		throttle, err := access.GetLoginThrottleWithKey(target.key)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedThrottle", throttle), log.Error(err))
+		spanForDB.Finish()
+
+		switch err {
+		case nil:
+			break
+		case gorm.ErrRecordNotFound:
+			continue
+		default:
+			status = http.StatusInternalServerError
+			message = fmt.Sprintf(internalServerErrorFormat, "unable to query login throttle, err: "+err.Error())
+			return
+		}
+
+		if throttle.LockedUntil == nil || !time.Now().Before(*throttle.LockedUntil) {
+			continue
+		}
+
+		if target.key == accountKey {
+			status = http.StatusLocked
+			_code = code.AccountLockedForLogin
+			message = fmt.Sprintf(lockedMessageFormat, "too many login failures, account is locked until "+throttle.LockedUntil.Format(time.RFC3339))
+		} else {
+			status = http.StatusTooManyRequests
+			_code = code.TooManyLoginFailures
+			message = fmt.Sprintf(lockedMessageFormat, "too many login failures, source is locked until "+throttle.LockedUntil.Format(time.RFC3339))
+		}
+		return
+	}
+
+	allowed = true
+	return
+}
+
+// method that increase failure count of account and source of request, and lock them if count exceeds limit
+// it ends tx by committing, because failure count must be stored even if login fails (rollback if unable to store)
+func (h _default) commitLoginFailure(ctx context.Context, access db.Accessor, accountKey string) {
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	for _, target := range throttleTargetsOf(ctx, accountKey) {
+		if err := h.increaseLoginFailure(access, target, parentSpan, reqID); err != nil {
+			access.Rollback()
+			return
+		}
+	}
+
+	access.Commit()
+	return
+}
+
+// method that increase failure count of throttle target, and lock it with the count actually stored if count exceeds limit
+// count is increased atomically in DB, so that failures of concurrent requests are all counted (row is created if it is first failure)
+func (h _default) increaseLoginFailure(access db.Accessor, target throttleTarget, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	now := time.Now()
+	spanForDB := h.tracer.StartSpan("IncreaseLoginThrottle", opentracing.ChildOf(parentSpan))
+	throttle, err := access.IncreaseLoginThrottle(target.key, now.Add(-failureCountExpiration))
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("IncreasedThrottle", throttle), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		return
+	}
+
+	var lockedUntil *time.Time
+	if duration := lockDurationOf(throttle.FailureCount, target.limit); duration != 0 {
+		until := now.Add(duration)
+		lockedUntil = &until
+	}
+
+	// row is locked by increasing count until tx ends, so lock time is not overwritten by concurrent failure
+	spanForDB = h.tracer.StartSpan("ModifyLoginThrottleLockedUntil", opentracing.ChildOf(parentSpan))
+	err = access.ModifyLoginThrottleLockedUntil(target.key, lockedUntil)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("LockedUntil", lockedUntil), log.Error(err))
+	spanForDB.Finish()
+	return
+}
+
+// method that reset failure count of account after login succeed
+func (h _default) resetLoginFailure(ctx context.Context, access db.Accessor, accountKey string) (err error) {
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	spanForDB := h.tracer.StartSpan("DeleteLoginThrottle", opentracing.ChildOf(parentSpan))
+	err = access.DeleteLoginThrottle(accountKey)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = fmt.Errorf("unable to reset login failure count, err: %v", err)
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uber/jaeger-client-go"
+	"testing"
+	"time"
+)
+
+func Test_increaseLoginFailure(t *testing.T) {
+	const throttleKey = "student:jinhong0719"
+
+	tests := []struct {
+		IncreaseReturns test.Returns // returns of IncreaseLoginThrottle
+		ModifyReturns   test.Returns // returns of ModifyLoginThrottleLockedUntil, nil if not called
+		ExpectLocked    bool         // whether lock time passed to ModifyLoginThrottleLockedUntil is not nil
+		ExpectedError   error
+	}{
+		{ // first failure -> not locked
+			IncreaseReturns: test.Returns{&model.LoginThrottle{FailureCount: 1}, nil},
+			ModifyReturns:   test.Returns{nil},
+		}, { // failure under limit -> not locked
+			IncreaseReturns: test.Returns{&model.LoginThrottle{FailureCount: accountFailureLimit - 1}, nil},
+			ModifyReturns:   test.Returns{nil},
+		}, { // failure reaching limit with count stored by concurrent failures -> locked
+			IncreaseReturns: test.Returns{&model.LoginThrottle{FailureCount: accountFailureLimit}, nil},
+			ModifyReturns:   test.Returns{nil},
+			ExpectLocked:    true,
+		}, { // IncreaseLoginThrottle unexpected error -> return error without locking
+			IncreaseReturns: test.Returns{&model.LoginThrottle{}, errors.New("unexpected error")},
+			ExpectedError:   errors.New("unexpected error"),
+		}, { // ModifyLoginThrottleLockedUntil unexpected error
+			IncreaseReturns: test.Returns{&model.LoginThrottle{FailureCount: accountFailureLimit + 1}, nil},
+			ModifyReturns:   test.Returns{errors.New("unexpected error")},
+			ExpectLocked:    true,
+			ExpectedError:   errors.New("unexpected error"),
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		newMock.On("IncreaseLoginThrottle", throttleKey, mock.Anything).Return(testCase.IncreaseReturns...).Once()
+		if testCase.ModifyReturns != nil {
+			expectLocked := testCase.ExpectLocked
+			newMock.On("ModifyLoginThrottleLockedUntil", throttleKey, mock.MatchedBy(func(lockedUntil *time.Time) bool {
+				return (lockedUntil != nil) == expectLocked
+			})).Return(testCase.ModifyReturns...).Once()
+		}
+
+		newMock.On("BeginTx").Return()
+		access, _ := defaultHandler.accessManage.BeginTx()
+		target := throttleTarget{key: throttleKey, limit: accountFailureLimit}
+		err := defaultHandler.increaseLoginFailure(access, target, jaeger.SpanContext{}, "")
+
+		assert.Equalf(t, testCase.ExpectedError, err, "error assertion error (test case: %v)", testCase)
+		newMock.AssertExpectations(t)
+	}
+}
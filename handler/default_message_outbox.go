@@ -0,0 +1,189 @@
+// add file in v.1.2.0
+// this file declare method that queue message in outbox table and background worker that deliver queued message in _default struct
+// message is queued in tx of RPC and sent after commit, failed message is retried with exponential backoff until max attempt count
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	"fmt"
+	logger "github.com/micro/go-micro/v2/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"time"
+)
+
+// status of outbox message
+const (
+	outboxStatusPending = "pending"
+	outboxStatusSent    = "sent"
+	outboxStatusFailed  = "failed"
+)
+
+const (
+	outboxMaxAttemptCount = 5                // 최대 발송 시도 횟수, 초과 시 failed 상태로 변경
+	outboxRetryBaseDelay  = time.Second * 30 // 첫 재시도 전 대기 시간, 시도 마다 두 배로 증가
+	outboxRetryMaxDelay   = time.Hour
+	outboxSendingLease    = time.Minute * 5  // 발송 중인 메시지를 다른 작업이 가져가지 않는 시간, 작업이 중단되면 이 시간 후 재발송
+)
+
+// content of message is replaced with this after message is sent or failed, because it may have secret like auth code
+const outboxRedactedContent = "(redacted after delivery)"
+
+// function that return delay before next attempt after attemptCount times of failed attempt
+func outboxRetryDelay(attemptCount int64) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := int64(1); i < attemptCount && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMaxDelay {
+		delay = outboxRetryMaxDelay
+	}
+	return delay
+}
+
+// method that queue message to receiver in outbox with tx of RPC, message is sent by worker after tx is committed
+func (h _default) enqueueMessage(access db.Accessor, message *model.OutboxMessage, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	message.Status = outboxStatusPending
+	message.AttemptCount = 0
+	message.NextAttemptAt = time.Now()
+
+	spanForDB := h.tracer.StartSpan("CreateOutboxMessage", opentracing.ChildOf(parentSpan))
+	_, err = access.CreateOutboxMessage(message)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("BatchID", message.BatchID), log.String("Recipient", message.Recipient), log.Error(err))
+	spanForDB.Finish()
+	return
+}
+
+// method that return function starting worker which deliver due outbox messages in chunk at every interval
+// returned function is registered with micro.AfterStart, message is claimed with lock, so worker can run in every service node
+func (h _default) OutboxWorker(interval time.Duration, chunkSize int) func() error {
+	return func() error {
+		go func() {
+			for range time.Tick(interval) {
+				for {
+					claimedCount, err := h.deliverOutboxChunk(chunkSize)
+					if err != nil {
+						logger.Errorf("unable to deliver outbox messages, err: %v", err)
+						break
+					}
+					if claimedCount < chunkSize {
+						break
+					}
+				}
+			}
+		}()
+		return nil
+	}
+}
+
+// method that claim due messages up to chunk size and send them, it returns count of claimed messages
+func (h _default) deliverOutboxChunk(chunkSize int) (claimedCount int, err error) {
+	messages, err := h.claimDueOutboxMessages(chunkSize)
+	if err != nil {
+		return
+	}
+	claimedCount = len(messages)
+
+	var sentCount, retryCount, failedCount int
+	for _, message := range messages {
+		switch status, deliverErr := h.deliverOutboxMessage(message); {
+		case deliverErr != nil:
+			logger.Errorf("unable to record delivery result of outbox message, id: %d, err: %v", message.ID, deliverErr)
+		case status == outboxStatusSent:
+			sentCount++
+		case status == outboxStatusFailed:
+			failedCount++
+		default:
+			retryCount++
+		}
+	}
+
+	if claimedCount != 0 {
+		logger.Infof("deliver outbox messages!, sent: %d, retry: %d, failed: %d", sentCount, retryCount, failedCount)
+	}
+	return
+}
+
+// method that lock due messages and push back their next attempt time by sending lease, so that other worker doesn't send them together
+func (h _default) claimDueOutboxMessages(limit int) (messages []*model.OutboxMessage, err error) {
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	span := h.tracer.StartSpan("GetDueOutboxMessagesForUpdate")
+	messages, err = access.GetDueOutboxMessagesForUpdate(now, limit)
+	span.LogFields(log.Int("SelectedCount", len(messages)), log.Error(err))
+	span.Finish()
+
+	if err != nil {
+		access.Rollback()
+		return
+	}
+
+	for _, message := range messages {
+		message.AttemptCount++
+		message.NextAttemptAt = now.Add(outboxSendingLease)
+		if err = access.ModifyOutboxMessage(message.ID, message); err != nil {
+			access.Rollback()
+			return
+		}
+	}
+
+	access.Commit()
+	return
+}
+
+// method that send claimed message and record result of it, it returns status of message after delivery
+// message is sent one by one instead of SendMassToReceivers, because mass sending reports only count of failed receivers
+// and it can't be known which message must be retried
+func (h _default) deliverOutboxMessage(message *model.OutboxMessage) (status string, err error) {
+	span := h.tracer.StartSpan("SendToReceivers")
+	jsonResp, sendErr := h.messageSender.SendToReceivers([]string{message.Receiver}, message.Content, message.MessageType, message.Title)
+	span.LogFields(log.Uint64("OutboxMessageID", uint64(message.ID)), log.Object("JsonResponse", jsonResp), log.Error(sendErr))
+	span.Finish()
+
+	now := time.Now()
+	switch {
+	case sendErr == nil:
+		message.Status = outboxStatusSent
+		message.MsgID = jsonResp.MsgID
+		message.LastError = ""
+		message.SentAt = &now
+	case message.AttemptCount >= outboxMaxAttemptCount:
+		message.Status = outboxStatusFailed
+		message.LastError = truncate(sendErr.Error(), 500)
+	default:
+		message.Status = outboxStatusPending
+		message.NextAttemptAt = now.Add(outboxRetryDelay(message.AttemptCount))
+		message.LastError = truncate(sendErr.Error(), 500)
+	}
+	status = message.Status
+
+	// content isn't needed after delivery is finished, so it is not kept in outbox with auth code in it
+	if status == outboxStatusSent || status == outboxStatusFailed {
+		message.Content = outboxRedactedContent
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		return
+	}
+
+	span = h.tracer.StartSpan("ModifyOutboxMessage")
+	err = access.ModifyOutboxMessage(message.ID, message)
+	span.LogFields(log.String("Status", message.Status), log.Int64("AttemptCount", message.AttemptCount), log.Error(err))
+	span.Finish()
+
+	if err != nil {
+		access.Rollback()
+		err = fmt.Errorf("message is %s but unable to store it, err: %v", status, err)
+		return
+	}
+	access.Commit()
+	return
+}
@@ -0,0 +1,177 @@
+// add file in v.1.2.0
+// this file declare message templates of every SMS sent from this service and method that render them in _default struct
+// template body is written in text/template syntax, and latest version stored in message_templates table is used instead of built-in default
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	"bytes"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"text/template"
+)
+
+// names of message template
+const (
+	joinSMSTemplateName           = "join_sms"
+	passwordResetSMSTemplateName  = "password_reset_sms"
+	freshmanDMSNoticeTemplateName = "freshman_dms_notice"
+)
+
+// data of join SMS template, sent to unsigned student with auth code
+type joinSMSData struct {
+	SchoolName     string
+	Grade          int64
+	Class          int64
+	StudentNumber  int64
+	Name           string
+	AuthCode       int64
+	SignupDeadline string // ex: 3/11(목)
+}
+
+// data of password reset SMS template, sent to account owner with reset code
+type passwordResetSMSData struct {
+	ResetCode         string
+	ExpirationMinutes int
+}
+
+// data of freshman DMS notice template, sent to 1st grade student after signup
+type freshmanDMSNoticeData struct {
+	SchoolName string
+}
+
+// built-in template used until template is changed with ChangeMessageTemplate, and sample data used to preview and validate template
+type messageTemplateDefault struct {
+	template   model.MessageTemplate
+	sampleData interface{}
+}
+
+var messageTemplateDefaults = map[string]messageTemplateDefault{
+	joinSMSTemplateName: {
+		template: model.MessageTemplate{
+			Name:        joinSMSTemplateName,
+			MessageType: "LMS",
+			Title:       "DSM 학교 지원 시스템(SMS) 회원가입 안내",
+			Body: `
+[{{.SchoolName}}]
+
+학교 지원 시스템(SMS) 회원가입 안내 문자입니다. 아래 내용 필독 후 진행해주세요.
+
+[가입 대상: {{.Grade}}{{.Class}}{{printf "%02d" .StudentNumber}} {{.Name}}]
+[인증 번호: {{.AuthCode}}]
+
+Play 스토어 또는 앱스토어에서 'SMS 학교 지원 시스템' 앱 다운로드 후 진행해주세요.
+
+참고로 iOS는 현재 앱 심사중이라 사용이 불가능합니다. 등록 되는대로 재안내 드리겠습니다.
+
+모든 재학생분들(신입생 포함)은 {{.SignupDeadline}}까지 회원가입을 완료해주세요.
+
+페이스북 'DSM 기숙사 지원 시스템' 페이지를 팔로우하여 여러 정보를 받아보세요!
+
+* 해당 문자는 전공동아리 DMS에서 발신되었습니다.`,
+		},
+		sampleData: joinSMSData{
+			SchoolName: "대덕소프트웨어마이스터고등학교", Grade: 2, Class: 1, StudentNumber: 7, Name: "홍길동", AuthCode: 123456, SignupDeadline: "3/11(목)",
+		},
+	},
+	passwordResetSMSTemplateName: {
+		template: model.MessageTemplate{
+			Name:        passwordResetSMSTemplateName,
+			MessageType: "SMS",
+			Body:        "[DSM 학교 지원 시스템] 비밀번호 재설정 인증 번호는 [{{.ResetCode}}] 입니다. {{.ExpirationMinutes}}분 안에 입력해주세요.",
+		},
+		sampleData: passwordResetSMSData{ResetCode: "123456", ExpirationMinutes: 10},
+	},
+	freshmanDMSNoticeTemplateName: {
+		template: model.MessageTemplate{
+			Name:        freshmanDMSNoticeTemplateName,
+			MessageType: "LMS",
+			Title:       "DSM 신입생 대상 기숙사 지원 시스템(DMS) 안내 문자",
+			Body: `
+[{{.SchoolName}}]
+
+신입생 대상 기숙사 지원 시스템(DMS) 안내 문자입니다.
+
+앞서, 저희 학교 지원 시스템(SMS)에 가입해주셔서 감사합니다.
+
+저희는 이 외에도 'DMS'라는 기숙사 지원 시스템을 제공하여 현재 모든 재학생분들이 사용중입니다.
+
+여러분들의 빠른 회원가입을 위하여 SMS에서 입력하신 계정 정보로 DMS 계정을 발급하였습니다.
+
+Play 스토어 또는 App Store에서 'DMS - 기숙사 지원 시스템' 앱을 다운 받아 사용해보세요!
+
+PC 전용 웹 사이트 또한 제공중이니 많이 방문해주세요.
+https://www.dsm-dms.com
+
+* 해당 문자는 전공동아리 DMS에서 발신되었습니다.
+`,
+		},
+		sampleData: freshmanDMSNoticeData{SchoolName: "대덕소프트웨어마이스터고등학교"},
+	},
+}
+
+// message template with parsed body, version 0 means built-in default
+type messageTemplate struct {
+	model.MessageTemplate
+	parsed *template.Template
+}
+
+// function that parse body of template and check it renders sample data of template name without error
+func parseMessageTemplate(stored model.MessageTemplate) (parsed messageTemplate, err error) {
+	builtIn, exists := messageTemplateDefaults[stored.Name]
+	if !exists {
+		err = fmt.Errorf("unknown message template name, name: %s", stored.Name)
+		return
+	}
+	if stored.MessageType == "SMS" && stored.Title != "" {
+		err = fmt.Errorf("title can't be set in SMS message template")
+		return
+	}
+
+	parsed.MessageTemplate = stored
+	if parsed.parsed, err = template.New(stored.Name).Option("missingkey=error").Parse(stored.Body); err != nil {
+		return
+	}
+	_, err = parsed.render(builtIn.sampleData)
+	return
+}
+
+// method that return message rendered with data
+func (t messageTemplate) render(data interface{}) (content string, err error) {
+	buf := new(bytes.Buffer)
+	if err = t.parsed.Execute(buf, data); err != nil {
+		return
+	}
+	content = buf.String()
+	return
+}
+
+// method that return latest version of message template with name, built-in default is returned if template has never been changed
+func (h _default) messageTemplateWithName(access db.Accessor, name string, parentSpan jaeger.SpanContext, reqID string) (tpl messageTemplate, err error) {
+	spanForDB := h.tracer.StartSpan("GetLatestMessageTemplateWithName", opentracing.ChildOf(parentSpan))
+	stored, err := access.GetLatestMessageTemplateWithName(name)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("TemplateName", name), log.Object("SelectedTemplate", stored), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		return parseMessageTemplate(*stored)
+	case gorm.ErrRecordNotFound:
+		return parseMessageTemplate(messageTemplateDefaults[name].template)
+	}
+	return
+}
+
+// method that render latest version of message template with name and data
+func (h _default) renderMessage(access db.Accessor, name string, data interface{}, parentSpan jaeger.SpanContext, reqID string) (tpl messageTemplate, content string, err error) {
+	if tpl, err = h.messageTemplateWithName(access, name, parentSpan, reqID); err != nil {
+		return
+	}
+	content, err = tpl.render(data)
+	return
+}
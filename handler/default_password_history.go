@@ -0,0 +1,105 @@
+// add file in v.1.2.0
+// this file declare method that block reuse of recent passwords when password is changed
+// replaced password hashes are stored in DB (password_histories table) up to passwordHistoryDepth per account
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	"auth/tool/hash"
+	code "auth/utils/code/golang"
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+)
+
+// method that check if pw is same with current password or one of recent passwords stored in history
+// it returns status, code and message to set in response if pw is not allowed
+func (h _default) checkPasswordReuse(ctx context.Context, access db.Accessor, ownerUUID, currentHashedPW, pw string) (allowed bool, status uint32, _code int32, message string) {
+	if passwordHistoryDepth == 0 {
+		allowed = true
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	spanForDB := h.tracer.StartSpan("GetRecentPasswordHistoriesWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	histories, err := access.GetRecentPasswordHistoriesWithOwnerUUID(ownerUUID, passwordHistoryDepth)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int("HistoryCount", len(histories)), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "unable to query password history, err: " + err.Error())
+		return
+	}
+
+	hashedPWs := []string{currentHashedPW}
+	for _, history := range histories {
+		hashedPWs = append(hashedPWs, string(history.HashedPW))
+	}
+
+	spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
+	for _, hashedPW := range hashedPWs {
+		switch err = hash.CompareHashAndPassword(hashedPW, pw); err {
+		case nil:
+			spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Bool("Reused", true))
+			spanForHash.Finish()
+			status = http.StatusConflict
+			_code = code.ReusedPassword
+			message = fmt.Sprintf(conflictErrorFormat, fmt.Sprintf("password used recently, please use password different from last %d passwords", passwordHistoryDepth))
+			return
+		case hash.ErrMismatchedHashAndPassword:
+			continue
+		default:
+			spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+			spanForHash.Finish()
+			status = http.StatusInternalServerError
+			message = fmt.Sprintf(internalServerErrorFormat, "hash compare error, err: " + err.Error())
+			return
+		}
+	}
+	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Bool("Reused", false))
+	spanForHash.Finish()
+
+	allowed = true
+	return
+}
+
+// method that store hash of replaced password in history, and delete history over passwordHistoryDepth
+func (h _default) recordPasswordHistory(ctx context.Context, access db.Accessor, ownerUUID, replacedHashedPW string) (err error) {
+	if passwordHistoryDepth == 0 {
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	spanForDB := h.tracer.StartSpan("CreatePasswordHistory", opentracing.ChildOf(parentSpan))
+	_, err = access.CreatePasswordHistory(&model.PasswordHistory{
+		OwnerUUID: model.OwnerUUID(ownerUUID),
+		HashedPW:  model.HashedPW(replacedHashedPW),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = fmt.Errorf("unable to store password history, err: %v", err)
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("DeletePasswordHistoriesExceptRecent", opentracing.ChildOf(parentSpan))
+	err = access.DeletePasswordHistoriesExceptRecent(ownerUUID, passwordHistoryDepth)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = fmt.Errorf("unable to delete old password history, err: %v", err)
+	}
+	return
+}
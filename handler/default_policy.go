@@ -0,0 +1,213 @@
+// add file in v.1.2.0
+// this file declare role, permission and rule of each RPC in one place
+// every RPC that need authorization consult rule declared in this file with authorize method of _default struct
+// (login, auth code, token, password reset and TOTP login RPCs are excluded because they authenticate caller with their own credential)
+
+package handler
+
+import (
+	"auth/db"
+	"auth/tool/jwt"
+	"context"
+	"fmt"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+)
+
+type role string
+
+const (
+	adminRole   role = "admin"
+	studentRole role = "student"
+	teacherRole role = "teacher"
+	parentRole  role = "parent"
+)
+
+type permission string
+
+// permission with ":own" suffix allows caller to access only resource that caller own
+const (
+	createAccountPermission         permission = "account:create"
+	deleteAccountPermission         permission = "account:delete"
+	restoreAccountPermission        permission = "account:restore"
+	manageUnsignedStudentPermission permission = "unsigned_student:manage"
+	rolloverAcademicYearPermission  permission = "academic_year:rollover"
+	manageSchoolTermPermission      permission = "school_term:manage"
+	manageMessageTemplatePermission permission = "message_template:manage"
+	readInformPermission            permission = "inform:read"
+
+	updateOwnStudentPermission permission = "student:update:own"
+	updateAnyStudentPermission permission = "student:update:any"
+	updateOwnTeacherPermission permission = "teacher:update:own"
+	updateAnyTeacherPermission permission = "teacher:update:any"
+	updateOwnParentPermission  permission = "parent:update:own"
+	updateAnyParentPermission  permission = "parent:update:any"
+
+	readOwnStudentParentPermission permission = "student.parent:read:own"
+	readAnyStudentParentPermission permission = "student.parent:read:any"
+	readOwnParentChildrenPermission permission = "parent.children:read:own"
+	readAnyParentChildrenPermission permission = "parent.children:read:any"
+
+	readOwnSessionPermission   permission = "session:read:own"
+	revokeOwnSessionPermission permission = "session:revoke:own"
+	revokeAnySessionPermission permission = "session:revoke:any"
+
+	unlockAccountPermission       permission = "account:unlock"
+	readAuthLogPermission         permission = "auth_log:read"
+	readAuditEventPermission      permission = "audit_event:read"
+	readMessageDeliveryPermission permission = "message_delivery:read"
+
+	manageOwnTOTPPermission permission = "totp:manage:own"
+	manageAnyTOTPPermission permission = "totp:manage:any"
+)
+
+var rolePermissions = map[role][]permission{
+	adminRole: {
+		createAccountPermission, deleteAccountPermission, restoreAccountPermission, manageUnsignedStudentPermission, rolloverAcademicYearPermission,
+		manageSchoolTermPermission, manageMessageTemplatePermission, readInformPermission, updateAnyStudentPermission, updateAnyTeacherPermission, updateAnyParentPermission,
+		readAnyStudentParentPermission, readAnyParentChildrenPermission, readOwnSessionPermission, revokeAnySessionPermission,
+		unlockAccountPermission, readAuthLogPermission, readAuditEventPermission, readMessageDeliveryPermission, manageOwnTOTPPermission, manageAnyTOTPPermission,
+	},
+	studentRole: {readInformPermission, updateOwnStudentPermission, readOwnStudentParentPermission, readOwnSessionPermission, revokeOwnSessionPermission},
+	teacherRole: {readInformPermission, updateOwnTeacherPermission, readOwnSessionPermission, revokeOwnSessionPermission, manageOwnTOTPPermission},
+	parentRole:  {readInformPermission, updateOwnParentPermission, readOwnParentChildrenPermission, readOwnSessionPermission, revokeOwnSessionPermission},
+}
+
+// rule of RPC, caller must have permission in any or have permission in own and be owner of target
+type rule struct {
+	any permission
+	own permission
+}
+
+var rules = map[string]rule{
+	// About Admin RPC Service
+	"CreateNewStudent":                    {any: createAccountPermission},
+	"CreateNewTeacher":                    {any: createAccountPermission},
+	"CreateNewParent":                     {any: createAccountPermission},
+	"AddUnsignedStudents":                 {any: manageUnsignedStudentPermission},
+	"SendJoinSMSToUnsignedStudents":       {any: manageUnsignedStudentPermission},
+	"RegenerateUnsignedStudentAuthCodes":  {any: manageUnsignedStudentPermission},
+	"ChangeUnsignedStudentAuthCodeExpiry": {any: manageUnsignedStudentPermission},
+	"ResendJoinSMSToUnsignedStudent":      {any: manageUnsignedStudentPermission},
+	"UnlockAccount":                       {any: unlockAccountPermission},
+	"GetAuthLogs":                         {any: readAuthLogPermission},
+	"ListAuditEvents":                     {any: readAuditEventPermission},
+	"DeleteStudent":                       {any: deleteAccountPermission},
+	"DeleteTeacher":                       {any: deleteAccountPermission},
+	"DeleteParent":                        {any: deleteAccountPermission},
+	"RestoreStudent":                      {any: restoreAccountPermission},
+	"RestoreTeacher":                      {any: restoreAccountPermission},
+	"RestoreParent":                       {any: restoreAccountPermission},
+	"RolloverAcademicYear":                {any: rolloverAcademicYearPermission},
+	"GetSchoolTerm":                       {any: readInformPermission},
+	"ChangeSchoolTerm":                    {any: manageSchoolTermPermission},
+	"GetMessageTemplate":                  {any: manageMessageTemplatePermission},
+	"ChangeMessageTemplate":               {any: manageMessageTemplatePermission},
+	"PreviewMessageTemplate":              {any: manageMessageTemplatePermission},
+	"GetMessageDeliveryStatus":            {any: readMessageDeliveryPermission},
+
+	// About Student RPC Service
+	"ChangeStudentPW":            {any: updateAnyStudentPermission, own: updateOwnStudentPermission},
+	"GetStudentInformWithUUID":   {any: readInformPermission},
+	"GetParentWithStudentUUID":   {any: readAnyStudentParentPermission, own: readOwnStudentParentPermission},
+	"GetStudentInformsWithUUIDs": {any: readInformPermission},
+	"GetStudentUUIDsWithInform":  {any: readInformPermission},
+
+	// About Teacher RPC Service
+	"ChangeTeacherPW":           {any: updateAnyTeacherPermission, own: updateOwnTeacherPermission},
+	"GetTeacherInformWithUUID":  {any: readInformPermission},
+	"GetTeacherUUIDsWithInform": {any: readInformPermission},
+	"ChangeTeacherInform":       {any: updateAnyTeacherPermission, own: updateOwnTeacherPermission},
+
+	// About Parent RPC Service
+	"ChangeParentPW":             {any: updateAnyParentPermission, own: updateOwnParentPermission},
+	"GetParentInformWithUUID":    {any: readInformPermission},
+	"GetParentUUIDsWithInform":   {any: readInformPermission},
+	"GetChildrenInformsWithUUID": {any: readAnyParentChildrenPermission, own: readOwnParentChildrenPermission},
+
+	// About Token RPC Service
+	"RevokeAllSessions": {any: revokeAnySessionPermission, own: revokeOwnSessionPermission},
+
+	// About Session RPC Service
+	"ListMySessions": {own: readOwnSessionPermission},
+	"RevokeSession":  {any: revokeAnySessionPermission, own: revokeOwnSessionPermission},
+
+	// About TOTP RPC Service
+	"EnrollTOTP":  {own: manageOwnTOTPPermission},
+	"VerifyTOTP":  {own: manageOwnTOTPPermission},
+	"DisableTOTP": {any: manageAnyTOTPPermission, own: manageOwnTOTPPermission},
+}
+
+// identity of caller derived from verified access token
+type identity struct {
+	UUID      string
+	Role      role
+	SessionID string
+}
+
+func identityFrom(claims jwt.Claims) identity {
+	return identity{UUID: claims.Subject, Role: role(claims.Role), SessionID: claims.SessionID}
+}
+
+// function that return identity of caller with access token in context
+// it must be called after caller is authorized with authorize method, so token is not verified again
+func callerOf(ctx context.Context) identity {
+	token, _ := ctx.Value("AccessToken").(string)
+	claims, _ := jwt.ParseWithHS256(token, []byte(jwtSecretKey))
+	return identityFrom(claims)
+}
+
+func (i identity) has(p permission) bool {
+	for _, permitted := range rolePermissions[i.Role] {
+		if p != "" && permitted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// targetUUID is uuid of account that RPC access, pass empty string if RPC doesn't access specific account
+func (r rule) allows(caller identity, targetUUID string) bool {
+	if caller.has(r.any) {
+		return true
+	}
+	return caller.has(r.own) && targetUUID != "" && targetUUID == caller.UUID
+}
+
+// method that authenticate caller with access token in metadata and authorize caller with rule of rpc
+// it returns status, code and message to set in response if caller is not authorized
+func (h _default) authorize(ctx context.Context, access db.Accessor, rpc, targetUUID string) (authorized bool, status uint32, _code int32, message string) {
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	token, ok := ctx.Value("AccessToken").(string)
+	if !ok || token == "" {
+		status = http.StatusUnauthorized
+		message = fmt.Sprintf(unauthorizedMessageFormat, "access token not exists in Authorization metadata")
+		return
+	}
+
+	claims, err := h.verifyAccessToken(access, token, parentSpan, reqID)
+	if err != nil {
+		status, _code, message = statusForTokenError(err)
+		return
+	}
+
+	r, ok := rules[rpc]
+	if !ok {
+		status = http.StatusForbidden
+		message = fmt.Sprintf(forbiddenMessageFormat, "rule of " + rpc + " is not declared")
+		return
+	}
+
+	if caller := identityFrom(claims); !r.allows(caller, targetUUID) {
+		status = http.StatusForbidden
+		message = fmt.Sprintf(forbiddenMessageFormat, fmt.Sprintf("%s(%s) is not permitted to call %s", caller.Role, caller.UUID, rpc))
+		return
+	}
+
+	// writes of business data in this tx are recorded as audit event of caller
+	access.SetAuditContext(claims.Subject, rpc)
+	authorized = true
+	return
+}
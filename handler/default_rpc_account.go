@@ -0,0 +1,362 @@
+// add file in v.1.2.0
+// this file declare method that handling RPC about deletion and restore of account (Delete*, Restore* in AuthAdmin service) in _default struct
+// account is soft deleted with auth, inform and parent children links in one tx, and purged permanently by purge job after retention window
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	"auth/tool/dberr"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"context"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	logger "github.com/micro/go-micro/v2/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"time"
+)
+
+// step of account deletion or restore run in one tx, each step is traced with span of its name
+type accountStep struct {
+	name string
+	run  func() error
+}
+
+func (h _default) runAccountSteps(steps []accountStep, uuid string, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	for _, step := range steps {
+		spanForDB := h.tracer.StartSpan(step.name, opentracing.ChildOf(parentSpan))
+		err = step.run()
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("AccountUUID", uuid), log.Error(err))
+		spanForDB.Finish()
+
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// method that soft delete auth, inform and parent children links of account and revoke every token of the account
+// it returns gorm.ErrRecordNotFound if account not exists or already deleted
+func (h _default) deleteAccount(access db.Accessor, accountType role, uuid string, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	var steps []accountStep
+	switch accountType {
+	case studentRole:
+		steps = []accountStep{
+			{"GetStudentAuthWithUUID", func() (err error) { _, err = access.GetStudentAuthWithUUID(uuid); return }},
+			{"DeleteStudentAuth", func() error { return access.DeleteStudentAuth(uuid) }},
+			{"DeleteStudentInform", func() error { return access.DeleteStudentInform(uuid) }},
+			{"DeleteParentChildrenWithStudentUUID", func() error { return access.DeleteParentChildrenWithStudentUUID(uuid) }},
+		}
+	case teacherRole:
+		steps = []accountStep{
+			{"GetTeacherAuthWithUUID", func() (err error) { _, err = access.GetTeacherAuthWithUUID(uuid); return }},
+			{"DeleteTeacherAuth", func() error { return access.DeleteTeacherAuth(uuid) }},
+			{"DeleteTeacherInform", func() error { return access.DeleteTeacherInform(uuid) }},
+		}
+	case parentRole:
+		steps = []accountStep{
+			{"GetParentAuthWithUUID", func() (err error) { _, err = access.GetParentAuthWithUUID(uuid); return }},
+			{"DeleteParentAuth", func() error { return access.DeleteParentAuth(uuid) }},
+			{"DeleteParentInform", func() error { return access.DeleteParentInform(uuid) }},
+			{"DeleteParentChildrenWithParentUUID", func() error { return access.DeleteParentChildrenWithParentUUID(uuid) }},
+		}
+	}
+
+	// access tokens issued before deletion are rejected, so that deleted account can't keep using service
+	steps = append(steps,
+		accountStep{"CreateSessionRevocation", func() (err error) {
+			_, err = access.CreateSessionRevocation(&model.SessionRevocation{OwnerUUID: model.OwnerUUID(uuid), RevokedBefore: time.Now()})
+			return
+		}},
+		accountStep{"DeleteRefreshTokensWithOwnerUUID", func() error { return access.DeleteRefreshTokensWithOwnerUUID(uuid) }},
+		accountStep{"DeleteSessionsWithOwnerUUID", func() error { return access.DeleteSessionsWithOwnerUUID(uuid) }},
+	)
+
+	err = h.runAccountSteps(steps, uuid, parentSpan, reqID)
+	return
+}
+
+// method that restore soft deleted account with parent children links deleted together
+// it returns gorm.ErrRecordNotFound if deleted account not exists
+// id or inform of account can be taken by other account while the account is deleted, because they are unique only in
+// not deleted rows, so that is reported as duplicate error of DB from Restore* method instead of checked before restoring
+func (h _default) restoreAccount(access db.Accessor, accountType role, uuid string, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	var deletedAt time.Time
+
+	var steps []accountStep
+	switch accountType {
+	case studentRole:
+		steps = []accountStep{
+			{"GetDeletedStudentAuthWithUUID", func() (err error) {
+				auth, err := access.GetDeletedStudentAuthWithUUID(uuid)
+				if err == nil { deletedAt = *auth.DeletedAt }
+				return
+			}},
+			{"RestoreStudentAuth", func() error { return access.RestoreStudentAuth(uuid) }},
+			{"RestoreStudentInform", func() error { return access.RestoreStudentInform(uuid) }},
+			{"RestoreParentChildrenWithStudentUUID", func() error { return access.RestoreParentChildrenWithStudentUUID(uuid, deletedAt) }},
+		}
+	case teacherRole:
+		steps = []accountStep{
+			{"GetDeletedTeacherAuthWithUUID", func() (err error) { _, err = access.GetDeletedTeacherAuthWithUUID(uuid); return }},
+			{"RestoreTeacherAuth", func() error { return access.RestoreTeacherAuth(uuid) }},
+			{"RestoreTeacherInform", func() error { return access.RestoreTeacherInform(uuid) }},
+		}
+	case parentRole:
+		steps = []accountStep{
+			{"GetDeletedParentAuthWithUUID", func() (err error) {
+				auth, err := access.GetDeletedParentAuthWithUUID(uuid)
+				if err == nil { deletedAt = *auth.DeletedAt }
+				return
+			}},
+			{"RestoreParentAuth", func() error { return access.RestoreParentAuth(uuid) }},
+			{"RestoreParentInform", func() error { return access.RestoreParentInform(uuid) }},
+			{"RestoreParentChildrenWithParentUUID", func() error { return access.RestoreParentChildrenWithParentUUID(uuid, deletedAt) }},
+		}
+	}
+
+	err = h.runAccountSteps(steps, uuid, parentSpan, reqID)
+	return
+}
+
+// function that return code meaning key of restored account is duplicated with other account, or zero if key is unexpected
+// key name of phone number is same in every inform table, so code is decided with account type together
+func duplicateCodeOf(accountType role, key string) (_code int32) {
+	switch accountType {
+	case studentRole:
+		switch key {
+		case model.StudentAuthInstance.StudentID.KeyName():
+			_code = code.StudentIDDuplicate
+		case model.StudentInformInstance.StudentNumber.KeyName():
+			_code = code.StudentNumberDuplicate
+		case model.StudentInformInstance.PhoneNumber.KeyName():
+			_code = code.StudentPhoneNumberDuplicate
+		}
+	case teacherRole:
+		switch key {
+		case model.TeacherAuthInstance.TeacherID.KeyName():
+			_code = code.TeacherIDDuplicate
+		case model.TeacherInformInstance.Class.KeyName():
+			_code = code.TeacherClassDuplicate
+		case model.TeacherInformInstance.PhoneNumber.KeyName():
+			_code = code.TeacherPhoneNumberDuplicate
+		}
+	case parentRole:
+		switch key {
+		case model.ParentAuthInstance.ParentID.KeyName():
+			_code = code.ParentIDDuplicate
+		case model.ParentInformInstance.PhoneNumber.KeyName():
+			_code = code.ParentPhoneNumberDuplicate
+		}
+	}
+	return
+}
+
+// method that handle Delete* RPC, only account type is different between them
+func (h _default) handleAccountDeletion(ctx context.Context, rpc string, accountType role, uuid string) (status uint32, _code int32, message string) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		status = http.StatusProxyAuthRequired
+		message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	if uuid == "" {
+		status = http.StatusProxyAuthRequired
+		message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "uuid of account to delete is empty")
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	var authorized bool
+	if authorized, status, _code, message = h.authorize(ctx, access, rpc, ""); !authorized {
+		access.Rollback()
+		return
+	}
+
+	switch err = h.deleteAccount(access, accountType, uuid, parentSpan, reqID); err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		access.Rollback()
+		status = http.StatusNotFound
+		message = fmt.Sprintf(notFoundMessageFormat, string(accountType) + " account not exists or already deleted")
+		return
+	default:
+		access.Rollback()
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "unable to delete account, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	status = http.StatusOK
+	message = fmt.Sprintf("succeed to delete %s account", accountType)
+	return
+}
+
+// method that handle Restore* RPC, only account type is different between them
+func (h _default) handleAccountRestore(ctx context.Context, rpc string, accountType role, uuid string) (status uint32, _code int32, message string) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		status = http.StatusProxyAuthRequired
+		message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	if uuid == "" {
+		status = http.StatusProxyAuthRequired
+		message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "uuid of account to restore is empty")
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	var authorized bool
+	if authorized, status, _code, message = h.authorize(ctx, access, rpc, ""); !authorized {
+		access.Rollback()
+		return
+	}
+
+	err = h.restoreAccount(access, accountType, uuid, parentSpan, reqID)
+	switch assertedError := dberr.From(err).(type) {
+	case nil:
+		break
+	case *dberr.Error:
+		access.Rollback()
+		switch assertedError.Category {
+		case dberr.NotFound:
+			status = http.StatusNotFound
+			message = fmt.Sprintf(notFoundMessageFormat, "deleted " + string(accountType) + " account not exists or already purged")
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
+			if _code = duplicateCodeOf(accountType, key); _code == 0 {
+				status = http.StatusInternalServerError
+				message = fmt.Sprintf(internalServerErrorFormat, "unexpected duplicate error, key: " + key)
+				return
+			}
+			status = http.StatusConflict
+			message = fmt.Sprintf(conflictErrorFormat, key + " of deleted account is already used by other account, entry: " + entry)
+		default:
+			status = http.StatusInternalServerError
+			message = fmt.Sprintf(internalServerErrorFormat, "unable to restore account, err: " + err.Error())
+		}
+		return
+	default:
+		access.Rollback()
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "unable to restore account, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	status = http.StatusOK
+	message = fmt.Sprintf("succeed to restore %s account", accountType)
+	return
+}
+
+func (h _default) DeleteStudent(ctx context.Context, req *proto.DeleteStudentRequest, resp *proto.DeleteStudentResponse) (_ error) {
+	resp.Status, resp.Code, resp.Message = h.handleAccountDeletion(ctx, "DeleteStudent", studentRole, req.StudentUUID)
+	return
+}
+
+func (h _default) DeleteTeacher(ctx context.Context, req *proto.DeleteTeacherRequest, resp *proto.DeleteTeacherResponse) (_ error) {
+	resp.Status, resp.Code, resp.Message = h.handleAccountDeletion(ctx, "DeleteTeacher", teacherRole, req.TeacherUUID)
+	return
+}
+
+func (h _default) DeleteParent(ctx context.Context, req *proto.DeleteParentRequest, resp *proto.DeleteParentResponse) (_ error) {
+	resp.Status, resp.Code, resp.Message = h.handleAccountDeletion(ctx, "DeleteParent", parentRole, req.ParentUUID)
+	return
+}
+
+func (h _default) RestoreStudent(ctx context.Context, req *proto.RestoreStudentRequest, resp *proto.RestoreStudentResponse) (_ error) {
+	resp.Status, resp.Code, resp.Message = h.handleAccountRestore(ctx, "RestoreStudent", studentRole, req.StudentUUID)
+	return
+}
+
+func (h _default) RestoreTeacher(ctx context.Context, req *proto.RestoreTeacherRequest, resp *proto.RestoreTeacherResponse) (_ error) {
+	resp.Status, resp.Code, resp.Message = h.handleAccountRestore(ctx, "RestoreTeacher", teacherRole, req.TeacherUUID)
+	return
+}
+
+func (h _default) RestoreParent(ctx context.Context, req *proto.RestoreParentRequest, resp *proto.RestoreParentResponse) (_ error) {
+	resp.Status, resp.Code, resp.Message = h.handleAccountRestore(ctx, "RestoreParent", parentRole, req.ParentUUID)
+	return
+}
+
+// name of DB lock held by node running purge job
+const purgeLockName = "auth.PurgeDeletedAccounts"
+
+// method that return function starting job which purge accounts deleted before retention window at every interval
+// returned function is registered with micro.AfterStart in every service node, but purge is run by only one node at a time
+// with lock of DB, so that nodes don't purge the same accounts concurrently and record duplicated audit events
+func (h _default) AccountPurger(retention, interval time.Duration) func() error {
+	return func() error {
+		go func() {
+			for range time.Tick(interval) {
+				purged, purgedCount, err := h.purgeDeletedAccounts(retention)
+				if err != nil {
+					logger.Errorf("unable to purge deleted accounts, err: %v", err)
+					continue
+				}
+				if !purged {
+					logger.Infof("skip purging deleted accounts, other node is purging them")
+					continue
+				}
+				logger.Infof("purge deleted accounts!, purged count: %d", purgedCount)
+			}
+		}()
+		return nil
+	}
+}
+
+// method that purge accounts deleted before retention window, purged is false if other node is purging with lock
+func (h _default) purgeDeletedAccounts(retention time.Duration) (purged bool, purgedCount int64, err error) {
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		return
+	}
+
+	if purged, err = access.TryLockUntilTxEnd(purgeLockName); err != nil || !purged {
+		access.Rollback()
+		return
+	}
+
+	// purge is run by job of service, not by account, so only name of job is recorded in audit event
+	access.SetAuditContext("", "PurgeDeletedAccounts")
+	span := h.tracer.StartSpan("PurgeDeletedAccounts")
+	purgedCount, err = access.PurgeDeletedAccounts(time.Now().Add(-retention))
+	span.LogFields(log.Int64("PurgedCount", purgedCount), log.Error(err))
+	span.Finish()
+
+	if err != nil {
+		access.Rollback()
+		return
+	}
+	access.Commit()
+	return
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	"auth/tool/mysqlerr"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_default_DeleteStudent(t *testing.T) {
+	tests := []test.DeleteStudentCase{
+		{ // success case
+			StudentUUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{UUID: "student-111111111111"}, nil},
+				"DeleteStudentAuth":                     {nil},
+				"DeleteStudentInform":                   {nil},
+				"DeleteParentChildrenWithStudentUUID":   {nil},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // no exist student uuid -> Proxy Authorization Required
+			StudentUUID:     test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // student not exists or already deleted -> not found
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		}, { // DeleteParentChildrenWithStudentUUID unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{}, nil},
+				"DeleteStudentAuth":                     {nil},
+				"DeleteStudentInform":                   {nil},
+				"DeleteParentChildrenWithStudentUUID":   {errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.DeleteStudentRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.DeleteStudentResponse)
+		_ = defaultHandler.DeleteStudent(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_RestoreParent(t *testing.T) {
+	tests := []test.RestoreParentCase{
+		{ // success case
+			ParentUUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetDeletedParentAuthWithUUID":          {}, // return deleted auth of test case
+				"RestoreParentAuth":                     {nil},
+				"RestoreParentInform":                   {nil},
+				"RestoreParentChildrenWithParentUUID":   {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist Span-Context -> Proxy Authorization Required
+			SpanContextString: test.EmptyReplaceValueForString,
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "parent-111111111112",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // deleted parent not exists -> not found
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetDeletedParentAuthWithUUID":          {&model.ParentAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		}, { // parent id is used by other account while deleted -> conflict
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetDeletedParentAuthWithUUID":          {},
+				"RestoreParentAuth":                     {mysqlerr.DuplicateEntry(model.ParentAuthInstance.ParentID.KeyName(), "jinhong0719")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.ParentIDDuplicate,
+		}, { // parent phone number is used by other account while deleted -> conflict
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetDeletedParentAuthWithUUID":          {},
+				"RestoreParentAuth":                     {nil},
+				"RestoreParentInform":                   {mysqlerr.DuplicateEntry(model.ParentInformInstance.PhoneNumber.KeyName(), "01088378347")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.ParentPhoneNumberDuplicate,
+		}, { // unexpected duplicate key -> internal server error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetDeletedParentAuthWithUUID":          {},
+				"RestoreParentAuth":                     {nil},
+				"RestoreParentInform":                   {nil},
+				"RestoreParentChildrenWithParentUUID":   {mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // RestoreParentInform unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetDeletedParentAuthWithUUID":          {},
+				"RestoreParentAuth":                     {nil},
+				"RestoreParentInform":                   {errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.RestoreParentRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.RestoreParentResponse)
+		_ = defaultHandler.RestoreParent(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_purgeDeletedAccounts(t *testing.T) {
+	tests := []struct {
+		LockReturns       test.Returns // returns of TryLockUntilTxEnd
+		PurgeReturns      test.Returns // returns of PurgeDeletedAccounts, nil if not called
+		EndTx             string       // method ending tx, Commit or Rollback
+		ExpectPurged      bool
+		ExpectPurgedCount int64
+		ExpectedError     error
+	}{
+		{ // success case
+			LockReturns:       test.Returns{true, nil},
+			PurgeReturns:      test.Returns{int64(3), nil},
+			EndTx:             "Commit",
+			ExpectPurged:      true,
+			ExpectPurgedCount: 3,
+		}, { // lock held by other node -> skip purge
+			LockReturns: test.Returns{false, nil},
+			EndTx:       "Rollback",
+		}, { // TryLockUntilTxEnd unexpected error
+			LockReturns:   test.Returns{false, errors.New("unexpected error")},
+			EndTx:         "Rollback",
+			ExpectedError: errors.New("unexpected error"),
+		}, { // PurgeDeletedAccounts unexpected error
+			LockReturns:   test.Returns{true, nil},
+			PurgeReturns:  test.Returns{int64(0), errors.New("unexpected error")},
+			EndTx:         "Rollback",
+			ExpectPurged:  true,
+			ExpectedError: errors.New("unexpected error"),
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		newMock.On("BeginTx").Return()
+		newMock.On("TryLockUntilTxEnd", purgeLockName).Return(testCase.LockReturns...).Once()
+		if testCase.PurgeReturns != nil {
+			newMock.On("PurgeDeletedAccounts", mock.Anything).Return(testCase.PurgeReturns...).Once()
+		}
+		newMock.On(testCase.EndTx).Return(&gorm.DB{}).Once()
+
+		purged, purgedCount, err := defaultHandler.purgeDeletedAccounts(time.Hour)
+
+		assert.Equalf(t, testCase.ExpectedError, err, "error assertion error (test case: %v)", testCase)
+		assert.Equalf(t, testCase.ExpectPurged, purged, "purged assertion error (test case: %v)", testCase)
+		assert.Equalf(t, testCase.ExpectPurgedCount, purgedCount, "purged count assertion error (test case: %v)", testCase)
+		newMock.AssertExpectations(t)
+	}
+}
@@ -3,25 +3,22 @@ package handler
 import (
 	"auth/model"
 	proto "auth/proto/golang/auth"
-	"auth/tool/message"
-	"auth/tool/mysqlerr"
-	"auth/tool/random"
+	"auth/tool/dberr"
+	"auth/tool/hash"
 	code "auth/utils/code/golang"
 	"bytes"
 	"context"
 	"fmt"
-	mysqlcode "github.com/VividCortex/mysqlerr"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/go-playground/validator/v10"
-	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/uber/jaeger-client-go"
-	"golang.org/x/crypto/bcrypt"
-	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -33,12 +30,6 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 		return
 	}
 
-	if !adminUUIDRegex.MatchString(req.UUID) {
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "you are not admin")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -49,31 +40,20 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 		return
 	}
 
-	sUUID, ok := ctx.Value("StudentUUID").(string)
-	if !ok || sUUID == "" {
-		sUUID = fmt.Sprintf("student-%s", random.StringConsistOfIntWithLength(12))
+	if authorized, status, _code, message := h.authorize(ctx, access, "CreateNewStudent", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
 	}
 
-	for {
-		spanForDB := h.tracer.StartSpan("GetStudentAuthWithUUID", opentracing.ChildOf(parentSpan))
-		selectedAuth, err := access.GetStudentAuthWithUUID(sUUID)
-		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("selectedAuth", selectedAuth), log.Error(err))
-		spanForDB.Finish()
-		if err == gorm.ErrRecordNotFound {
-			break
-		}
-		if err != nil {
-			access.Rollback()
-			resp.Status = http.StatusInternalServerError
-			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
-			return
-		}
-		sUUID = fmt.Sprintf("student-%s", random.StringConsistOfIntWithLength(12))
-		continue
+	if strong, status, _code, message := checkPasswordStrength(req.StudentPW, req.StudentID); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
 	}
 
 	spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.StudentPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.StudentPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -84,17 +64,21 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 		return
 	}
 
-	spanForDB := h.tracer.StartSpan("CreateStudentAuth", opentracing.ChildOf(parentSpan))
-	resultAuth, err := access.CreateStudentAuth(&model.StudentAuth{
-		UUID:       model.UUID(sUUID),
-		StudentID:  model.StudentID(req.StudentID),
-		StudentPW:  model.StudentPW(string(hashedBytes)),
-		ParentUUID: model.ParentUUID(req.ParentUUID),
+	var resultAuth *model.StudentAuth
+	sUUID, err := writeWithUniqueID(accountUUIDGenerator("student", ctx.Value("StudentUUID")), accountUUIDKeys, func(sUUID string) (err error) {
+		spanForDB := h.tracer.StartSpan("CreateStudentAuth", opentracing.ChildOf(parentSpan))
+		resultAuth, err = access.CreateStudentAuth(&model.StudentAuth{
+			UUID:       model.UUID(sUUID),
+			StudentID:  model.StudentID(req.StudentID),
+			StudentPW:  model.StudentPW(hashedPW),
+			ParentUUID: model.ParentUUID(req.ParentUUID),
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", resultAuth), log.Error(err))
+		spanForDB.Finish()
+		return
 	})
-	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", resultAuth), log.Error(err))
-	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -102,16 +86,11 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for student auth model, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.StudentAuthInstance.StudentID.KeyName():
 				resp.Status = http.StatusConflict
@@ -122,21 +101,15 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected duplicate error, key: " + key)
 			}
 			return
-		case mysqlcode.ER_NO_REFERENCED_ROW_2:
-			fkInform, _, err := mysqlerr.ParseFKConstraintFailErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse fk contraint error, err: " + err.Error())
-				return
-			}
-			switch fkInform.ConstraintName {
+		case dberr.FKViolation:
+			switch assertedError.Constraint {
 			case model.StudentAuthInstance.ParentUUIDConstraintName():
 				resp.Status = http.StatusConflict
 				resp.Code = code.ParentUUIDNoExist
-				resp.Message = fmt.Sprintf(conflictErrorFormat, "FK constraint fail, FK name: " + fkInform.AttrName)
+				resp.Message = fmt.Sprintf(conflictErrorFormat, "FK constraint fail, FK name: " + assertedError.Column)
 			default:
 				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected FK constraint fail, FK name: " + fkInform.AttrName)
+				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected FK constraint fail, FK name: " + assertedError.Column)
 			}
 			return
 		default:
@@ -159,7 +132,7 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 	}
 
 	profileURI := fmt.Sprintf("profiles/uuids/%s", string(resultAuth.UUID))
-	spanForDB = h.tracer.StartSpan("CreateStudentInform", opentracing.ChildOf(parentSpan))
+	spanForDB := h.tracer.StartSpan("CreateStudentInform", opentracing.ChildOf(parentSpan))
 	studentInform := &model.StudentInform{
 		StudentUUID:   model.StudentUUID(string(resultAuth.UUID)),
 		Grade:         model.Grade(int64(req.Grade)),
@@ -178,7 +151,7 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedInform", resultInform), log.Error(err))
 	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -186,16 +159,11 @@ func (h _default) CreateNewStudent(ctx context.Context, req *proto.CreateNewStud
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for student inform, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.StudentInformInstance.StudentNumber.KeyName():
 				resp.Status = http.StatusConflict
@@ -256,12 +224,6 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		return
 	}
 
-	if !adminUUIDRegex.MatchString(req.UUID) {
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "you are not admin")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -272,27 +234,20 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		return
 	}
 
-	var pUUID string
-	for {
-		pUUID = fmt.Sprintf("parent-%s", random.StringConsistOfIntWithLength(12))
-		spanForDB := h.tracer.StartSpan("GetParentAuthWithUUID", opentracing.ChildOf(parentSpan))
-		selectedAuth, err := access.GetParentAuthWithUUID(pUUID)
-		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("selectedAuth", selectedAuth), log.Error(err))
-		spanForDB.Finish()
-		if err == gorm.ErrRecordNotFound {
-			break
-		}
-		if err != nil {
-			access.Rollback()
-			resp.Status = http.StatusInternalServerError
-			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
-			return
-		}
-		continue
+	if authorized, status, _code, message := h.authorize(ctx, access, "CreateNewParent", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	if strong, status, _code, message := checkPasswordStrength(req.ParentPW, req.ParentID); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
 	}
 
 	spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.ParentPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.ParentPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -303,16 +258,20 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		return
 	}
 
-	spanForDB := h.tracer.StartSpan("CreateParentAuth", opentracing.ChildOf(parentSpan))
-	resultAuth, err := access.CreateParentAuth(&model.ParentAuth{
-		UUID:     model.UUID(pUUID),
-		ParentID: model.ParentID(req.ParentID),
-		ParentPW: model.ParentPW(string(hashedBytes)),
+	var resultAuth *model.ParentAuth
+	pUUID, err := writeWithUniqueID(accountUUIDGenerator("parent", ctx.Value("ParentUUID")), accountUUIDKeys, func(pUUID string) (err error) {
+		spanForDB := h.tracer.StartSpan("CreateParentAuth", opentracing.ChildOf(parentSpan))
+		resultAuth, err = access.CreateParentAuth(&model.ParentAuth{
+			UUID:     model.UUID(pUUID),
+			ParentID: model.ParentID(req.ParentID),
+			ParentPW: model.ParentPW(hashedPW),
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", resultAuth), log.Error(err))
+		spanForDB.Finish()
+		return
 	})
-	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", resultAuth), log.Error(err))
-	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -320,16 +279,11 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for teacher auth model, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.ParentAuthInstance.ParentID.KeyName():
 				resp.Status = http.StatusConflict
@@ -352,7 +306,7 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		return
 	}
 
-	spanForDB = h.tracer.StartSpan("CreateParentInform", opentracing.ChildOf(parentSpan))
+	spanForDB := h.tracer.StartSpan("CreateParentInform", opentracing.ChildOf(parentSpan))
 	resultInform, err := access.CreateParentInform(&model.ParentInform{
 		ParentUUID:  model.ParentUUID(string(resultAuth.UUID)),
 		Name:        model.Name(req.Name),
@@ -361,7 +315,7 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedInform", resultInform), log.Error(err))
 	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -369,16 +323,11 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for teacher inform, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.ParentInformInstance.PhoneNumber.KeyName():
 				resp.Status = http.StatusConflict
@@ -429,7 +378,7 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("ResultChild", resultChild), log.Error(err))
 		spanForDB.Finish()
 
-		switch assertedError := err.(type) {
+		switch assertedError := dberr.From(err).(type) {
 		case nil:
 			break
 		case validator.ValidationErrors:
@@ -437,10 +386,10 @@ func (h _default) CreateNewParent(ctx context.Context, req *proto.CreateNewParen
 			resp.Status = http.StatusProxyAuthRequired
 			resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for parent children, err: " + err.Error())
 			return
-		case *mysql.MySQLError:
+		case *dberr.Error:
 			access.Rollback()
 			resp.Status = http.StatusConflict
-			resp.Message = fmt.Sprintf(conflictErrorFormat, "mysql error occurs in CreateParentChildren, err: " + err.Error())
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "DB error occurs in CreateParentChildren, err: " + err.Error())
 			return
 		default:
 			access.Rollback()
@@ -497,6 +446,11 @@ func (h _default) LoginAdminAuth(ctx context.Context, req *proto.LoginAdminAuthR
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "LoginAdminAuth", accountType: adminRole, accountID: req.AdminID, ownerUUID: resp.LoggedInAdminUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -504,19 +458,27 @@ func (h _default) LoginAdminAuth(ctx context.Context, req *proto.LoginAdminAuthR
 		return
 	}
 
+	accountKey := accountThrottleKey(string(adminRole), req.AdminID)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, accountKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetAdminAuthWithID", opentracing.ChildOf(parentSpan))
 	resultAuth, err := access.GetAdminAuthWithID(req.AdminID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", resultAuth), log.Error(err))
 	spanForDB.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
 		case gorm.ErrRecordNotFound:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.AdminIDNoExist
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "admin id not exists")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " +err.Error())
 		}
@@ -524,28 +486,78 @@ func (h _default) LoginAdminAuth(ctx context.Context, req *proto.LoginAdminAuthR
 	}
 
 	spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
-	err = bcrypt.CompareHashAndPassword([]byte(resultAuth.AdminPW), []byte(req.AdminPW))
+	err = hash.CompareHashAndPassword(string(resultAuth.AdminPW), req.AdminPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
-		case bcrypt.ErrMismatchedHashAndPassword:
+		case hash.ErrMismatchedHashAndPassword:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.IncorrectAdminPWForLogin
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "hash compare error, err: " + err.Error())
 		}
 		return
 	}
 
+	err = h.rehashPWIfNeeded(access.ChangeAdminPW, string(resultAuth.UUID), string(resultAuth.AdminPW), req.AdminPW, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	if err = h.resetLoginFailure(ctx, access, accountKey); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	// login of account with enabled TOTP is finished in LoginWithTOTP with token issued here (add in v.1.2.0)
+	totpEnabled, err := h.totpEnabled(access, string(resultAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+	if totpEnabled {
+		totpToken, err := issueTOTPToken(string(resultAuth.UUID))
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+		access.Commit()
+		resp.Status = http.StatusAccepted
+		resp.Code = code.TOTPRequired
+		resp.Message = "password is correct, please finish login with totp code"
+		resp.TOTPToken = totpToken
+		return
+	}
+
+	accessToken, refreshToken, err := h.startSession(ctx, access, string(resultAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "succeed to login admin auth"
 	resp.LoggedInAdminUUID = string(resultAuth.UUID)
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
 
 	return
 }
@@ -558,11 +570,8 @@ func (h _default) AddUnsignedStudents(ctx context.Context, req *proto.AddUnsigne
 		return
 	}
 
-	if !adminUUIDRegex.MatchString(req.UUID) {
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "you are not admin")
-		return
-	}
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
@@ -571,13 +580,27 @@ func (h _default) AddUnsignedStudents(ctx context.Context, req *proto.AddUnsigne
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "AddUnsignedStudents", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	term, status, _code, message := h.currentSchoolTerm(access, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	svc := s3.New(h.awsSession)
+	authCodeExpiresAt := authCodeExpiryOf(term, time.Now())
 	var addCount uint32 = 0
 	var noAddCount uint32 = 0
 	var duplicateLog string
 
 	for _, student := range req.Students {
-		preProfileUri := fmt.Sprintf("profiles/years/2021/grades/%d/groups/%d/numbers/%d", student.Grade, student.Group, student.StudentNumber)
+		preProfileUri := preProfileURIOf(term, int64(student.Grade), int64(student.Group), int64(student.StudentNumber))
 		_, err := svc.HeadObject(&s3.HeadObjectInput{
 			Bucket: aws.String(s3Bucket),
 			Key:    aws.String(preProfileUri),
@@ -589,22 +612,25 @@ func (h _default) AddUnsignedStudents(ctx context.Context, req *proto.AddUnsigne
 			return
 		}
 
-		rand.Seed(time.Now().UnixNano())
-		min := 100000
-		max := 999999
-		authCode := rand.Intn(max - min + 1) + min
-
-		_, err = access.AddUnsignedStudent(&model.UnsignedStudent{
-			AuthCode:      model.AuthCode(int64(authCode)),
-			Grade:         model.Grade(int64(student.Grade)),
-			Class:         model.Class(int64(student.Group)),
-			StudentNumber: model.StudentNumber(int64(student.StudentNumber)),
-			Name:          model.Name(student.Name),
-			PhoneNumber:   model.PhoneNumber(student.PhoneNumber),
-			PreProfileURI: model.PreProfileURI(preProfileUri),
+		_, err = writeWithUniqueID(generateAuthCode, authCodeKeys, func(authCode string) (err error) {
+			parsedAuthCode, _ := strconv.ParseInt(authCode, 10, 64)
+			spanForDB := h.tracer.StartSpan("AddUnsignedStudent", opentracing.ChildOf(parentSpan))
+			_, err = access.AddUnsignedStudent(&model.UnsignedStudent{
+				AuthCode:          model.AuthCode(parsedAuthCode),
+				Grade:             model.Grade(int64(student.Grade)),
+				Class:             model.Class(int64(student.Group)),
+				StudentNumber:     model.StudentNumber(int64(student.StudentNumber)),
+				Name:              model.Name(student.Name),
+				PhoneNumber:       model.PhoneNumber(student.PhoneNumber),
+				PreProfileURI:     model.PreProfileURI(preProfileUri),
+				AuthCodeExpiresAt: &authCodeExpiresAt,
+			})
+			spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("PreProfileURI", preProfileUri), log.Error(err))
+			spanForDB.Finish()
+			return
 		})
 
-		switch assertedError := err.(type) {
+		switch assertedError := dberr.From(err).(type) {
 		case nil:
 			addCount++
 			continue
@@ -613,9 +639,9 @@ func (h _default) AddUnsignedStudents(ctx context.Context, req *proto.AddUnsigne
 			resp.Status = http.StatusProxyAuthRequired
 			resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for unsigned students, err: " + err.Error())
 			return
-		case *mysql.MySQLError:
-			switch assertedError.Number {
-			case mysqlcode.ER_DUP_ENTRY:
+		case *dberr.Error:
+			switch assertedError.Category {
+			case dberr.Duplicate:
 				noAddCount++
 				duplicateLog += fmt.Sprintf("\nuri: %s, name: %s, duplicate err: %v", preProfileUri, student.Name, assertedError)
 				continue
@@ -649,12 +675,6 @@ func (h _default) SendJoinSMSToUnsignedStudents(ctx context.Context, req *proto.
 		return
 	}
 
-	if !adminUUIDRegex.MatchString(req.UUID) {
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "you are not admin")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -665,6 +685,19 @@ func (h _default) SendJoinSMSToUnsignedStudents(ctx context.Context, req *proto.
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "SendJoinSMSToUnsignedStudents", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	term, status, _code, message := h.currentSchoolTerm(access, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetUnsignedStudents", opentracing.ChildOf(parentSpan))
 	selectedStudents, err := access.GetUnsignedStudents(int64(req.TargetGrade), int64(req.TargetGroup), int64(req.TargetNumber))
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedStudents", selectedStudents), log.Error(err))
@@ -683,47 +716,274 @@ func (h _default) SendJoinSMSToUnsignedStudents(ctx context.Context, req *proto.
 		return
 	}
 
-	smsFormat := `
-[대덕소프트웨어마이스터고등학교]
+	smsTemplate, err := h.messageTemplateWithName(access, joinSMSTemplateName, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to get join message template, err: " + err.Error())
+		return
+	}
+
+	// messages are queued in this tx and sent by outbox worker, so that batch is not lost by failure of message service
+	// expired auth code can't be used for signup, so student having it is skipped until auth code is regenerated
+	batchID := uuid.New().String()
+	now := time.Now()
+	var queuedCount, expiredCount uint32
+	for _, student := range selectedStudents {
+		if authCodeExpired(student, now) {
+			expiredCount++
+			continue
+		}
+
+		if err = h.enqueueJoinSMS(access, smsTemplate, term, student, batchID, parentSpan, reqID); err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+		queuedCount++
+	}
+
+	access.Commit()
+	resp.Status = http.StatusAccepted
+	resp.Message = fmt.Sprintf("succeed to queue join messages, check result with GetMessageDeliveryStatus. skipped student with expired auth code: %d", expiredCount)
+	resp.BatchID = batchID
+	resp.QueuedCount = queuedCount
+	return
+}
+
+// add in v.1.2.0
+func (h _default) UnlockAccount(ctx context.Context, req *proto.UnlockAccountRequest, resp *proto.UnlockAccountResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	switch role(req.AccountType) {
+	case adminRole, studentRole, teacherRole, parentRole:
+		break
+	default:
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid account type, type: " + req.AccountType)
+		return
+	}
 
-학교 지원 시스템(SMS) 회원가입 안내 문자입니다. 아래 내용 필독 후 진행해주세요.
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
 
-[가입 대상: %d%d%02d %s]
-[인증 번호: %d]
+	if authorized, status, _code, message := h.authorize(ctx, access, "UnlockAccount", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	// unlock source of request too, if source ip is specified in request
+	throttleKeys := []string{accountThrottleKey(req.AccountType, req.AccountID)}
+	if req.SourceIP != "" {
+		throttleKeys = append(throttleKeys, sourceThrottleKey(req.SourceIP))
+	}
+
+	for _, throttleKey := range throttleKeys {
+		if err = h.resetLoginFailure(ctx, access, throttleKey); err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+	}
 
-Play 스토어 또는 앱스토어에서 'SMS 학교 지원 시스템' 앱 다운로드 후 진행해주세요.
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to unlock account"
+	return
+}
 
-참고로 iOS는 현재 앱 심사중이라 사용이 불가능합니다. 등록 되는대로 재안내 드리겠습니다.
+// add in v.1.2.0
+const (
+	defaultAuthLogLimit = 100
+	maxAuthLogLimit     = 500
+)
 
-모든 재학생분들(신입생 포함)은 3/11(목)까지 회원가입을 완료해주세요.
+func (h _default) GetAuthLogs(ctx context.Context, req *proto.GetAuthLogsRequest, resp *proto.GetAuthLogsResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
 
-페이스북 'DSM 기숙사 지원 시스템' 페이지를 팔로우하여 여러 정보를 받아보세요!
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
-* 해당 문자는 전공동아리 DMS에서 발신되었습니다.`
+	switch req.Outcome {
+	case "", authLogOutcomeSuccess, authLogOutcomeFailure:
+		break
+	default:
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid outcome, outcome: " + req.Outcome)
+		return
+	}
 
-	receivers := make([]string, len(selectedStudents))
-	contents := make([]string, len(selectedStudents))
-	for i, student := range selectedStudents {
-		receivers[i] = string(student.PhoneNumber)
-		contents[i] = fmt.Sprintf(smsFormat, student.Grade, student.Class, student.StudentNumber, student.Name, student.AuthCode)
+	// zero value of time range means unbounded
+	var since, until time.Time
+	if req.Since != 0 { since = time.Unix(req.Since, 0) }
+	if req.Until != 0 { until = time.Unix(req.Until, 0) }
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "since must be before until")
+		return
 	}
 
-	spanForMsg := h.tracer.StartSpan("SendMassToReceivers", opentracing.ChildOf(parentSpan))
-	jsonResp, err := message.SendMassToReceivers(receivers, contents, "LMS", "DSM 학교 지원 시스템(SMS) 회원가입 안내")
-	spanForMsg.SetTag("X-Request-Id", reqID).LogFields(log.Object("JsonResponse", jsonResp), log.Error(err))
-	spanForMsg.Finish()
+	limit := int(req.Limit)
+	if limit <= 0 || limit > maxAuthLogLimit {
+		limit = defaultAuthLogLimit
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetAuthLogs", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetAuthLogs", opentracing.ChildOf(parentSpan))
+	selectedLogs, err := access.GetAuthLogs(&model.AuthLog{
+		AccountType: req.AccountType,
+		AccountID:   req.AccountID,
+		OwnerUUID:   req.OwnerUUID,
+		Outcome:     req.Outcome,
+	}, since, until, limit)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int("SelectedLogCount", len(selectedLogs)), log.Error(err))
+	spanForDB.Finish()
 
 	if err != nil {
 		access.Rollback()
 		resp.Status = http.StatusInternalServerError
-		resp.Message = fmt.Sprintf(internalServerErrorFormat, "fail to send mass message, err: " + err.Error())
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
 		return
 	}
 
+	authLogs := make([]*proto.AuthLog, len(selectedLogs))
+	for i, selectedLog := range selectedLogs {
+		authLogs[i] = &proto.AuthLog{
+			Action:      selectedLog.Action,
+			AccountType: selectedLog.AccountType,
+			AccountID:   selectedLog.AccountID,
+			OwnerUUID:   selectedLog.OwnerUUID,
+			Outcome:     selectedLog.Outcome,
+			Status:      selectedLog.Status,
+			Code:        selectedLog.Code,
+			RequestID:   selectedLog.RequestID,
+			SourceIP:    selectedLog.SourceIP,
+			UserAgent:   selectedLog.UserAgent,
+			CreatedAt:   selectedLog.CreatedAt.Unix(),
+		}
+	}
+
+	access.Commit()
 	resp.Status = http.StatusOK
-	resp.Message = "succeed to send mass message"
-	resp.SendCount = uint32(jsonResp.SuccessCnt)
-	resp.NoSendCount = uint32(jsonResp.ErrorCnt)
+	resp.Message = "succeed to get auth logs"
+	resp.AuthLogs = authLogs
+	return
+}
+
+// add in v.1.2.0
+const (
+	defaultAuditEventLimit = 50
+	maxAuditEventLimit     = 200
+)
+
+// audit events are paginated with cursor, which is id of last event in previous page
+func (h _default) ListAuditEvents(ctx context.Context, req *proto.ListAuditEventsRequest, resp *proto.ListAuditEventsResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	switch req.Action {
+	case "", "create", "update", "delete", "restore", "purge":
+		break
+	default:
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid action, action: " + req.Action)
+		return
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > maxAuditEventLimit {
+		limit = defaultAuditEventLimit
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "ListAuditEvents", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetAuditEvents", opentracing.ChildOf(parentSpan))
+	selectedEvents, err := access.GetAuditEvents(&model.AuditEvent{
+		ActorUUID: req.ActorUUID,
+		RPC:       req.RPC,
+		Entity:    req.Entity,
+		TargetKey: req.TargetKey,
+		Action:    req.Action,
+	}, uint(req.Cursor), limit)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int("SelectedEventCount", len(selectedEvents)), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	auditEvents := make([]*proto.AuditEvent, len(selectedEvents))
+	for i, selectedEvent := range selectedEvents {
+		auditEvents[i] = &proto.AuditEvent{
+			ID:        uint64(selectedEvent.ID),
+			ActorUUID: selectedEvent.ActorUUID,
+			RPC:       selectedEvent.RPC,
+			Entity:    selectedEvent.Entity,
+			TargetKey: selectedEvent.TargetKey,
+			Action:    selectedEvent.Action,
+			Diff:      selectedEvent.Diff,
+			CreatedAt: selectedEvent.CreatedAt.Unix(),
+		}
+	}
+
+	// next cursor is not set in last page, so that client stops paging
+	if len(selectedEvents) == limit {
+		resp.NextCursor = uint64(selectedEvents[len(selectedEvents)-1].ID)
+	}
+
 	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to list audit events"
+	resp.AuditEvents = auditEvents
 	return
 }
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"testing"
+	"time"
 )
 
 //func init() {
@@ -38,45 +39,54 @@ func Test_default_CreateNewStudent(t *testing.T) {
 	tests := []test.CreateNewStudentCase{
 		{ // success case
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, nil},
-				"Commit":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:      http.StatusCreated,
 			ExpectedStudentUUID: studentUUIDRegexString,
 		}, { // not admin uuid -> forbidden
 			UUID:            "NotAdminAuthUUID", // (admin-숫자 12개의 형식이여야 함)
-			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus:  http.StatusForbidden,
 		}, { // invalid request value -> Proxy Authorization Required
 			StudentID: "유효하지 않은 아이디", // ASCII, 4~16 사이 문자열이여야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, (validator.ValidationErrors)(nil)},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // invalid request value -> Proxy Authorization Required
 			Grade: 100, // 1~3 사이의 숫자여야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, (validator.ValidationErrors)(nil)},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // invalid request value -> Proxy Authorization Required
 			Name: "Invalid Name", // 2~4 글자의 한글이어야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, (validator.ValidationErrors)(nil)},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
@@ -98,30 +108,33 @@ func Test_default_CreateNewStudent(t *testing.T) {
 		}, { // student id duplicate -> Conflict -101
 			StudentID: "jinhong0719",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, mysqlerr.DuplicateEntry(model.StudentAuthInstance.StudentID.KeyName(), "jinhong0719")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, mysqlerr.DuplicateEntry(model.StudentAuthInstance.StudentID.KeyName(), "jinhong0719")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.StudentIDDuplicate,
 		}, { // parent uuid fk constraint fail -> Conflict -102
 			ParentUUID: "parent-111111111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, test.StudentAuthParentUUIDFKConstraintFailError},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, test.StudentAuthParentUUIDFKConstraintFailError},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.ParentUUIDNoExist,
 		}, { // image empty byte array
 			Image: []byte(test.EmptyReplaceValueForString),
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // student number duplicate -> Conflict -103
@@ -129,60 +142,59 @@ func Test_default_CreateNewStudent(t *testing.T) {
 			Class:         2,
 			StudentNumber: 7,
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, mysqlerr.DuplicateEntry(model.StudentInformInstance.StudentNumber.KeyName(), "2207")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, mysqlerr.DuplicateEntry(model.StudentInformInstance.StudentNumber.KeyName(), "2-2-7")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.StudentNumberDuplicate,
 		}, { // phone number duplicate -> Conflict -104
 			PhoneNumber: "01088378347",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, mysqlerr.DuplicateEntry(model.StudentInformInstance.PhoneNumber.KeyName(), "01088378347")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, mysqlerr.DuplicateEntry(model.StudentInformInstance.PhoneNumber.KeyName(), "01088378347")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.StudentPhoneNumberDuplicate,
-		}, { // CheckIfStudentAuthExists error occur
-			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, errors.New("unexpected error from DB Connection")},
-				"Rollback":               {&gorm.DB{}},
-			},
-			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return invalid duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return unexpected key duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return invalid Fk Constraint Fail error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_NO_REFERENCED_ROW_2, Message: "InvalidMessage"}},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_NO_REFERENCED_ROW_2, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return unexpected constraint name error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"CreateStudentAuth": {&model.StudentAuth{}, mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
 					ConstraintName: "unexpected constraint name",
 					AttrName:       "unexpected attr",
@@ -192,8 +204,9 @@ func Test_default_CreateNewStudent(t *testing.T) {
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return unexpected constraint name error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"CreateStudentAuth": {&model.StudentAuth{}, mysqlerr.FKConstraintFailWithoutReferenceInform(mysqlerr.FKInform{
 					ConstraintName: "unexpected constraint name",
 					AttrName:       "unexpected attr",
@@ -203,54 +216,60 @@ func Test_default_CreateNewStudent(t *testing.T) {
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentAuth return unexpected type of error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, errors.New("unexpected type of error")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, errors.New("unexpected type of error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentInform return invalid duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentInform return unexpected duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, mysqlerr.DuplicateEntry("UnexpectedKey", "duplicated")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, mysqlerr.DuplicateEntry("UnexpectedKey", "duplicated")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentInform return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateStudentInform return unexpected type of error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"CreateStudentAuth":      {&model.StudentAuth{}, nil},
-				"CreateStudentInform":    {&model.StudentInform{}, errors.New("unexpected error")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateStudentAuth":                     {&model.StudentAuth{}, nil},
+				"CreateStudentInform":                   {&model.StudentInform{}, errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -287,45 +306,54 @@ func Test_default_CreateNewTeacher(t *testing.T) {
 			Grade: test.EmptyReplaceValueForUint32,
 			Class: test.EmptyReplaceValueForUint32,
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, nil},
-				"Commit":                   {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:      http.StatusCreated,
 			ExpectedStudentUUID: teacherUUIDRegexString,
 		}, { // not admin uuid -> forbidden
 			UUID:            "NotAdminAuthUUID", // (admin-숫자 12개의 형식이여야 함)
-			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus:  http.StatusForbidden,
 		}, { // invalid request value -> Proxy Authorization Required
 			TeacherID: "유효하지 않은 아이디", // ASCII, 4~16 사이 문자열이여야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, (validator.ValidationErrors)(nil)},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // invalid request value -> Proxy Authorization Required
 			Grade: 100, // 1~3 사이의 숫자여야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, (validator.ValidationErrors)(nil)},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // invalid request value -> Proxy Authorization Required
 			Name: "Invalid Name", // 2~4 글자의 한글이어야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, (validator.ValidationErrors)(nil)},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
@@ -347,97 +375,100 @@ func Test_default_CreateNewTeacher(t *testing.T) {
 		}, { // student id duplicate -> Conflict -201
 			TeacherID: "duplicateID",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, mysqlerr.DuplicateEntry(model.TeacherAuthInstance.TeacherID.KeyName(), "duplicateID")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, mysqlerr.DuplicateEntry(model.TeacherAuthInstance.TeacherID.KeyName(), "duplicateID")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.TeacherIDDuplicate,
 		}, { // phone number duplicate -> Conflict -202
 			PhoneNumber: "01088378347",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, mysqlerr.DuplicateEntry(model.TeacherInformInstance.PhoneNumber.KeyName(), "01088378347")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, mysqlerr.DuplicateEntry(model.TeacherInformInstance.PhoneNumber.KeyName(), "01088378347")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.TeacherPhoneNumberDuplicate,
-		}, { // CheckIfTeacherAuth1Exists error occur
-			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, errors.New("unexpected error from DB Connection")},
-				"Rollback":                 {&gorm.DB{}},
-			},
-			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherAuth return invalid duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherAuth return unexpected key duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherAuth return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherAuth return unexpected type of error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, errors.New("unexpected type of error")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, errors.New("unexpected type of error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherInform return invalid duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherInform return unexpected duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, mysqlerr.DuplicateEntry("UnexpectedKey", "duplicated")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, mysqlerr.DuplicateEntry("UnexpectedKey", "duplicated")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherInform return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateTeacherInform return unexpected type of error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherAuthWithUUID":   {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"CreateTeacherAuth":        {&model.TeacherAuth{}, nil},
-				"CreateTeacherInform":      {&model.TeacherInform{}, errors.New("unexpected type of error")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateTeacherAuth":                     {&model.TeacherAuth{}, nil},
+				"CreateTeacherInform":                   {&model.TeacherInform{}, errors.New("unexpected type of error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -471,35 +502,43 @@ func Test_default_CreateNewParent(t *testing.T) {
 	tests := []test.CreateNewParentCase{
 		{ // success case
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, nil},
-				"Commit":                {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:      http.StatusCreated,
 			ExpectedStudentUUID: parentUUIDRegexString,
 		}, { // not admin uuid -> forbidden
 			UUID:            "NotAdminAuthUUID", // (admin-숫자 12개의 형식이여야 함)
-			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus:  http.StatusForbidden,
 		}, { // invalid request value -> Proxy Authorization Required
 			ParentID: "유효하지 않은 아이디", // ASCII, 4~16 사이 문자열이여야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, (validator.ValidationErrors)(nil)},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // invalid request value -> Proxy Authorization Required
 			Name: "Invalid Name", // 2~4 글자의 한글이어야 함
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, (validator.ValidationErrors)(nil)},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, (validator.ValidationErrors)(nil)},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusProxyAuthRequired,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
@@ -521,97 +560,100 @@ func Test_default_CreateNewParent(t *testing.T) {
 		}, { // student id duplicate -> Conflict -201
 			ParentID: "duplicateID",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, mysqlerr.DuplicateEntry(model.ParentAuthInstance.ParentID.KeyName(), "duplicateID")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, mysqlerr.DuplicateEntry(model.ParentAuthInstance.ParentID.KeyName(), "duplicateID")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.ParentIDDuplicate,
 		}, { // phone number duplicate -> Conflict -202
 			PhoneNumber: "01088378347",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, mysqlerr.DuplicateEntry(model.ParentInformInstance.PhoneNumber.KeyName(), "01088378347")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, mysqlerr.DuplicateEntry(model.ParentInformInstance.PhoneNumber.KeyName(), "01088378347")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.ParentPhoneNumberDuplicate,
-		}, { // GetParentAuth1WithUUID error occur
-			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, errors.New("unexpected error from DB Connection")},
-				"Rollback":              {&gorm.DB{}},
-			},
-			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentAuth return invalid duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentAuth return unexpected key duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, mysqlerr.DuplicateEntry("UnexpectedKey", "error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentAuth return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentAuth return unexpected type of error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, errors.New("unexpected type of error")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, errors.New("unexpected type of error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentInform return invalid duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_DUP_ENTRY, Message: "InvalidMessage"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentInform return unexpected duplicate error
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, mysqlerr.DuplicateEntry("UnexpectedKey", "duplicated")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, mysqlerr.DuplicateEntry("UnexpectedKey", "duplicated")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentInform return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, &mysql.MySQLError{Number: mysqlcode.ER_BAD_NULL_ERROR, Message: "unexpected code"}},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // CreateParentInform return unexpected error code
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"CreateParentAuth":      {&model.ParentAuth{}, nil},
-				"CreateParentInform":    {&model.ParentInform{}, errors.New("unexpected type of error")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateParentAuth":                      {&model.ParentAuth{}, nil},
+				"CreateParentInform":                    {&model.ParentInform{}, errors.New("unexpected type of error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -641,22 +683,50 @@ func Test_default_CreateNewParent(t *testing.T) {
 
 func Test_default_LoginAdminAuth(t *testing.T) {
 	hashedByte, _ := bcrypt.GenerateFromPassword([]byte("testPW"), 1)
+	lockedUntil := time.Now().Add(time.Minute)
+	enabledAt := time.Now().Add(-time.Hour)
 
 	tests := []test.LoginAdminAuthCase{
 		{ // success case
 			AdminID: "jinhong07191",
 			AdminPW: "testPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetAdminAuthWithID": {&model.AdminAuth{
 					UUID:    "admin-111111111111",
 					AdminID: "jinhong07191",
 					AdminPW: model.AdminPW(string(hashedByte)),
 				}, nil},
-				"Commit": {&gorm.DB{}},
+				"ChangeAdminPW":                 {nil},
+				"DeleteLoginThrottle":           {nil},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{}, gorm.ErrRecordNotFound},
+				"CreateSession":                 {&model.Session{}, nil},
+				"CreateRefreshToken":            {&model.RefreshToken{}, nil},
+				"Commit":                        {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus:            http.StatusOK,
 			ExpectedLoggedInAdminUUID: "admin-111111111111",
+		}, { // success case with enabled TOTP -> second step required
+			AdminID: "jinhong07196",
+			AdminPW: "testPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetAdminAuthWithID": {&model.AdminAuth{
+					UUID:    "admin-111111111111",
+					AdminID: "jinhong07196",
+					AdminPW: model.AdminPW(string(hashedByte)),
+				}, nil},
+				"ChangeAdminPW":                 {nil},
+				"DeleteLoginThrottle":           {nil},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{EnabledAt: &enabledAt}, nil},
+				"Commit":                        {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus: http.StatusAccepted,
+			ExpectedCode:   code.TOTPRequired,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
 			XRequestID:      test.EmptyReplaceValueForString,
 			ExpectedMethods: map[test.Method]test.Returns{},
@@ -676,34 +746,57 @@ func Test_default_LoginAdminAuth(t *testing.T) {
 		}, { // Parent ID no exists
 			AdminID: "jinhong07192",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":            {},
-				"GetAdminAuthWithID": {&model.AdminAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":           {&gorm.DB{}},
+				"BeginTx":                        {},
+				"GetLoginThrottleWithKey":        {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetAdminAuthWithID":             {&model.AdminAuth{}, gorm.ErrRecordNotFound},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.AdminIDNoExist,
 		}, { // GetParentAuthWithID unexpected error
 			AdminID: "jinhong07193",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":            {},
-				"GetAdminAuthWithID": {&model.AdminAuth{}, errors.New("unexpected error")},
-				"Rollback":           {&gorm.DB{}},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetAdminAuthWithID":      {&model.AdminAuth{}, errors.New("unexpected error")},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // incorrect Parent PW
 			AdminID: "jinhong07194",
 			AdminPW: "incorrectPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetAdminAuthWithID": {&model.AdminAuth{
 					UUID:    "admin-111111111111",
 					AdminID: "jinhong07194",
 					AdminPW: model.AdminPW(string(hashedByte)),
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectAdminPWForLogin,
+		}, { // account locked by too many login failures
+			AdminID: "jinhong07195",
+			AdminPW: "testPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{FailureCount: 5, LockedUntil: &lockedUntil}, nil},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusLocked,
+			ExpectedCode:   code.AccountLockedForLogin,
 		},
 	}
 
@@ -724,6 +817,189 @@ func Test_default_LoginAdminAuth(t *testing.T) {
 		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, testCase.ExpectedLoggedInAdminUUID, resp.LoggedInAdminUUID, "logged in uuid assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedStatus == http.StatusOK, resp.AccessToken != "" && resp.RefreshToken != "", "token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedCode == code.TOTPRequired, resp.TOTPToken != "", "totp token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_GetAuthLogs(t *testing.T) {
+	selectedLogs := []*model.AuthLog{
+		{Action: "LoginStudentAuth", AccountType: "student", AccountID: "jinhong0719", Outcome: "failure", Status: http.StatusConflict},
+		{Action: "LoginStudentAuth", AccountType: "student", AccountID: "jinhong0719", OwnerUUID: "student-111111111111", Outcome: "success", Status: http.StatusOK},
+	}
+
+	tests := []test.GetAuthLogsCase{
+		{ // success case
+			AccountType: "student",
+			AccountID:   "jinhong0719",
+			Since:       time.Now().Add(-time.Hour * 24).Unix(),
+			Until:       time.Now().Unix(),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAuthLogs":                           {selectedLogs, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:       http.StatusOK,
+			ExpectedLimit:        100,
+			ExpectedAuthLogCount: 2,
+		}, { // success case (failure outcome with limit)
+			OwnerUUID: "student-111111111111",
+			Outcome:   "failure",
+			Limit:     10,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAuthLogs":                           {selectedLogs[:1], nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:       http.StatusOK,
+			ExpectedLimit:        10,
+			ExpectedAuthLogCount: 1,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // invalid outcome -> Proxy Authorization Required
+			Outcome:         "locked",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // since is after until -> Proxy Authorization Required
+			Since:           time.Now().Unix(),
+			Until:           time.Now().Add(-time.Hour).Unix(),
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // GetAuthLogs unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAuthLogs":                           {[]*model.AuthLog{}, errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedLimit:  100,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.GetAuthLogsRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.GetAuthLogsResponse)
+		_ = defaultHandler.GetAuthLogs(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedAuthLogCount, len(resp.AuthLogs), "auth log count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_ListAuditEvents(t *testing.T) {
+	selectedEvents := []*model.AuditEvent{
+		{Model: gorm.Model{ID: 12}, ActorUUID: "admin-111111111111", RPC: "ChangeTeacherInform", Entity: "teacher_informs", TargetKey: "teacher-111111111111", Action: "update", Diff: `{"name":{"before":"박진홍","after":"박진우"}}`},
+		{Model: gorm.Model{ID: 7}, ActorUUID: "admin-111111111111", RPC: "CreateNewTeacher", Entity: "teacher_auths", TargetKey: "teacher-111111111111", Action: "create", Diff: `{"teacher_id":{"before":null,"after":"jinhong0719"}}`},
+	}
+
+	tests := []test.ListAuditEventsCase{
+		{ // success case (last page)
+			TargetKey: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAuditEvents":                        {selectedEvents, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:          http.StatusOK,
+			ExpectedLimit:           50,
+			ExpectedAuditEventCount: 2,
+		}, { // success case (page is full -> next cursor is id of last event)
+			RPC:    "ChangeTeacherInform",
+			Action: "update",
+			Cursor: 20,
+			Limit:  1,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAuditEvents":                        {selectedEvents[:1], nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:          http.StatusOK,
+			ExpectedLimit:           1,
+			ExpectedAuditEventCount: 1,
+			ExpectedNextCursor:      12,
+		}, { // no exist Span-Context -> Proxy Authorization Required
+			SpanContextString: test.EmptyReplaceValueForString,
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // invalid action -> Proxy Authorization Required
+			Action:          "truncate",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // GetAuditEvents unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAuditEvents":                        {[]*model.AuditEvent{}, errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedLimit:  50,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.ListAuditEventsRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.ListAuditEventsResponse)
+		_ = defaultHandler.ListAuditEvents(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedAuditEventCount, len(resp.AuditEvents), "audit event count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedNextCursor, resp.NextCursor, "next cursor assertion error (test case: %v, message: %s)", testCase, resp.Message)
 
 		newMock.AssertExpectations(t)
 	}
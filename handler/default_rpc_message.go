@@ -0,0 +1,97 @@
+// add file in v.1.2.0
+// this file declare method that handling RPC about delivery of queued message (in AuthAdmin service) in _default struct
+// content of message is not returned, because it includes secret of recipient (ex: auth code)
+
+package handler
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+)
+
+func (h _default) GetMessageDeliveryStatus(ctx context.Context, req *proto.GetMessageDeliveryStatusRequest, resp *proto.GetMessageDeliveryStatusResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	if len(req.BatchID) != 36 {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid batch id, batch id: " + req.BatchID)
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetMessageDeliveryStatus", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetOutboxMessagesWithBatchID", opentracing.ChildOf(parentSpan))
+	selectedMessages, err := access.GetOutboxMessagesWithBatchID(req.BatchID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int("SelectedCount", len(selectedMessages)), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		access.Rollback()
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, "messages with that batch id not exist")
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+	access.Commit()
+
+	resp.Deliveries = make([]*proto.MessageDelivery, len(selectedMessages))
+	for i, message := range selectedMessages {
+		delivery := &proto.MessageDelivery{
+			Receiver:     message.Receiver,
+			Recipient:    message.Recipient,
+			Status:       message.Status,
+			AttemptCount: uint32(message.AttemptCount),
+			MsgID:        message.MsgID,
+			LastError:    message.LastError,
+		}
+		if message.SentAt != nil {
+			delivery.SentAt = message.SentAt.Unix()
+		}
+		resp.Deliveries[i] = delivery
+
+		switch message.Status {
+		case outboxStatusPending:
+			resp.PendingCount++
+		case outboxStatusSent:
+			resp.SentCount++
+		case outboxStatusFailed:
+			resp.FailedCount++
+		}
+	}
+
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to get message delivery status"
+	return
+}
@@ -0,0 +1,264 @@
+// add file in v.1.2.0
+// this file declare method that handling message template RPC (in AuthAdmin service) in _default struct
+// admin can read, preview and change template without redeploy, and every change is stored as new version
+
+package handler
+
+import (
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/dberr"
+	code "auth/utils/code/golang"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+)
+
+func (h _default) GetMessageTemplate(ctx context.Context, req *proto.GetMessageTemplateRequest, resp *proto.GetMessageTemplateResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	builtIn, exists := messageTemplateDefaults[req.Name]
+	if !exists {
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, "message template with that name not exists, name: " + req.Name)
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetMessageTemplate", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	var selectedTemplate *model.MessageTemplate
+	spanForDB := h.tracer.StartSpan("GetMessageTemplate", opentracing.ChildOf(parentSpan))
+	if req.Version == 0 {
+		selectedTemplate, err = access.GetLatestMessageTemplateWithName(req.Name)
+	} else {
+		selectedTemplate, err = access.GetMessageTemplateWithNameAndVersion(req.Name, int64(req.Version))
+	}
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedTemplate", selectedTemplate), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		if req.Version == 0 {
+			selectedTemplate = &builtIn.template
+			break
+		}
+		access.Rollback()
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, fmt.Sprintf("message template with that version not exists, version: %d", req.Version))
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to get message template"
+	resp.Name = selectedTemplate.Name
+	resp.Version = uint32(selectedTemplate.Version)
+	resp.MessageType = selectedTemplate.MessageType
+	resp.Title = selectedTemplate.Title
+	resp.Body = selectedTemplate.Body
+	resp.EditorUUID = selectedTemplate.EditorUUID
+	return
+}
+
+func (h _default) ChangeMessageTemplate(ctx context.Context, req *proto.ChangeMessageTemplateRequest, resp *proto.ChangeMessageTemplateResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	if _, exists := messageTemplateDefaults[req.Name]; !exists {
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, "message template with that name not exists, name: " + req.Name)
+		return
+	}
+
+	changedTemplate := model.MessageTemplate{Name: req.Name, MessageType: req.MessageType, Title: req.Title, Body: req.Body}
+	if _, err := parseMessageTemplate(changedTemplate); err != nil {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid message template, err: " + err.Error())
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeMessageTemplate", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetLatestMessageTemplateWithName", opentracing.ChildOf(parentSpan))
+	latestTemplate, err := access.GetLatestMessageTemplateWithName(req.Name)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("LatestTemplate", latestTemplate), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		changedTemplate.Version = latestTemplate.Version + 1
+	case gorm.ErrRecordNotFound:
+		changedTemplate.Version = 1
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	changedTemplate.EditorUUID = callerOf(ctx).UUID
+	spanForDB = h.tracer.StartSpan("CreateMessageTemplate", opentracing.ChildOf(parentSpan))
+	createdTemplate, err := access.CreateMessageTemplate(&changedTemplate)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedTemplate", createdTemplate), log.Error(err))
+	spanForDB.Finish()
+
+	switch assertedError := dberr.From(err).(type) {
+	case nil:
+		break
+	case validator.ValidationErrors:
+		access.Rollback()
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for message template, err: " + err.Error())
+		return
+	case *dberr.Error:
+		access.Rollback()
+		if assertedError.Category == dberr.Duplicate {
+			// other admin changed same template at the same time
+			resp.Status = http.StatusConflict
+			resp.Code = code.MessageTemplateVersionConflict
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "message template is changed by other request, please try again")
+			return
+		}
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected CreateMessageTemplate error, err: " + assertedError.Error())
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "CreateMessageTemplate returns unexpected type of error, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusCreated
+	resp.Message = "succeed to change message template"
+	resp.Version = uint32(changedTemplate.Version)
+	return
+}
+
+// preview render template with sample data, body in request is rendered if it is set, or stored version (0 is latest) is rendered
+func (h _default) PreviewMessageTemplate(ctx context.Context, req *proto.PreviewMessageTemplateRequest, resp *proto.PreviewMessageTemplateResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	builtIn, exists := messageTemplateDefaults[req.Name]
+	if !exists {
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, "message template with that name not exists, name: " + req.Name)
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "PreviewMessageTemplate", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	// template to preview is template in request or stored template, stored template is read only and tx is rolled back
+	previewedTemplate := &model.MessageTemplate{Name: req.Name, MessageType: req.MessageType, Title: req.Title, Body: req.Body}
+	if req.Body == "" {
+		spanForDB := h.tracer.StartSpan("GetMessageTemplate", opentracing.ChildOf(parentSpan))
+		if req.Version == 0 {
+			previewedTemplate, err = access.GetLatestMessageTemplateWithName(req.Name)
+		} else {
+			previewedTemplate, err = access.GetMessageTemplateWithNameAndVersion(req.Name, int64(req.Version))
+		}
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedTemplate", previewedTemplate), log.Error(err))
+		spanForDB.Finish()
+	}
+	access.Rollback()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		if req.Version == 0 {
+			previewedTemplate = &builtIn.template
+			break
+		}
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, fmt.Sprintf("message template with that version not exists, version: %d", req.Version))
+		return
+	default:
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	previewed, err := parseMessageTemplate(*previewedTemplate)
+	if err != nil {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid message template, err: " + err.Error())
+		return
+	}
+
+	resp.Content, _ = previewed.render(builtIn.sampleData)
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to preview message template with sample data"
+	resp.MessageType = previewed.MessageType
+	resp.Title = previewed.Title
+	return
+}
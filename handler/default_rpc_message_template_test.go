@@ -0,0 +1,102 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func Test_default_ChangeMessageTemplate(t *testing.T) {
+	tests := []test.ChangeMessageTemplateCase{
+		{ // success case (first change of template)
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLatestMessageTemplateWithName":      {&model.MessageTemplate{}, gorm.ErrRecordNotFound},
+				"CreateMessageTemplate":                 {&model.MessageTemplate{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:  http.StatusCreated,
+			ExpectedVersion: 1,
+		}, { // success case (template changed before)
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLatestMessageTemplateWithName":      {&model.MessageTemplate{Version: 3}, nil},
+				"CreateMessageTemplate":                 {&model.MessageTemplate{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:  http.StatusCreated,
+			ExpectedVersion: 4,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // unknown template name -> not found
+			Name:            "unknown_template",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusNotFound,
+		}, { // variable not exists in data of template -> Proxy Authorization Required
+			Body:            "인증 번호는 [{{.AuthCode}}] 입니다.",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // invalid template syntax -> Proxy Authorization Required
+			Body:            "인증 번호는 [{{.ResetCode] 입니다.",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // title in SMS template -> Proxy Authorization Required
+			Title:           "비밀번호 재설정",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // same version is created by other request -> conflict
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLatestMessageTemplateWithName":      {&model.MessageTemplate{Version: 3}, nil},
+				"CreateMessageTemplate":                 {&model.MessageTemplate{}, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.MessageTemplateVersionConflict,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.ChangeMessageTemplateRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.ChangeMessageTemplateResponse)
+		_ = defaultHandler.ChangeMessageTemplate(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedVersion, resp.Version, "version assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
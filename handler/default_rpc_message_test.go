@@ -0,0 +1,130 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/message"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_default_GetMessageDeliveryStatus(t *testing.T) {
+	sentAt := time.Now()
+	deliveredMessages := []*model.OutboxMessage{
+		{Receiver: "01012345678", Recipient: "2107 박진홍", Status: "sent", AttemptCount: 1, MsgID: "123456789", SentAt: &sentAt},
+		{Receiver: "01012345679", Recipient: "2108 홍길동", Status: "pending", AttemptCount: 2, LastError: "timeout"},
+		{Receiver: "01012345670", Recipient: "2109 김철수", Status: "failed", AttemptCount: 5, LastError: "timeout"},
+	}
+
+	tests := []test.GetMessageDeliveryStatusCase{
+		{ // success case
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetOutboxMessagesWithBatchID":          {deliveredMessages, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:       http.StatusOK,
+			ExpectedSentCount:    1,
+			ExpectedPendingCount: 1,
+			ExpectedFailedCount:  1,
+		}, { // no exist Span-Context -> Proxy Authorization Required
+			SpanContextString: test.EmptyReplaceValueForString,
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // invalid batch id -> Proxy Authorization Required
+			BatchID:         "batch-1",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // batch not exists -> not found
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetOutboxMessagesWithBatchID":          {[]*model.OutboxMessage{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.GetMessageDeliveryStatusRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.GetMessageDeliveryStatusResponse)
+		_ = defaultHandler.GetMessageDeliveryStatus(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedSentCount, resp.SentCount, "sent count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedPendingCount, resp.PendingCount, "pending count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedFailedCount, resp.FailedCount, "failed count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_deliverOutboxChunk(t *testing.T) {
+	tests := []struct {
+		attemptCount   int64 // attempt count before claim
+		sendErr        error
+		expectedStatus string
+		expectedMsgID  bool
+	}{
+		{attemptCount: 0, sendErr: nil, expectedStatus: "sent", expectedMsgID: true},
+		{attemptCount: 0, sendErr: errors.New("network is unreachable"), expectedStatus: "pending"},
+		{attemptCount: 4, sendErr: errors.New("network is unreachable"), expectedStatus: "failed"},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+		defaultHandler.messageSender.(*message.Fake).FailWith(testCase.sendErr)
+
+		dueMessage := &model.OutboxMessage{
+			Receiver: "01012345678", MessageType: "SMS", Content: "인증 번호는 [123456] 입니다.", Status: "pending", AttemptCount: testCase.attemptCount,
+		}
+		newMock.On("BeginTx").Return()
+		newMock.On("GetDueOutboxMessagesForUpdate", mock.Anything, 10).Return([]*model.OutboxMessage{dueMessage}, nil)
+		newMock.On("ModifyOutboxMessage", mock.Anything, mock.Anything).Return(nil)
+		newMock.On("Commit").Return(&gorm.DB{})
+
+		claimedCount, err := defaultHandler.deliverOutboxChunk(10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, claimedCount)
+		assert.Equal(t, testCase.attemptCount + 1, dueMessage.AttemptCount)
+		assert.Equalf(t, testCase.expectedStatus, dueMessage.Status, "status assertion error (last error: %s)", dueMessage.LastError)
+		assert.Equal(t, testCase.expectedMsgID, dueMessage.MsgID != "")
+		assert.Equal(t, testCase.expectedStatus == "sent", dueMessage.SentAt != nil)
+		assert.Equalf(t, testCase.expectedStatus != "pending", dueMessage.Content == outboxRedactedContent, "content assertion error (content: %s)", dueMessage.Content)
+		if testCase.expectedStatus == "pending" {
+			assert.True(t, dueMessage.NextAttemptAt.After(time.Now().Add(outboxRetryBaseDelay - time.Second)))
+		}
+
+		newMock.AssertExpectations(t)
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"auth/model"
 	proto "auth/proto/golang/auth"
+	"auth/tool/hash"
 	code "auth/utils/code/golang"
 	"context"
 	"fmt"
@@ -10,7 +11,6 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/uber/jaeger-client-go"
-	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"reflect"
 )
@@ -26,6 +26,11 @@ func (h _default) LoginParentAuth(ctx context.Context, req *proto.LoginParentAut
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "LoginParentAuth", accountType: parentRole, accountID: req.ParentID, ownerUUID: resp.LoggedInParentUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -33,19 +38,27 @@ func (h _default) LoginParentAuth(ctx context.Context, req *proto.LoginParentAut
 		return
 	}
 
+	accountKey := accountThrottleKey(string(parentRole), req.ParentID)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, accountKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetParentAuthWithID", opentracing.ChildOf(parentSpan))
 	resultAuth, err := access.GetParentAuthWithID(req.ParentID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", resultAuth), log.Error(err))
 	spanForDB.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
 		case gorm.ErrRecordNotFound:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.ParentIDNoExist
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "parent id not exists")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " +err.Error())
 		}
@@ -53,28 +66,54 @@ func (h _default) LoginParentAuth(ctx context.Context, req *proto.LoginParentAut
 	}
 
 	spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
-	err = bcrypt.CompareHashAndPassword([]byte(resultAuth.ParentPW), []byte(req.ParentPW))
+	err = hash.CompareHashAndPassword(string(resultAuth.ParentPW), req.ParentPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
-		case bcrypt.ErrMismatchedHashAndPassword:
+		case hash.ErrMismatchedHashAndPassword:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.IncorrectParentPWForLogin
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "hash compare error, err: " + err.Error())
 		}
 		return
 	}
 
+	err = h.rehashPWIfNeeded(access.ChangeParentPW, string(resultAuth.UUID), string(resultAuth.ParentPW), req.ParentPW, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	if err = h.resetLoginFailure(ctx, access, accountKey); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	accessToken, refreshToken, err := h.startSession(ctx, access, string(resultAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "succeed to login parent auth"
 	resp.LoggedInParentUUID = string(resultAuth.UUID)
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
 
 	return
 }
@@ -87,20 +126,14 @@ func (h _default) ChangeParentPW(ctx context.Context, req *proto.ChangeParentPWR
 		return
 	}
 
-	switch true {
-	case parentUUIDRegex.MatchString(req.ParentUUID) && req.UUID == req.ParentUUID:
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not parent or admin uuid OR not your student uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "ChangeParentPW", accountType: parentRole, ownerUUID: req.ParentUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -108,6 +141,12 @@ func (h _default) ChangeParentPW(ctx context.Context, req *proto.ChangeParentPWR
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeParentPW", req.ParentUUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetParentAuthWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetParentAuthWithUUID(req.ParentUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -127,14 +166,14 @@ func (h _default) ChangeParentPW(ctx context.Context, req *proto.ChangeParentPWR
 	}
 
 	spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
-	err = bcrypt.CompareHashAndPassword([]byte(selectedAuth.ParentPW), []byte(req.CurrentPW))
+	err = hash.CompareHashAndPassword(string(selectedAuth.ParentPW), req.CurrentPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
 	if err != nil {
 		access.Rollback()
 		switch err {
-		case bcrypt.ErrMismatchedHashAndPassword:
+		case hash.ErrMismatchedHashAndPassword:
 			resp.Status = http.StatusConflict
 			resp.Code = code.IncorrectParentPWForChange
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
@@ -145,8 +184,20 @@ func (h _default) ChangeParentPW(ctx context.Context, req *proto.ChangeParentPWR
 		return
 	}
 
+	if strong, status, _code, message := checkPasswordStrength(req.RevisionPW, string(selectedAuth.ParentID)); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	if allowed, status, _code, message := h.checkPasswordReuse(ctx, access, string(selectedAuth.UUID), string(selectedAuth.ParentPW), req.RevisionPW); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForHash = h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.RevisionPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.RevisionPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -158,7 +209,7 @@ func (h _default) ChangeParentPW(ctx context.Context, req *proto.ChangeParentPWR
 	}
 
 	spanForDB = h.tracer.StartSpan("ChangeParentPW", opentracing.ChildOf(parentSpan))
-	err = access.ChangeParentPW(string(selectedAuth.UUID), string(hashedBytes))
+	err = access.ChangeParentPW(string(selectedAuth.UUID), hashedPW)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForDB.Finish()
 
@@ -169,6 +220,13 @@ func (h _default) ChangeParentPW(ctx context.Context, req *proto.ChangeParentPWR
 		return
 	}
 
+	if err = h.recordPasswordHistory(ctx, access, string(selectedAuth.UUID), string(selectedAuth.ParentPW)); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "parent pw change success"
@@ -183,21 +241,6 @@ func (h _default) GetParentInformWithUUID(ctx context.Context, req *proto.GetPar
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -208,6 +251,12 @@ func (h _default) GetParentInformWithUUID(ctx context.Context, req *proto.GetPar
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetParentInformWithUUID", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetParentInformWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetParentInformWithUUID(req.ParentUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -243,21 +292,6 @@ func (h _default) GetParentUUIDsWithInform(ctx context.Context, req *proto.GetPa
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -268,6 +302,12 @@ func (h _default) GetParentUUIDsWithInform(ctx context.Context, req *proto.GetPa
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetParentUUIDsWithInform", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	informToSelect := &model.ParentInform{
 		Name:          model.Name(req.Name),
 		PhoneNumber:   model.PhoneNumber(req.PhoneNumber),
@@ -312,17 +352,6 @@ func (h _default) GetChildrenInformsWithUUID(ctx context.Context, req *proto.Get
 		return
 	}
 
-	switch true {
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID) && req.UUID == req.ParentUUID:
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not admin or your parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -333,6 +362,12 @@ func (h _default) GetChildrenInformsWithUUID(ctx context.Context, req *proto.Get
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetChildrenInformsWithUUID", req.ParentUUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetStudentInformsWithParentUUID", opentracing.ChildOf(parentSpan))
 	selectedInforms, err := access.GetStudentInformsWithParentUUID(req.ParentUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedInforms", selectedInforms), log.Error(err))
@@ -16,19 +16,26 @@ import (
 
 func Test_default_LoginParentAuth(t *testing.T) {
 	hashedByte, _ := bcrypt.GenerateFromPassword([]byte("testPW"), 1)
+	lockedUntil := time.Now().Add(time.Minute)
 
 	tests := []test.LoginParentAuthCase{
 		{ // success case
 			ParentID: "jinhong07191",
 			ParentPW: "testPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetParentAuthWithID": {&model.ParentAuth{
 					UUID:     "parent-111111111111",
 					ParentID: "jinhong07191",
 					ParentPW: model.ParentPW(string(hashedByte)),
 				}, nil},
-				"Commit": {&gorm.DB{}},
+				"ChangeParentPW":      {nil},
+				"DeleteLoginThrottle": {nil},
+				"CreateSession":       {&model.Session{}, nil},
+				"CreateRefreshToken":  {&model.RefreshToken{}, nil},
+				"Commit":              {&gorm.DB{}},
+				"CreateAuthLog":       {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus:             http.StatusOK,
 			ExpectedLoggedInParentUUID: "parent-111111111111",
@@ -51,34 +58,57 @@ func Test_default_LoginParentAuth(t *testing.T) {
 		}, { // Parent ID no exists
 			ParentID: "jinhong07192",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":             {},
-				"GetParentAuthWithID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":            {&gorm.DB{}},
+				"BeginTx":                        {},
+				"GetLoginThrottleWithKey":        {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetParentAuthWithID":            {&model.ParentAuth{}, gorm.ErrRecordNotFound},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.ParentIDNoExist,
 		}, { // GetParentAuthWithID unexpected error
 			ParentID: "jinhong07193",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":             {},
-				"GetParentAuthWithID": {&model.ParentAuth{}, errors.New("unexpected error")},
-				"Rollback":            {&gorm.DB{}},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetParentAuthWithID":     {&model.ParentAuth{}, errors.New("unexpected error")},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // incorrect Parent PW
 			ParentID: "jinhong07194",
 			ParentPW: "incorrectPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetParentAuthWithID": {&model.ParentAuth{
 					UUID:     "parent-111111111111",
 					ParentID: "jinhong07194",
 					ParentPW: model.ParentPW(string(hashedByte)),
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectParentPWForLogin,
+		}, { // account locked by too many login failures
+			ParentID: "jinhong07195",
+			ParentPW: "testPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{FailureCount: 5, LockedUntil: &lockedUntil}, nil},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusLocked,
+			ExpectedCode:   code.AccountLockedForLogin,
 		},
 	}
 
@@ -99,6 +129,7 @@ func Test_default_LoginParentAuth(t *testing.T) {
 		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, testCase.ExpectedLoggedInParentUUID, resp.LoggedInParentUUID, "logged in uuid assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedStatus == http.StatusOK, resp.AccessToken != "" && resp.RefreshToken != "", "token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
 
 		newMock.AssertExpectations(t)
 	}
@@ -114,13 +145,19 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			CurrentPW:  "testPW",
 			RevisionPW: "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetParentAuthWithUUID": {&model.ParentAuth{
 					UUID:     "parent-111111111111",
 					ParentPW: model.ParentPW(string(hashedTestPW)),
 				}, nil},
-				"ChangeParentPW": {nil},
-				"Commit":         {&gorm.DB{}},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeParentPW":                          {nil},
+				"CreatePasswordHistory":                   {&model.PasswordHistory{}, nil},
+				"DeletePasswordHistoriesExceptRecent":     {nil},
+				"Commit":                                  {&gorm.DB{}},
+				"CreateAuthLog":                           {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusOK,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
@@ -144,12 +181,28 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			ParentUUID:     "student-111111111112",
 			CurrentPW:      "testPW",
 			RevisionPW:     "NewPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // forbidden (not my auth)
 			UUID:           "parent-111111111113",
 			ParentUUID:     "parent-111111111114",
 			CurrentPW:      "testPW",
 			RevisionPW:     "NewPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // not exists parent
 			UUID:       "parent-111111111115",
@@ -157,9 +210,13 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			CurrentPW:  "testPW",
 			RevisionPW: "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentAuthWithUUID":                 {&model.ParentAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusNotFound,
 		}, { // 현재 Password 불일치
@@ -168,12 +225,16 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			CurrentPW:  "IncorrectPassword",
 			RevisionPW: "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetParentAuthWithUUID": {&model.ParentAuth{
 					UUID:     "parent-111111111116",
 					ParentPW: model.ParentPW(string(hashedTestPW)),
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectParentPWForChange,
@@ -183,9 +244,13 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			CurrentPW:  "testPW",
 			RevisionPW: "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":               {},
-				"GetParentAuthWithUUID": {&model.ParentAuth{}, errors.New("DB not connected")},
-				"Rollback":              {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentAuthWithUUID":                 {&model.ParentAuth{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // ChangeParentPW 에러 반환
@@ -194,13 +259,18 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			CurrentPW:  "testPW",
 			RevisionPW: "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetParentAuthWithUUID": {&model.ParentAuth{
 					UUID:     "parent-111111111118",
 					ParentPW: model.ParentPW(string(hashedTestPW)),
 				}, nil},
-				"ChangeParentPW": {errors.New("DB not connected")},
-				"Rollback":       {&gorm.DB{}},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeParentPW":                          {errors.New("DB not connected")},
+				"Rollback":                                {&gorm.DB{}},
+				"CreateAuthLog":                           {&model.AuthLog{}, nil},
+				"Commit":                                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // GetParentAuthWithUUID Short Hashed PW 반환
@@ -209,12 +279,16 @@ func Test_default_ChangeParentPW(t *testing.T) {
 			CurrentPW:  "testPW",
 			RevisionPW: "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetParentAuthWithUUID": {&model.ParentAuth{
 					UUID:     "parent-111111111119",
 					ParentPW: "TooShortHashedPasword",
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -249,7 +323,9 @@ func Test_default_GetParentInformWithUUID(t *testing.T) {
 			UUID:       "parent-111111111111",
 			ParentUUID: "parent-111111111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetParentInformWithUUID": {&model.ParentInform{
 					Model:       gorm.Model{CreatedAt: now, UpdatedAt: now},
 					ParentUUID:  "parent-111111111111",
@@ -286,20 +362,34 @@ func Test_default_GetParentInformWithUUID(t *testing.T) {
 		}, { // forbidden (not parent)
 			UUID:           "student-111111111112",
 			ParentUUID:     "student-111111111112",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 			ExpectedInform: &model.ParentInform{},
 		}, { // forbidden (not my auth)
 			UUID:           "parent-111111111113",
 			ParentUUID:     "parent-111111111114",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 			ExpectedInform: &model.ParentInform{},
 		}, { // no exist parent uuid
 			UUID:       "admin-111111111111",
 			ParentUUID: "parent-111111111115",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                 {},
-				"GetParentInformWithUUID": {&model.ParentInform{}, gorm.ErrRecordNotFound},
-				"Rollback":                {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentInformWithUUID":               {&model.ParentInform{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusNotFound,
 			ExpectedInform: &model.ParentInform{},
@@ -307,9 +397,11 @@ func Test_default_GetParentInformWithUUID(t *testing.T) {
 			UUID:       "admin-111111111112",
 			ParentUUID: "parent-111111111116",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                 {},
-				"GetParentInformWithUUID": {&model.ParentInform{}, errors.New("DB not connected")},
-				"Rollback":                {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentInformWithUUID":               {&model.ParentInform{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 			ExpectedInform: &model.ParentInform{},
@@ -349,9 +441,11 @@ func Test_default_GetParentUUIDsWithInform(t *testing.T) {
 			UUID: "admin-111111111111",
 			Name: "이성진",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetParentUUIDsWithInform": {[]string{"parent-123412341234", "parent-432143214321"}, nil},
-				"Commit":                   {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentUUIDsWithInform":              {[]string{"parent-123412341234", "parent-432143214321"}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:      http.StatusOK,
 			ExpectedParentUUIDs: []string{"parent-123412341234", "parent-432143214321"},
@@ -359,9 +453,11 @@ func Test_default_GetParentUUIDsWithInform(t *testing.T) {
 			UUID:        "parent-111111111111",
 			PhoneNumber: "01088378347",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetParentUUIDsWithInform": {[]string{"parent-111111111111"}, nil},
-				"Commit":                   {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentUUIDsWithInform":              {[]string{"parent-111111111111"}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:      http.StatusOK,
 			ExpectedParentUUIDs: []string{"parent-111111111111"},
@@ -387,14 +483,22 @@ func Test_default_GetParentUUIDsWithInform(t *testing.T) {
 			ExpectedStatus:    http.StatusProxyAuthRequired,
 		}, { // forbidden (not parent)
 			UUID:           "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // no exist parent uuid with that inform
 			UUID:        "admin-111111111111",
 			PhoneNumber: "01011111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetParentUUIDsWithInform": {[]string{}, gorm.ErrRecordNotFound},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentUUIDsWithInform":              {[]string{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.ParentWithThatInformNoExist,
@@ -402,9 +506,11 @@ func Test_default_GetParentUUIDsWithInform(t *testing.T) {
 			UUID:        "admin-111111111112",
 			PhoneNumber: "01012341234",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetParentUUIDsWithInform": {[]string{}, errors.New("I don't know about that error")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetParentUUIDsWithInform":              {[]string{}, errors.New("I don't know about that error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -0,0 +1,466 @@
+// add file in v.1.2.0
+// this file declare method that handling RPC about password reset (AuthPassword service) in _default struct
+// user who forgot password receive one-time code with SMS to phone number in inform, and reset password with that code
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/hash"
+	"auth/tool/random"
+	code "auth/utils/code/golang"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"time"
+)
+
+const (
+	passwordResetCodeLength          = 6
+	passwordResetCodeExpiration      = time.Minute * 10 // 인증 코드 유효 시간
+	passwordResetResendInterval      = time.Minute      // 인증 코드 재발송 최소 간격
+	passwordResetMaxSendCount        = 5                // 하루 동안 발송 가능한 최대 인증 코드 수
+	passwordResetSendCountExpiration = time.Hour * 24   // 마지막 발송 후 이 시간이 지나면 발송 횟수 초기화
+	passwordResetMaxAttemptCount     = 5                // 인증 코드 당 최대 입력 실패 횟수, 초과 시 재발송 필요
+)
+
+// method that return uuid and hashed pw of account with account type and id, it returns gorm.ErrRecordNotFound if account not exists
+func (h _default) accountWithID(access db.Accessor, accountType role, accountID string, parentSpan jaeger.SpanContext, reqID string) (uuid, hashedPW string, err error) {
+	spanForDB := h.tracer.StartSpan("GetAuthWithID", opentracing.ChildOf(parentSpan))
+	switch accountType {
+	case studentRole:
+		var auth *model.StudentAuth
+		if auth, err = access.GetStudentAuthWithID(accountID); err == nil {
+			uuid, hashedPW = string(auth.UUID), string(auth.StudentPW)
+		}
+	case teacherRole:
+		var auth *model.TeacherAuth
+		if auth, err = access.GetTeacherAuthWithID(accountID); err == nil {
+			uuid, hashedPW = string(auth.UUID), string(auth.TeacherPW)
+		}
+	case parentRole:
+		var auth *model.ParentAuth
+		if auth, err = access.GetParentAuthWithID(accountID); err == nil {
+			uuid, hashedPW = string(auth.UUID), string(auth.ParentPW)
+		}
+	}
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("AccountType", string(accountType)), log.String("SelectedUUID", uuid), log.Error(err))
+	spanForDB.Finish()
+	return
+}
+
+// method that return phone number in inform of account, it returns gorm.ErrRecordNotFound if inform not exists
+func (h _default) phoneNumberWithUUID(access db.Accessor, accountType role, uuid string, parentSpan jaeger.SpanContext, reqID string) (phoneNumber string, err error) {
+	spanForDB := h.tracer.StartSpan("GetInformWithUUID", opentracing.ChildOf(parentSpan))
+	switch accountType {
+	case studentRole:
+		var inform *model.StudentInform
+		if inform, err = access.GetStudentInformWithUUID(uuid); err == nil {
+			phoneNumber = string(inform.PhoneNumber)
+		}
+	case teacherRole:
+		var inform *model.TeacherInform
+		if inform, err = access.GetTeacherInformWithUUID(uuid); err == nil {
+			phoneNumber = string(inform.PhoneNumber)
+		}
+	case parentRole:
+		var inform *model.ParentInform
+		if inform, err = access.GetParentInformWithUUID(uuid); err == nil {
+			phoneNumber = string(inform.PhoneNumber)
+		}
+	}
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("AccountType", string(accountType)), log.Error(err))
+	spanForDB.Finish()
+	return
+}
+
+// function that return code meaning account id not exists with account type
+func idNoExistCodeOf(accountType role) (_code int32) {
+	switch accountType {
+	case studentRole:
+		_code = code.StudentIDNoExist
+	case teacherRole:
+		_code = code.TeacherIDNoExist
+	case parentRole:
+		_code = code.ParentIDNoExist
+	}
+	return
+}
+
+func (h _default) RequestPasswordReset(ctx context.Context, req *proto.RequestPasswordResetRequest, resp *proto.RequestPasswordResetResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	accountType := role(req.AccountType)
+	switch accountType {
+	case studentRole, teacherRole, parentRole:
+		break
+	default:
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid account type for password reset, type: " + req.AccountType)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	uuid, _, err := h.accountWithID(access, accountType, req.AccountID, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		switch err {
+		case gorm.ErrRecordNotFound:
+			resp.Status = http.StatusConflict
+			resp.Code = idNoExistCodeOf(accountType)
+			resp.Message = fmt.Sprintf(conflictErrorFormat, string(accountType) + " id not exists")
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetPasswordResetWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedReset, err := access.GetPasswordResetWithOwnerUUID(uuid)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedReset", selectedReset), log.Error(err))
+	spanForDB.Finish()
+
+	now := time.Now()
+	resetExists := err == nil
+	var sendCount int64 = 1
+	switch err {
+	case nil:
+		if now.Sub(selectedReset.LastSentAt) < passwordResetResendInterval {
+			access.Rollback()
+			resp.Status = http.StatusTooManyRequests
+			resp.Code = code.TooManyPasswordResetRequests
+			resp.Message = fmt.Sprintf(lockedMessageFormat, "password reset code was sent recently, please retry after a minute")
+			return
+		}
+		if now.Sub(selectedReset.LastSentAt) < passwordResetSendCountExpiration {
+			sendCount = selectedReset.SendCount + 1
+		}
+		if sendCount > passwordResetMaxSendCount {
+			access.Rollback()
+			resp.Status = http.StatusTooManyRequests
+			resp.Code = code.TooManyPasswordResetRequests
+			resp.Message = fmt.Sprintf(lockedMessageFormat, "password reset code was sent too many times today")
+			return
+		}
+	case gorm.ErrRecordNotFound:
+		break
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	phoneNumber, err := h.phoneNumberWithUUID(access, accountType, uuid, parentSpan, reqID)
+	if err == gorm.ErrRecordNotFound || (err == nil && phoneNumber == "") {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.PhoneNumberNotRegistered
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "phone number to send reset code is not registered")
+		return
+	} else if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	resetCode, err := random.SecureStringConsistOfIntWithLength(passwordResetCodeLength)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to generate reset code, err: " + err.Error())
+		return
+	}
+
+	reset := &model.PasswordReset{
+		OwnerUUID:    model.OwnerUUID(uuid),
+		CodeHash:     model.CodeHash(hash.SHA256ToHex(resetCode)),
+		ExpiresAt:    now.Add(passwordResetCodeExpiration),
+		AttemptCount: 0,
+		SendCount:    sendCount,
+		LastSentAt:   now,
+	}
+
+	if resetExists {
+		spanForDB = h.tracer.StartSpan("ModifyPasswordReset", opentracing.ChildOf(parentSpan))
+		err = access.ModifyPasswordReset(uuid, reset)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int64("SendCount", sendCount), log.Error(err))
+		spanForDB.Finish()
+	} else {
+		spanForDB = h.tracer.StartSpan("CreatePasswordReset", opentracing.ChildOf(parentSpan))
+		var createdReset *model.PasswordReset
+		createdReset, err = access.CreatePasswordReset(reset)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedReset", createdReset), log.Error(err))
+		spanForDB.Finish()
+	}
+
+	switch err.(type) {
+	case nil:
+		break
+	case validator.ValidationErrors:
+		access.Rollback()
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for password reset model, err: " + err.Error())
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to store password reset, err: " + err.Error())
+		return
+	}
+
+	smsData := passwordResetSMSData{ResetCode: resetCode, ExpirationMinutes: int(passwordResetCodeExpiration.Minutes())}
+	smsTemplate, content, err := h.renderMessage(access, passwordResetSMSTemplateName, smsData, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to render reset code message, err: " + err.Error())
+		return
+	}
+
+	spanForMsg := h.tracer.StartSpan("SendToReceivers", opentracing.ChildOf(parentSpan))
+	jsonResp, err := h.messageSender.SendToReceivers([]string{phoneNumber}, content, smsTemplate.MessageType, smsTemplate.Title)
+	spanForMsg.SetTag("X-Request-Id", reqID).LogFields(log.Object("JsonResponse", jsonResp), log.Error(err))
+	spanForMsg.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "fail to send reset code, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to send password reset code"
+	resp.ExpiresAt = reset.ExpiresAt.Unix()
+	return
+}
+
+func (h _default) ConfirmPasswordReset(ctx context.Context, req *proto.ConfirmPasswordResetRequest, resp *proto.ConfirmPasswordResetResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	accountType := role(req.AccountType)
+	switch accountType {
+	case studentRole, teacherRole, parentRole:
+		break
+	default:
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid account type for password reset, type: " + req.AccountType)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	uuid, currentHashedPW, err := h.accountWithID(access, accountType, req.AccountID, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		switch err {
+		case gorm.ErrRecordNotFound:
+			resp.Status = http.StatusConflict
+			resp.Code = idNoExistCodeOf(accountType)
+			resp.Message = fmt.Sprintf(conflictErrorFormat, string(accountType) + " id not exists")
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	}
+
+	// password is reset by owner of account proved with reset code
+	access.SetAuditContext(uuid, "ConfirmPasswordReset")
+
+	spanForDB := h.tracer.StartSpan("GetPasswordResetWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedReset, err := access.GetPasswordResetWithOwnerUUID(uuid)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedReset", selectedReset), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		switch err {
+		case gorm.ErrRecordNotFound:
+			resp.Status = http.StatusConflict
+			resp.Code = code.PasswordResetNotRequested
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "password reset is not requested")
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	}
+
+	if !time.Now().Before(selectedReset.ExpiresAt) {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.ExpiredPasswordResetCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "password reset code is expired")
+		return
+	}
+
+	// attempt is counted in DB before comparing code, so that parallel guesses can't exceed max attempt count
+	// attempt with correct code is also counted, but reset is deleted after password is changed
+	spanForDB = h.tracer.StartSpan("IncreasePasswordResetAttemptCount", opentracing.ChildOf(parentSpan))
+	err = access.IncreasePasswordResetAttemptCount(uuid, passwordResetMaxAttemptCount)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.TooManyPasswordResetAttempts
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "too many incorrect code, please request password reset again")
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to update DB, err: " + err.Error())
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash.SHA256ToHex(req.Code)), []byte(selectedReset.CodeHash)) != 1 {
+		// commit to store attempt count even though reset fails
+		access.Commit()
+		resp.Status = http.StatusConflict
+		resp.Code = code.IncorrectPasswordResetCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "incorrect password reset code")
+		return
+	}
+
+	if strong, status, _code, message := checkPasswordStrength(req.NewPW, req.AccountID); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	if allowed, status, _code, message := h.checkPasswordReuse(ctx, access, uuid, currentHashedPW, req.NewPW); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.NewPW)
+	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForHash.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to hash pw, err: " + err.Error())
+		return
+	}
+
+	changePW := map[role]func(uuid, pw string) error{
+		studentRole: access.ChangeStudentPW,
+		teacherRole: access.ChangeTeacherPW,
+		parentRole:  access.ChangeParentPW,
+	}[accountType]
+
+	spanForDB = h.tracer.StartSpan("ChangePW", opentracing.ChildOf(parentSpan))
+	err = changePW(uuid, hashedPW)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to update DB, err: " + err.Error())
+		return
+	}
+
+	if err = h.recordPasswordHistory(ctx, access, uuid, currentHashedPW); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("DeletePasswordReset", opentracing.ChildOf(parentSpan))
+	err = access.DeletePasswordReset(uuid)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to delete password reset, err: " + err.Error())
+		return
+	}
+
+	// sessions issued with old password are revoked, and account is unlocked because owner proved identity with code
+	spanForDB = h.tracer.StartSpan("CreateSessionRevocation", opentracing.ChildOf(parentSpan))
+	revocation, err := access.CreateSessionRevocation(&model.SessionRevocation{
+		OwnerUUID:     model.OwnerUUID(uuid),
+		RevokedBefore: time.Now(),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SessionRevocation", revocation), log.Error(err))
+	spanForDB.Finish()
+
+	if err == nil {
+		spanForDB = h.tracer.StartSpan("DeleteRefreshTokensWithOwnerUUID", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRefreshTokensWithOwnerUUID(uuid)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	}
+	if err == nil {
+		spanForDB = h.tracer.StartSpan("DeleteSessionsWithOwnerUUID", opentracing.ChildOf(parentSpan))
+		err = access.DeleteSessionsWithOwnerUUID(uuid)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	}
+	if err == nil {
+		err = h.resetLoginFailure(ctx, access, accountThrottleKey(string(accountType), req.AccountID))
+	}
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to revoke sessions, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to reset password"
+	return
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/hash"
+	code "auth/utils/code/golang"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_default_ConfirmPasswordReset(t *testing.T) {
+	codeHash := model.CodeHash(hash.SHA256ToHex("123456"))
+	expiresAt := time.Now().Add(passwordResetCodeExpiration)
+	hashedCurrentPW, _ := bcrypt.GenerateFromPassword([]byte("currentPW"), 1)
+
+	tests := []test.ConfirmPasswordResetCase{
+		{ // success case
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                                 {},
+				"GetStudentAuthWithID":                    {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID":           {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: expiresAt}, nil},
+				"IncreasePasswordResetAttemptCount":       {nil},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeStudentPW":                         {nil},
+				"CreatePasswordHistory":                   {&model.PasswordHistory{}, nil},
+				"DeletePasswordHistoriesExceptRecent":     {nil},
+				"DeletePasswordReset":                     {nil},
+				"CreateSessionRevocation":                 {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":        {nil},
+				"DeleteSessionsWithOwnerUUID":             {nil},
+				"DeleteLoginThrottle":                     {nil},
+				"Commit":                                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // success case (parent account)
+			AccountType: "parent",
+			AccountID:   "parentID",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                                 {},
+				"GetParentAuthWithID":                     {&model.ParentAuth{UUID: "parent-111111111111", ParentPW: model.ParentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID":           {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: expiresAt}, nil},
+				"IncreasePasswordResetAttemptCount":       {nil},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeParentPW":                          {nil},
+				"CreatePasswordHistory":                   {&model.PasswordHistory{}, nil},
+				"DeletePasswordHistoriesExceptRecent":     {nil},
+				"DeletePasswordReset":                     {nil},
+				"CreateSessionRevocation":                 {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":        {nil},
+				"DeleteSessionsWithOwnerUUID":             {nil},
+				"DeleteLoginThrottle":                     {nil},
+				"Commit":                                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // admin account can't reset password with SMS -> Proxy Authorization Required
+			AccountType:     "admin",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // student id no exists
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":              {},
+				"GetStudentAuthWithID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":             {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.StudentIDNoExist,
+		}, { // password reset not requested
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                       {},
+				"GetStudentAuthWithID":          {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID": {&model.PasswordReset{}, gorm.ErrRecordNotFound},
+				"Rollback":                      {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.PasswordResetNotRequested,
+		}, { // expired reset code
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                       {},
+				"GetStudentAuthWithID":          {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID": {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+				"Rollback":                      {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.ExpiredPasswordResetCode,
+		}, { // incorrect reset code -> attempt count stored
+			Code: "654321",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                           {},
+				"GetStudentAuthWithID":              {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID":     {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: expiresAt}, nil},
+				"IncreasePasswordResetAttemptCount": {nil},
+				"Commit":                            {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.IncorrectPasswordResetCode,
+		}, { // too many incorrect code
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                           {},
+				"GetStudentAuthWithID":              {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID":     {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: expiresAt, AttemptCount: passwordResetMaxAttemptCount}, nil},
+				"IncreasePasswordResetAttemptCount": {gorm.ErrRecordNotFound},
+				"Rollback":                          {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.TooManyPasswordResetAttempts,
+		}, { // IncreasePasswordResetAttemptCount unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                           {},
+				"GetStudentAuthWithID":              {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID":     {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: expiresAt}, nil},
+				"IncreasePasswordResetAttemptCount": {errors.New("unexpected error")},
+				"Rollback":                          {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // ChangeStudentPW unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                                 {},
+				"GetStudentAuthWithID":                    {&model.StudentAuth{UUID: "student-111111111111", StudentPW: model.StudentPW(string(hashedCurrentPW))}, nil},
+				"GetPasswordResetWithOwnerUUID":           {&model.PasswordReset{CodeHash: codeHash, ExpiresAt: expiresAt}, nil},
+				"IncreasePasswordResetAttemptCount":       {nil},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeStudentPW":                         {errors.New("unexpected error")},
+				"Rollback":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.ConfirmPasswordResetRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.ConfirmPasswordResetResponse)
+		_ = defaultHandler.ConfirmPasswordReset(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,318 @@
+// add file in v.1.2.0
+// this file declare method that handling academic year rollover RPC (in AuthAdmin service) in _default struct
+// rollover promote 1st and 2nd grade students with new class and student number, and archive & delete graduating 3rd grade students in one tx
+
+package handler
+
+import (
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/dberr"
+	code "auth/utils/code/golang"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	lastGrade        = 3
+	maxClass         = 4
+	maxStudentNumber = 21
+)
+
+// reasons of rollover conflict reported to client
+const (
+	rolloverConflictDuplicateNumber   = "duplicate grade, class and student number"
+	rolloverConflictMissingAssignment = "promoted student has no assignment"
+	rolloverConflictUnknownStudent    = "assigned student not exists"
+	rolloverConflictGraduating        = "graduating student can't be assigned"
+	rolloverConflictDuplicateStudent  = "student is assigned more than once"
+	rolloverConflictOutOfRange        = "class or student number is out of range"
+)
+
+// new class and student number of student in next academic year, read from one row of assignment file
+type classAssignment struct {
+	studentUUID   string
+	class         int64
+	studentNumber int64
+}
+
+// plan of academic year rollover, informs of promoted students have grade, class and student number of next year
+type rolloverPlan struct {
+	promoted  []*model.StudentInform
+	graduates []*model.StudentInform
+	conflicts []*proto.RolloverConflict
+}
+
+// function that parse assignment file in csv format with rows of "student_uuid,class,student_number" (header row is optional)
+func parseClassAssignments(file []byte) (assignments []classAssignment, err error) {
+	reader := csv.NewReader(bytes.NewReader(file))
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			err = readErr
+			return
+		}
+		if line == 1 && strings.EqualFold(record[0], "student_uuid") {
+			continue
+		}
+
+		assignment := classAssignment{studentUUID: record[0]}
+		if assignment.class, err = strconv.ParseInt(record[1], 10, 64); err != nil {
+			err = fmt.Errorf("line %d: invalid class, err: %v", line, err)
+			return
+		}
+		if assignment.studentNumber, err = strconv.ParseInt(record[2], 10, 64); err != nil {
+			err = fmt.Errorf("line %d: invalid student number, err: %v", line, err)
+			return
+		}
+		assignments = append(assignments, assignment)
+	}
+	return
+}
+
+// function that return plan of rollover with current student informs and assignments of next academic year
+// conflicts are detected with same rule as alive unique key of grade, class and student number in student_informs table
+func planRollover(informs []*model.StudentInform, assignments []classAssignment) (plan rolloverPlan) {
+	informWithUUID := make(map[string]*model.StudentInform, len(informs))
+	for _, inform := range informs {
+		informWithUUID[string(inform.StudentUUID)] = inform
+	}
+
+	conflictOf := func(uuid string, grade, class, number int64, reason string) *proto.RolloverConflict {
+		return &proto.RolloverConflict{StudentUUID: uuid, Grade: uint32(grade), Class: uint32(class), StudentNumber: uint32(number), Reason: reason}
+	}
+
+	assignmentWithUUID := make(map[string]classAssignment, len(assignments))
+	for _, assignment := range assignments {
+		inform, exists := informWithUUID[assignment.studentUUID]
+		switch {
+		case !exists:
+			plan.conflicts = append(plan.conflicts, conflictOf(assignment.studentUUID, 0, assignment.class, assignment.studentNumber, rolloverConflictUnknownStudent))
+			continue
+		case inform.Grade >= lastGrade:
+			plan.conflicts = append(plan.conflicts, conflictOf(assignment.studentUUID, int64(inform.Grade), assignment.class, assignment.studentNumber, rolloverConflictGraduating))
+			continue
+		}
+		if _, duplicated := assignmentWithUUID[assignment.studentUUID]; duplicated {
+			plan.conflicts = append(plan.conflicts, conflictOf(assignment.studentUUID, int64(inform.Grade) + 1, assignment.class, assignment.studentNumber, rolloverConflictDuplicateStudent))
+			continue
+		}
+		assignmentWithUUID[assignment.studentUUID] = assignment
+	}
+
+	takenBy := map[string]string{}
+	for _, inform := range informs {
+		uuid := string(inform.StudentUUID)
+		if inform.Grade >= lastGrade {
+			plan.graduates = append(plan.graduates, inform)
+			continue
+		}
+
+		nextGrade := int64(inform.Grade) + 1
+		assignment, assigned := assignmentWithUUID[uuid]
+		if !assigned {
+			plan.conflicts = append(plan.conflicts, conflictOf(uuid, nextGrade, 0, 0, rolloverConflictMissingAssignment))
+			continue
+		}
+		if assignment.class < 1 || assignment.class > maxClass || assignment.studentNumber < 1 || assignment.studentNumber > maxStudentNumber {
+			plan.conflicts = append(plan.conflicts, conflictOf(uuid, nextGrade, assignment.class, assignment.studentNumber, rolloverConflictOutOfRange))
+			continue
+		}
+
+		key := fmt.Sprintf("%d%d%02d", nextGrade, assignment.class, assignment.studentNumber)
+		if other, taken := takenBy[key]; taken {
+			plan.conflicts = append(plan.conflicts, conflictOf(uuid, nextGrade, assignment.class, assignment.studentNumber, rolloverConflictDuplicateNumber + " with " + other))
+			continue
+		}
+		takenBy[key] = uuid
+
+		plan.promoted = append(plan.promoted, &model.StudentInform{
+			StudentUUID:   inform.StudentUUID,
+			Grade:         model.Grade(nextGrade),
+			Class:         model.Class(assignment.class),
+			StudentNumber: model.StudentNumber(assignment.studentNumber),
+		})
+	}
+
+	// students are moved from higher grade, so that every student moves into seat already emptied in the grade
+	sort.SliceStable(plan.promoted, func(i, j int) bool { return plan.promoted[i].Grade > plan.promoted[j].Grade })
+	return
+}
+
+func (h _default) RolloverAcademicYear(ctx context.Context, req *proto.RolloverAcademicYearRequest, resp *proto.RolloverAcademicYearResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	if req.AcademicYear < 2000 || req.AcademicYear > 2100 {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, fmt.Sprintf("invalid academic year, year: %d", req.AcademicYear))
+		return
+	}
+
+	assignments, err := parseClassAssignments(req.AssignmentFile)
+	if err != nil {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "unable to parse assignment file, err: " + err.Error())
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "RolloverAcademicYear", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetAllStudentInformsForUpdate", opentracing.ChildOf(parentSpan))
+	selectedInforms, err := access.GetAllStudentInformsForUpdate()
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int("SelectedInformCount", len(selectedInforms)), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	resp.DryRun = req.DryRun
+	plan := planRollover(selectedInforms, assignments)
+	if len(plan.conflicts) != 0 {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.AcademicYearRolloverConflict
+		resp.Message = fmt.Sprintf(conflictErrorFormat, fmt.Sprintf("%d conflicts found in rollover plan, nothing is changed", len(plan.conflicts)))
+		resp.Conflicts = plan.conflicts
+		return
+	}
+
+	// graduates are archived and deleted first, so that 2nd grade students can be moved into seats of 3rd grade
+	for _, graduate := range plan.graduates {
+		spanForDB = h.tracer.StartSpan("CreateGraduatedStudent", opentracing.ChildOf(parentSpan))
+		archived, err := access.CreateGraduatedStudent(&model.GraduatedStudent{
+			StudentUUID:   graduate.StudentUUID,
+			AcademicYear:  int64(req.AcademicYear),
+			Grade:         graduate.Grade,
+			Class:         graduate.Class,
+			StudentNumber: graduate.StudentNumber,
+			Name:          graduate.Name,
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("ArchivedStudent", archived), log.Error(err))
+		spanForDB.Finish()
+
+		if err == nil {
+			err = h.deleteAccount(access, studentRole, string(graduate.StudentUUID), parentSpan, reqID)
+		}
+
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, fmt.Sprintf("unable to archive graduate, uuid: %s, err: %v", graduate.StudentUUID, err))
+			return
+		}
+	}
+
+	for _, promoted := range plan.promoted {
+		spanForDB = h.tracer.StartSpan("ModifyStudentInform", opentracing.ChildOf(parentSpan))
+		err = access.ModifyStudentInform(string(promoted.StudentUUID), &model.StudentInform{
+			Grade:         promoted.Grade,
+			Class:         promoted.Class,
+			StudentNumber: promoted.StudentNumber,
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("PromotedInform", promoted), log.Error(err))
+		spanForDB.Finish()
+
+		// children registered by parent are moved with student, so that parent finds child with inform of new academic year
+		if err == nil {
+			spanForDB = h.tracer.StartSpan("ModifyParentChildrenWithStudentUUID", opentracing.ChildOf(parentSpan))
+			err = access.ModifyParentChildrenWithStudentUUID(string(promoted.StudentUUID), &model.ParentChildren{
+				Grade:         promoted.Grade,
+				Class:         promoted.Class,
+				StudentNumber: promoted.StudentNumber,
+			})
+			spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("StudentUUID", string(promoted.StudentUUID)), log.Error(err))
+			spanForDB.Finish()
+		}
+
+		switch assertedError := dberr.From(err).(type) {
+		case nil:
+			continue
+		case validator.ValidationErrors:
+			access.Rollback()
+			resp.Status = http.StatusProxyAuthRequired
+			resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for student inform, err: " + err.Error())
+			return
+		case *dberr.Error:
+			// seat is taken by student not in plan (ex: student added while rollover), it is reported as conflict
+			if assertedError.Category == dberr.Duplicate {
+				access.Rollback()
+				resp.Status = http.StatusConflict
+				resp.Code = code.AcademicYearRolloverConflict
+				resp.Message = fmt.Sprintf(conflictErrorFormat, "seat of promoted student is already taken, nothing is changed")
+				resp.Conflicts = []*proto.RolloverConflict{{
+					StudentUUID:   string(promoted.StudentUUID),
+					Grade:         uint32(promoted.Grade),
+					Class:         uint32(promoted.Class),
+					StudentNumber: uint32(promoted.StudentNumber),
+					Reason:        rolloverConflictDuplicateNumber,
+				}}
+				return
+			}
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected error while promoting student, err: " + assertedError.Error())
+			return
+		default:
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "promoting student returns unexpected type of error, err: " + err.Error())
+			return
+		}
+	}
+
+	resp.PromotedCount = uint32(len(plan.promoted))
+	resp.GraduatedCount = uint32(len(plan.graduates))
+
+	// dry run executes every change in tx to check it with DB, and roll it back at the end
+	if req.DryRun {
+		access.Rollback()
+		resp.Status = http.StatusOK
+		resp.Message = "succeed to dry run academic year rollover, nothing is changed"
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to rollover academic year"
+	return
+}
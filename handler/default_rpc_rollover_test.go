@@ -0,0 +1,187 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func currentStudentInforms() []*model.StudentInform {
+	return []*model.StudentInform{
+		{StudentUUID: "student-111111111111", Grade: 3, Class: 1, StudentNumber: 1, Name: "졸업생"},
+		{StudentUUID: "student-111111111112", Grade: 2, Class: 1, StudentNumber: 1, Name: "이학년"},
+		{StudentUUID: "student-111111111113", Grade: 1, Class: 2, StudentNumber: 3, Name: "일학년"},
+	}
+}
+
+func Test_default_RolloverAcademicYear(t *testing.T) {
+	const validAssignmentFile = "student_uuid,class,student_number\nstudent-111111111112,1,1\nstudent-111111111113,3,5\n"
+
+	tests := []test.RolloverAcademicYearCase{
+		{ // success case
+			AssignmentFile: validAssignmentFile,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAllStudentInformsForUpdate":         {currentStudentInforms(), nil},
+				"CreateGraduatedStudent":                {&model.GraduatedStudent{}, nil},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{UUID: "student-111111111111"}, nil},
+				"DeleteStudentAuth":                     {nil},
+				"DeleteStudentInform":                   {nil},
+				"DeleteParentChildrenWithStudentUUID":   {nil},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"ModifyStudentInform":                   {nil},
+				"ModifyParentChildrenWithStudentUUID":   {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedChildRevisions: map[string]*model.ParentChildren{
+				"student-111111111112": {Grade: 3, Class: 1, StudentNumber: 1},
+				"student-111111111113": {Grade: 2, Class: 3, StudentNumber: 5},
+			},
+			ExpectedStatus:         http.StatusOK,
+			ExpectedPromotedCount:  2,
+			ExpectedGraduatedCount: 1,
+		}, { // dry run -> every change is rolled back
+			AssignmentFile: validAssignmentFile,
+			DryRun:         true,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAllStudentInformsForUpdate":         {currentStudentInforms(), nil},
+				"CreateGraduatedStudent":                {&model.GraduatedStudent{}, nil},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{UUID: "student-111111111111"}, nil},
+				"DeleteStudentAuth":                     {nil},
+				"DeleteStudentInform":                   {nil},
+				"DeleteParentChildrenWithStudentUUID":   {nil},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"ModifyStudentInform":                   {nil},
+				"ModifyParentChildrenWithStudentUUID":   {nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus:         http.StatusOK,
+			ExpectedPromotedCount:  2,
+			ExpectedGraduatedCount: 1,
+		}, { // student assigned twice, graduating student assigned & student without assignment -> conflict
+			AssignmentFile: "student-111111111112,1,1\nstudent-111111111112,1,2\nstudent-111111111111,2,2\n",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAllStudentInformsForUpdate":         {currentStudentInforms(), nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus:        http.StatusConflict,
+			ExpectedCode:          code.AcademicYearRolloverConflict,
+			ExpectedConflictCount: 3,
+		}, { // promoted student without assignment & out of range class -> conflict
+			AssignmentFile: "student-111111111113,5,1\n",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAllStudentInformsForUpdate":         {currentStudentInforms(), nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus:        http.StatusConflict,
+			ExpectedCode:          code.AcademicYearRolloverConflict,
+			ExpectedConflictCount: 2,
+		}, { // seat taken by student not in plan -> conflict
+			AssignmentFile: validAssignmentFile,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAllStudentInformsForUpdate":         {currentStudentInforms(), nil},
+				"CreateGraduatedStudent":                {&model.GraduatedStudent{}, nil},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{UUID: "student-111111111111"}, nil},
+				"DeleteStudentAuth":                     {nil},
+				"DeleteStudentInform":                   {nil},
+				"DeleteParentChildrenWithStudentUUID":   {nil},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"ModifyStudentInform":                   {&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus:        http.StatusConflict,
+			ExpectedCode:          code.AcademicYearRolloverConflict,
+			ExpectedConflictCount: 1,
+		}, { // seat of parent children taken by child not in plan -> conflict
+			AssignmentFile: validAssignmentFile,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetAllStudentInformsForUpdate":         {currentStudentInforms(), nil},
+				"CreateGraduatedStudent":                {&model.GraduatedStudent{}, nil},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{UUID: "student-111111111111"}, nil},
+				"DeleteStudentAuth":                     {nil},
+				"DeleteStudentInform":                   {nil},
+				"DeleteParentChildrenWithStudentUUID":   {nil},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"ModifyStudentInform":                   {nil},
+				"ModifyParentChildrenWithStudentUUID":   {&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus:        http.StatusConflict,
+			ExpectedCode:          code.AcademicYearRolloverConflict,
+			ExpectedConflictCount: 1,
+		}, { // invalid academic year -> Proxy Authorization Required
+			AcademicYear:    1999,
+			AssignmentFile:  validAssignmentFile,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // unparsable assignment file -> Proxy Authorization Required
+			AssignmentFile:  "student-111111111112,first,1\n",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID:           "teacher-111111111111",
+			AssignmentFile: validAssignmentFile,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.RolloverAcademicYearRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.RolloverAcademicYearResponse)
+		_ = defaultHandler.RolloverAcademicYear(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedPromotedCount, resp.PromotedCount, "promoted count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedGraduatedCount, resp.GraduatedCount, "graduated count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Lenf(t, resp.Conflicts, testCase.ExpectedConflictCount, "conflict count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
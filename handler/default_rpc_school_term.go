@@ -0,0 +1,161 @@
+// add file in v.1.2.0
+// this file declare method that handling school term RPC (in AuthAdmin service) in _default struct
+// school term decide academic year of S3 profile path and school name & signup deadline in join SMS, instead of hardcoded value
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"time"
+)
+
+const (
+	preProfileURIFormat = "profiles/years/%d/grades/%d/groups/%d/numbers/%d"
+	signupDeadlineLayout = "2006-01-02" // layout of signup deadline in request & response
+)
+
+var koreanWeekdays = [...]string{"일", "월", "화", "수", "목", "금", "토"}
+
+// function that return S3 key of pre profile of student in academic year of school term
+func preProfileURIOf(term *model.SchoolTerm, grade, class, studentNumber int64) string {
+	return fmt.Sprintf(preProfileURIFormat, term.AcademicYear, grade, class, studentNumber)
+}
+
+// function that return signup deadline of school term in format used in SMS (ex: 3/11(목))
+func signupDeadlineTextOf(term *model.SchoolTerm) string {
+	deadline := term.SignupDeadline
+	return fmt.Sprintf("%d/%d(%s)", deadline.Month(), deadline.Day(), koreanWeekdays[deadline.Weekday()])
+}
+
+// method that return current school term, status & code & message is set if school term can't be returned
+func (h _default) currentSchoolTerm(access db.Accessor, parentSpan jaeger.SpanContext, reqID string) (term *model.SchoolTerm, status uint32, _code int32, message string) {
+	spanForDB := h.tracer.StartSpan("GetCurrentSchoolTerm", opentracing.ChildOf(parentSpan))
+	term, err := access.GetCurrentSchoolTerm()
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedTerm", term), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		return
+	case gorm.ErrRecordNotFound:
+		status = http.StatusConflict
+		_code = code.SchoolTermNotConfigured
+		message = fmt.Sprintf(conflictErrorFormat, "school term is not configured yet, please configure it with ChangeSchoolTerm")
+	default:
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+	}
+	return
+}
+
+func (h _default) GetSchoolTerm(ctx context.Context, req *proto.GetSchoolTermRequest, resp *proto.GetSchoolTermResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetSchoolTerm", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	term, status, _code, message := h.currentSchoolTerm(access, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to get current school term"
+	resp.AcademicYear = uint32(term.AcademicYear)
+	resp.SignupDeadline = term.SignupDeadline.Format(signupDeadlineLayout)
+	resp.SchoolName = term.SchoolName
+	return
+}
+
+func (h _default) ChangeSchoolTerm(ctx context.Context, req *proto.ChangeSchoolTermRequest, resp *proto.ChangeSchoolTermResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	signupDeadline, err := time.ParseInLocation(signupDeadlineLayout, req.SignupDeadline, time.Local)
+	if err != nil {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid signup deadline, it must be in YYYY-MM-DD format, deadline: " + req.SignupDeadline)
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeSchoolTerm", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("CreateSchoolTerm", opentracing.ChildOf(parentSpan))
+	createdTerm, err := access.CreateSchoolTerm(&model.SchoolTerm{
+		AcademicYear:   int64(req.AcademicYear),
+		SignupDeadline: signupDeadline,
+		SchoolName:     req.SchoolName,
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedTerm", createdTerm), log.Error(err))
+	spanForDB.Finish()
+
+	switch err.(type) {
+	case nil:
+		break
+	case validator.ValidationErrors:
+		access.Rollback()
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for school term, err: " + err.Error())
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to create school term, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusCreated
+	resp.Message = "succeed to change school term"
+	return
+}
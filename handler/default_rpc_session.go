@@ -0,0 +1,151 @@
+// add file in v.1.2.0
+// this file declare method that handling RPC about login session (AuthSession service) in _default struct
+
+package handler
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+)
+
+func (h _default) ListMySessions(ctx context.Context, req *proto.ListMySessionsRequest, resp *proto.ListMySessionsResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "ListMySessions", req.UUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetSessionsWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedSessions, err := access.GetSessionsWithOwnerUUID(req.UUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedSessions", selectedSessions), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	// session of access token used for this request is marked, so that client doesn't sign out itself by mistake
+	currentSessionID := callerOf(ctx).SessionID
+	sessions := make([]*proto.Session, len(selectedSessions))
+	for i, selectedSession := range selectedSessions {
+		sessions[i] = &proto.Session{
+			SessionID:  string(selectedSession.SessionID),
+			Device:     selectedSession.Device,
+			UserAgent:  selectedSession.UserAgent,
+			IPAddress:  selectedSession.IPAddress,
+			CreatedAt:  selectedSession.CreatedAt.Unix(),
+			LastSeenAt: selectedSession.LastSeenAt.Unix(),
+			Current:    currentSessionID != "" && string(selectedSession.SessionID) == currentSessionID,
+		}
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to list sessions"
+	resp.Sessions = sessions
+	return
+}
+
+func (h _default) RevokeSession(ctx context.Context, req *proto.RevokeSessionRequest, resp *proto.RevokeSessionResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "RevokeSession", req.UUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetSessionWithSessionID", opentracing.ChildOf(parentSpan))
+	selectedSession, err := access.GetSessionWithSessionID(req.SessionID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedSession", selectedSession), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		access.Rollback()
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, "session not exists or already revoked")
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	// session of other account is treated as not exists, so that caller can't find out session id of other account
+	if string(selectedSession.OwnerUUID) != req.UUID {
+		access.Rollback()
+		resp.Status = http.StatusNotFound
+		resp.Message = fmt.Sprintf(notFoundMessageFormat, "session not exists or already revoked")
+		return
+	}
+
+	// access tokens of the session are rejected in verifyAccessToken after session is deleted
+	spanForDB = h.tracer.StartSpan("DeleteSession", opentracing.ChildOf(parentSpan))
+	err = access.DeleteSession(req.SessionID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err == nil {
+		spanForDB = h.tracer.StartSpan("DeleteRefreshTokensWithSessionID", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRefreshTokensWithSessionID(req.SessionID)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	}
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to revoke session, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to revoke session"
+	return
+}
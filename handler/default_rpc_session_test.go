@@ -0,0 +1,199 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_default_ListMySessions(t *testing.T) {
+	currentSession := &model.Session{SessionID: "3f1d2c4b-5a6e-4f70-8b9c-1d2e3f4a5b6c", OwnerUUID: "student-111111111111", Device: "iPhone 12", LastSeenAt: time.Now()}
+	otherSession := &model.Session{SessionID: "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b", OwnerUUID: "student-111111111111", Device: "Galaxy S20", LastSeenAt: time.Now().Add(-time.Hour)}
+
+	tests := []test.ListMySessionsCase{
+		{ // success case
+			UUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {currentSession, nil},
+				"GetSessionsWithOwnerUUID":              {[]*model.Session{currentSession, otherSession}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:           http.StatusOK,
+			ExpectedSessionCount:     2,
+			ExpectedCurrentSessionID: "3f1d2c4b-5a6e-4f70-8b9c-1d2e3f4a5b6c",
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // no exist access token -> Unauthorized
+			UUID: test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":  {},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+		}, { // session of access token is revoked -> Unauthorized
+			UUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {&model.Session{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.RevokedAccessToken,
+		}, { // list sessions of other account -> forbidden
+			UUID:       "student-111111111111",
+			TargetUUID: "student-111111111112",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {currentSession, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // GetSessionsWithOwnerUUID unexpected error
+			UUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {currentSession, nil},
+				"GetSessionsWithOwnerUUID":              {[]*model.Session{}, errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.ListMySessionsRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.ListMySessionsResponse)
+		_ = defaultHandler.ListMySessions(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedSessionCount, len(resp.Sessions), "session count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		currentSessionID := ""
+		for _, session := range resp.Sessions {
+			if session.Current {
+				currentSessionID = session.SessionID
+			}
+		}
+		assert.Equalf(t, testCase.ExpectedCurrentSessionID, currentSessionID, "current session assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_RevokeSession(t *testing.T) {
+	session := &model.Session{SessionID: "3f1d2c4b-5a6e-4f70-8b9c-1d2e3f4a5b6c", OwnerUUID: "parent-111111111111"}
+
+	tests := []test.RevokeSessionCase{
+		{ // success case
+			UUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {session, nil},
+				"DeleteSession":                         {nil},
+				"DeleteRefreshTokensWithSessionID":      {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // success case (revoke session of other account by admin)
+			UUID:       "admin-111111111111",
+			TargetUUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {session, nil},
+				"DeleteSession":                         {nil},
+				"DeleteRefreshTokensWithSessionID":      {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist Span-Context -> Proxy Authorization Required
+			SpanContextString: test.EmptyReplaceValueForString,
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // revoke session of other account by not admin -> forbidden
+			UUID:       "student-111111111111",
+			TargetUUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {&model.Session{OwnerUUID: "student-111111111111"}, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // session id of other account -> not found
+			UUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {&model.Session{OwnerUUID: "parent-111111111112"}, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		}, { // DeleteRefreshTokensWithSessionID unexpected error
+			UUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetSessionWithSessionID":               {session, nil},
+				"DeleteSession":                         {nil},
+				"DeleteRefreshTokensWithSessionID":      {errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.RevokeSessionRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.RevokeSessionResponse)
+		_ = defaultHandler.RevokeSession(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
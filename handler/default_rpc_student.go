@@ -3,30 +3,27 @@ package handler
 import (
 	"auth/model"
 	proto "auth/proto/golang/auth"
+	"auth/tool/dberr"
 	"auth/tool/hash"
-	"auth/tool/message"
-	"auth/tool/mysqlerr"
-	"auth/tool/random"
 	code "auth/utils/code/golang"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	mysqlcode "github.com/VividCortex/mysqlerr"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/go-playground/validator/v10"
-	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/uber/jaeger-client-go"
-	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"net/url"
 	"reflect"
 	"regexp"
 	"strconv"
+	"time"
 )
 
 func (h _default) LoginStudentAuth(ctx context.Context, req *proto.LoginStudentAuthRequest, resp *proto.LoginStudentAuthResponse) (_ error) {
@@ -40,6 +37,11 @@ func (h _default) LoginStudentAuth(ctx context.Context, req *proto.LoginStudentA
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "LoginStudentAuth", accountType: studentRole, accountID: req.StudentID, ownerUUID: resp.LoggedInStudentUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -47,19 +49,27 @@ func (h _default) LoginStudentAuth(ctx context.Context, req *proto.LoginStudentA
 		return
 	}
 
+	accountKey := accountThrottleKey(string(studentRole), req.StudentID)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, accountKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetStudentAuthWithID", opentracing.ChildOf(parentSpan))
 	resultAuth, err := access.GetStudentAuthWithID(req.StudentID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", resultAuth), log.Error(err))
 	spanForDB.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
 		case gorm.ErrRecordNotFound:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.StudentIDNoExist
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "student id not exists")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " +err.Error())
 		}
@@ -72,23 +82,49 @@ func (h _default) LoginStudentAuth(ctx context.Context, req *proto.LoginStudentA
 	spanForHash.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
 		case hash.ErrMismatchedHashAndPassword:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.IncorrectStudentPWForLogin
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "hash compare error, err: " + err.Error())
 		}
 		return
 	}
 
+	err = h.rehashPWIfNeeded(access.ChangeStudentPW, string(resultAuth.UUID), string(resultAuth.StudentPW), req.StudentPW, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	if err = h.resetLoginFailure(ctx, access, accountKey); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	accessToken, refreshToken, err := h.startSession(ctx, access, string(resultAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "succeed to login student auth"
 	resp.LoggedInStudentUUID = string(resultAuth.UUID)
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
 
 	return
 }
@@ -101,20 +137,14 @@ func (h _default) ChangeStudentPW(ctx context.Context, req *proto.ChangeStudentP
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.StudentUUID) && req.UUID == req.StudentUUID:
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin uuid OR not your student uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "ChangeStudentPW", accountType: studentRole, ownerUUID: req.StudentUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -122,6 +152,12 @@ func (h _default) ChangeStudentPW(ctx context.Context, req *proto.ChangeStudentP
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeStudentPW", req.StudentUUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetStudentAuthWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetStudentAuthWithUUID(req.StudentUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -159,8 +195,20 @@ func (h _default) ChangeStudentPW(ctx context.Context, req *proto.ChangeStudentP
 		return
 	}
 
+	if strong, status, _code, message := checkPasswordStrength(req.RevisionPW, string(selectedAuth.StudentID)); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	if allowed, status, _code, message := h.checkPasswordReuse(ctx, access, string(selectedAuth.UUID), string(selectedAuth.StudentPW), req.RevisionPW); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForHash = h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.RevisionPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.RevisionPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -172,7 +220,7 @@ func (h _default) ChangeStudentPW(ctx context.Context, req *proto.ChangeStudentP
 	}
 
 	spanForDB = h.tracer.StartSpan("ChangeStudentPW", opentracing.ChildOf(parentSpan))
-	err = access.ChangeStudentPW(string(selectedAuth.UUID), string(hashedBytes))
+	err = access.ChangeStudentPW(string(selectedAuth.UUID), hashedPW)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForDB.Finish()
 
@@ -183,6 +231,13 @@ func (h _default) ChangeStudentPW(ctx context.Context, req *proto.ChangeStudentP
 		return
 	}
 
+	if err = h.recordPasswordHistory(ctx, access, string(selectedAuth.UUID), string(selectedAuth.StudentPW)); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "student pw change success"
@@ -197,21 +252,6 @@ func (h _default) GetStudentInformWithUUID(ctx context.Context, req *proto.GetSt
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -222,6 +262,12 @@ func (h _default) GetStudentInformWithUUID(ctx context.Context, req *proto.GetSt
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetStudentInformWithUUID", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetStudentInformWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetStudentInformWithUUID(req.StudentUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -284,17 +330,6 @@ func (h _default) GetParentWithStudentUUID(ctx context.Context, req *proto.GetPa
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.StudentUUID) && req.UUID == req.StudentUUID:
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not your student uuid or not admin uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -305,6 +340,12 @@ func (h _default) GetParentWithStudentUUID(ctx context.Context, req *proto.GetPa
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetParentWithStudentUUID", req.StudentUUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetStudentAuthWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetStudentAuthWithUUID(req.StudentUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -353,21 +394,6 @@ func (h _default) GetStudentInformsWithUUIDs(ctx context.Context, req *proto.Get
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -378,6 +404,12 @@ func (h _default) GetStudentInformsWithUUIDs(ctx context.Context, req *proto.Get
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetStudentInformsWithUUIDs", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetStudentInformsWithUUIDs", opentracing.ChildOf(parentSpan))
 	selectedInforms, err := access.GetStudentInformsWithUUIDs(req.StudentUUIDs)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedInforms", selectedInforms), log.Error(err))
@@ -423,21 +455,6 @@ func (h _default) GetStudentUUIDsWithInform(ctx context.Context, req *proto.GetS
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -448,6 +465,12 @@ func (h _default) GetStudentUUIDsWithInform(ctx context.Context, req *proto.GetS
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetStudentUUIDsWithInform", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	informToSelect := &model.StudentInform{
 		Grade:         model.Grade(int64(req.Grade)),
 		Class:         model.Class(int64(req.Group)),
@@ -524,6 +547,14 @@ func (h _default) GetUnsignedStudentWithAuthCode(ctx context.Context, req *proto
 		return
 	}
 
+	if authCodeExpired(selectedStudent, time.Now()) {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.ExpiredSignupAuthCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "auth code is expired, please ask admin to regenerate it")
+		return
+	}
+
 	access.Commit()
 	resp.AuthCode = uint32(selectedStudent.AuthCode)
 	resp.Name = string(selectedStudent.Name)
@@ -556,6 +587,9 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		return
 	}
 
+	// student signs up with auth code instead of access token, so audit event of this RPC has no actor
+	access.SetAuditContext("", "CreateNewStudentWithAuthCode")
+
 	spanForDB := h.tracer.StartSpan("GetUnsignedStudentWithAuthCode", opentracing.ChildOf(parentSpan))
 	student, err := access.GetUnsignedStudentWithAuthCode(int64(req.AuthCode))
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedStudent", student), log.Error(err))
@@ -574,23 +608,12 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		return
 	}
 
-	var sUUID string
-	for {
-		sUUID = fmt.Sprintf("student-%s", random.StringConsistOfIntWithLength(12))
-		spanForDB := h.tracer.StartSpan("GetStudentAuthWithUUID", opentracing.ChildOf(parentSpan))
-		selectedAuth, err := access.GetStudentAuthWithUUID(sUUID)
-		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("selectedAuth", selectedAuth), log.Error(err))
-		spanForDB.Finish()
-		if err == gorm.ErrRecordNotFound {
-			break
-		}
-		if err != nil {
-			access.Rollback()
-			resp.Status = http.StatusInternalServerError
-			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
-			return
-		}
-		continue
+	if authCodeExpired(student, time.Now()) {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.ExpiredSignupAuthCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "auth code is expired, please ask admin to regenerate it")
+		return
 	}
 
 	spanForDB = h.tracer.StartSpan("GetParentChildWithInform", opentracing.ChildOf(parentSpan))
@@ -618,9 +641,14 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 	if regexp.MustCompile("^pbkdf2:sha\\d+(:\\d+)?\\$.*\\$.*$").MatchString(req.StudentPW) {
 		hashedPW = req.StudentPW
 	} else {
+		if strong, status, _code, message := checkPasswordStrength(req.StudentPW, req.StudentID); !strong {
+			access.Rollback()
+			resp.Status, resp.Code, resp.Message = status, _code, message
+			return
+		}
+
 		spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-		hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.StudentPW), bcrypt.MinCost)
-		hashedPW = string(hashedBytes)
+		hashedPW, err = hashPolicy.GenerateFromPassword(req.StudentPW)
 		spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 		spanForHash.Finish()
 
@@ -632,17 +660,21 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		}
 	}
 
-	spanForDB = h.tracer.StartSpan("CreateStudentAuth", opentracing.ChildOf(parentSpan))
-	resultAuth, err := access.CreateStudentAuth(&model.StudentAuth{
-		UUID:       model.UUID(sUUID),
-		StudentID:  model.StudentID(req.StudentID),
-		StudentPW:  model.StudentPW(hashedPW),
-		ParentUUID: model.ParentUUID(parentUUID),
+	var resultAuth *model.StudentAuth
+	_, err = writeWithUniqueID(accountUUIDGenerator("student", ctx.Value("StudentUUID")), accountUUIDKeys, func(sUUID string) (err error) {
+		spanForDB := h.tracer.StartSpan("CreateStudentAuth", opentracing.ChildOf(parentSpan))
+		resultAuth, err = access.CreateStudentAuth(&model.StudentAuth{
+			UUID:       model.UUID(sUUID),
+			StudentID:  model.StudentID(req.StudentID),
+			StudentPW:  model.StudentPW(hashedPW),
+			ParentUUID: model.ParentUUID(parentUUID),
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreateStudentAuth", resultAuth), log.Error(err))
+		spanForDB.Finish()
+		return
 	})
-	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreateStudentAuth", resultAuth), log.Error(err))
-	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -650,16 +682,11 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for student auth model, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.StudentAuthInstance.StudentID.KeyName():
 				resp.Status = http.StatusConflict
@@ -697,7 +724,7 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedInform", resultInform), log.Error(err))
 	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -705,7 +732,7 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for student inform, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
 		resp.Status = http.StatusInternalServerError
 		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected CreateStudentInform error, err: " + assertedError.Error())
@@ -748,7 +775,7 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 	}
 
 	spanForS3 := h.tracer.StartSpan("CopyObject", opentracing.ChildOf(parentSpan))
-	preProfileUri := fmt.Sprintf("profiles/years/2021/grades/%d/groups/%d/numbers/%d", student.Grade, student.Class, student.StudentNumber)
+	preProfileUri := string(student.PreProfileURI) // path is decided with school term when unsigned student was added
 	source := s3Bucket + "/" + preProfileUri
 	_, err = s3.New(h.awsSession).CopyObject(&s3.CopyObjectInput{
 		Bucket:     aws.String(s3Bucket),
@@ -765,6 +792,19 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		return
 	}
 
+	// notice for freshman is rendered before commit, because school term and template are read in tx
+	var noticeTemplate messageTemplate
+	var noticeContent string
+	var noticeErr error
+	if studentInform.Grade == 1 {
+		term, status, _, errMessage := h.currentSchoolTerm(access, parentSpan, reqID)
+		if status != 0 {
+			noticeErr = errors.New(errMessage)
+		} else {
+			noticeTemplate, noticeContent, noticeErr = h.renderMessage(access, freshmanDMSNoticeTemplateName, freshmanDMSNoticeData{SchoolName: term.SchoolName}, parentSpan, reqID)
+		}
+	}
+
 	access.Commit()
 	resp.Status = http.StatusCreated
 	resp.Message = "succeed to create new student with auth code"
@@ -774,27 +814,13 @@ func (h _default) CreateNewStudentWithAuthCode(ctx context.Context, req *proto.C
 		return
 	}
 
-	smsContent := `
-[대덕소프트웨어마이스터고등학교]
-
-신입생 대상 기숙사 지원 시스템(DMS) 안내 문자입니다.
-
-앞서, 저희 학교 지원 시스템(SMS)에 가입해주셔서 감사합니다.
-
-저희는 이 외에도 'DMS'라는 기숙사 지원 시스템을 제공하여 현재 모든 재학생분들이 사용중입니다.
-
-여러분들의 빠른 회원가입을 위하여 SMS에서 입력하신 계정 정보로 DMS 계정을 발급하였습니다.
-
-Play 스토어 또는 App Store에서 'DMS - 기숙사 지원 시스템' 앱을 다운 받아 사용해보세요!
-
-PC 전용 웹 사이트 또한 제공중이니 많이 방문해주세요.
-https://www.dsm-dms.com
-
-* 해당 문자는 전공동아리 DMS에서 발신되었습니다.
-`
+	if noticeErr != nil {
+		resp.Message += fmt.Sprintf("unable to render freshman notice: %v", noticeErr)
+		return
+	}
 
 	spanForMsg := h.tracer.StartSpan("SendToReceivers", opentracing.ChildOf(parentSpan))
-	jsonResp, err := message.SendToReceivers([]string{string(student.PhoneNumber)}, smsContent, "LMS", "DSM 신입생 대상 기숙사 지원 시스템(DMS) 안내 문자")
+	jsonResp, err := h.messageSender.SendToReceivers([]string{string(student.PhoneNumber)}, noticeContent, noticeTemplate.MessageType, noticeTemplate.Title)
 	spanForMsg.SetTag("X-Request-Id", reqID).LogFields(log.Object("JsonResponse", jsonResp), log.Error(err))
 	spanForMsg.Finish()
 	if err != nil {
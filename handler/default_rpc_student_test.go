@@ -16,20 +16,28 @@ import (
 
 func Test_default_LoginStudentAuth(t *testing.T) {
 	hashedByte, _ := bcrypt.GenerateFromPassword([]byte("testPW"), 1)
+	lockedUntil := time.Now().Add(time.Minute)
+	currentPolicyHash, _ := hashPolicy.GenerateFromPassword("testPW")
 
 	tests := []test.LoginStudentAuthCase{
 		{ // success case
 			StudentID: "jinhong07191",
 			StudentPW: "testPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetStudentAuthWithID": {&model.StudentAuth{
 					UUID:       "student-111111111111", // 중복 X !!
 					StudentID:  "jinhong0719",
 					StudentPW:  model.StudentPW(string(hashedByte)),
 					ParentUUID: "parent-111111111111",
 				}, nil},
-				"Commit": {&gorm.DB{}},
+				"ChangeStudentPW":     {nil},
+				"DeleteLoginThrottle": {nil},
+				"CreateSession":       {&model.Session{}, nil},
+				"CreateRefreshToken":  {&model.RefreshToken{}, nil},
+				"Commit":              {&gorm.DB{}},
+				"CreateAuthLog":       {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus:              http.StatusOK,
 			ExpectedLoggedInStudentUUID: "student-111111111111",
@@ -52,35 +60,97 @@ func Test_default_LoginStudentAuth(t *testing.T) {
 		}, { // Student ID no exists
 			StudentID: "jinhong07192",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":              {},
-				"GetStudentAuthWithID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":             {&gorm.DB{}},
+				"BeginTx":                        {},
+				"GetLoginThrottleWithKey":        {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithID":           {&model.StudentAuth{}, gorm.ErrRecordNotFound},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.StudentIDNoExist,
 		}, { // GetStudentAuthWithID unexpected error
 			StudentID: "jinhong07193",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":              {},
-				"GetStudentAuthWithID": {&model.StudentAuth{}, errors.New("unexpected error")},
-				"Rollback":             {&gorm.DB{}},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithID":    {&model.StudentAuth{}, errors.New("unexpected error")},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // incorrect Student PW
 			StudentID: "jinhong07194",
 			StudentPW: "incorrectPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetStudentAuthWithID": {&model.StudentAuth{
 					UUID:       "student-111111111111", // 중복 X !!
 					StudentID:  "jinhong07194",
 					StudentPW:  model.StudentPW(string(hashedByte)),
 					ParentUUID: "parent-111111111111",
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.IncorrectStudentPWForLogin,
+		}, { // account locked by too many login failures
+			StudentID: "jinhong07195",
+			StudentPW: "testPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{FailureCount: 5, LockedUntil: &lockedUntil}, nil},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusLocked,
+			ExpectedCode:   code.AccountLockedForLogin,
+		}, { // incorrect Student PW reaching failure limit -> lock account
+			StudentID: "jinhong07196",
+			StudentPW: "incorrectPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{Model: gorm.Model{UpdatedAt: time.Now()}, FailureCount: 4}, nil},
+				"GetStudentAuthWithID": {&model.StudentAuth{
+					UUID:       "student-111111111111", // 중복 X !!
+					StudentID:  "jinhong07196",
+					StudentPW:  model.StudentPW(string(hashedByte)),
+					ParentUUID: "parent-111111111111",
+				}, nil},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 5}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectStudentPWForLogin,
+		}, { // success case with pw already hashed by current policy -> no rehash
+			StudentID: "jinhong07197",
+			StudentPW: "testPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithID": {&model.StudentAuth{
+					UUID:       "student-111111111111", // 중복 X !!
+					StudentID:  "jinhong07197",
+					StudentPW:  model.StudentPW(currentPolicyHash),
+					ParentUUID: "parent-111111111111",
+				}, nil},
+				"DeleteLoginThrottle": {nil},
+				"CreateSession":       {&model.Session{}, nil},
+				"CreateRefreshToken":  {&model.RefreshToken{}, nil},
+				"Commit":              {&gorm.DB{}},
+				"CreateAuthLog":       {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus:              http.StatusOK,
+			ExpectedLoggedInStudentUUID: "student-111111111111",
 		},
 	}
 
@@ -101,6 +171,7 @@ func Test_default_LoginStudentAuth(t *testing.T) {
 		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, testCase.ExpectedLoggedInStudentUUID, resp.LoggedInStudentUUID, "student uuid assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedStatus == http.StatusOK, resp.AccessToken != "" && resp.RefreshToken != "", "token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
 
 		newMock.AssertExpectations(t)
 	}
@@ -109,6 +180,7 @@ func Test_default_LoginStudentAuth(t *testing.T) {
 func Test_default_ChangeStudentPW(t *testing.T) {
 	hashedTestPW1, _ := bcrypt.GenerateFromPassword([]byte("testPW1"), 1)
 	hashedTestPW2, _ := bcrypt.GenerateFromPassword([]byte("testPW2"), 1)
+	hashedRecentPW, _ := bcrypt.GenerateFromPassword([]byte("RecentPassword"), 1)
 
 	tests := []test.ChangeStudentPWCase{
 		{ // success case
@@ -117,13 +189,19 @@ func Test_default_ChangeStudentPW(t *testing.T) {
 			CurrentPW:   "testPW1",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentAuthWithUUID": {&model.StudentAuth{
 					UUID:      "student-111111111111",
 					StudentPW: model.StudentPW(string(hashedTestPW1)),
 				}, nil},
-				"ChangeStudentPW": {nil},
-				"Commit":          {&gorm.DB{}},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeStudentPW":                         {nil},
+				"CreatePasswordHistory":                   {&model.PasswordHistory{}, nil},
+				"DeletePasswordHistoriesExceptRecent":     {nil},
+				"Commit":                                  {&gorm.DB{}},
+				"CreateAuthLog":                           {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusOK,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
@@ -147,12 +225,28 @@ func Test_default_ChangeStudentPW(t *testing.T) {
 			StudentUUID:    "parent-111111111112",
 			CurrentPW:      "testPW1",
 			RevisionPW:     "NewPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // forbidden (not my auth)
 			UUID:           "student-111111111113",
 			StudentUUID:    "student-111111111114",
 			CurrentPW:      "testPW1",
 			RevisionPW:     "NewPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // not exists student
 			UUID:           "student-111111111115",
@@ -160,9 +254,13 @@ func Test_default_ChangeStudentPW(t *testing.T) {
 			CurrentPW:      "testPW1",
 			RevisionPW:     "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusNotFound,
 		}, { // 현재 Password 불일치
@@ -171,24 +269,74 @@ func Test_default_ChangeStudentPW(t *testing.T) {
 			CurrentPW:   "testPW1",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentAuthWithUUID": {&model.StudentAuth{
 					UUID:      "student-111111111116",
 					StudentPW: model.StudentPW(string(hashedTestPW2)),
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectStudentPWForChange,
+		}, { // 변경할 Password 가 정책에 맞지 않음
+			UUID:        "student-111111111116",
+			StudentUUID: "student-111111111116",
+			CurrentPW:   "testPW1",
+			RevisionPW:  "password",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID": {&model.StudentAuth{
+					UUID:      "student-111111111116",
+					StudentPW: model.StudentPW(string(hashedTestPW1)),
+				}, nil},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.WeakPassword,
+		}, { // 변경할 Password 가 최근에 사용한 Password 와 같음
+			UUID:        "student-111111111116",
+			StudentUUID: "student-111111111116",
+			CurrentPW:   "testPW1",
+			RevisionPW:  "RecentPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID": {&model.StudentAuth{
+					UUID:      "student-111111111116",
+					StudentPW: model.StudentPW(string(hashedTestPW1)),
+				}, nil},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{
+					{HashedPW: model.HashedPW(string(hashedTestPW2))},
+					{HashedPW: model.HashedPW(string(hashedRecentPW))},
+				}, nil},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.ReusedPassword,
 		}, { // GetStudentAuthWithUUID 에러 반환
 			UUID:        "student-111111111117",
 			StudentUUID: "student-111111111117",
 			CurrentPW:   "testPW1",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetStudentAuthWithUUID": {&model.StudentAuth{}, errors.New("DB not connected")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentAuthWithUUID":                {&model.StudentAuth{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // ChangeStudentPW 에러 반환
@@ -197,13 +345,18 @@ func Test_default_ChangeStudentPW(t *testing.T) {
 			CurrentPW:   "testPW1",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentAuthWithUUID": {&model.StudentAuth{
 					UUID:      "student-111111111118",
 					StudentPW: model.StudentPW(string(hashedTestPW1)),
 				}, nil},
-				"ChangeStudentPW": {errors.New("DB not connected")},
-				"Rollback":        {&gorm.DB{}},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeStudentPW":                         {errors.New("DB not connected")},
+				"Rollback":                                {&gorm.DB{}},
+				"CreateAuthLog":                           {&model.AuthLog{}, nil},
+				"Commit":                                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // GetStudentAuthWithUUID Short Hashed PW 반환
@@ -212,12 +365,16 @@ func Test_default_ChangeStudentPW(t *testing.T) {
 			CurrentPW:   "testPW1",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentAuthWithUUID": {&model.StudentAuth{
 					UUID:      "student-111111111119",
 					StudentPW: "TooShortHashedPasword",
 				}, nil},
-				"Rollback":        {&gorm.DB{}},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -252,7 +409,9 @@ func Test_default_GetStudentInformWithUUID(t *testing.T) {
 			UUID: "student-111111111111",
 			StudentUUID: "student-111111111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentInformWithUUID": {&model.StudentInform{
 					Model:         gorm.Model{CreatedAt: now, UpdatedAt: now},
 					StudentUUID:   "student-111111111111",
@@ -297,20 +456,34 @@ func Test_default_GetStudentInformWithUUID(t *testing.T) {
 		}, { // forbidden (not student)
 			UUID:           "parent-111111111112",
 			StudentUUID:    "student-111111111112",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 			ExpectedInform: &model.StudentInform{},
 		}, { // forbidden (not my auth)
 			UUID:           "student-111111111113",
 			StudentUUID:    "student-111111111114",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 			ExpectedInform: &model.StudentInform{},
 		}, { // no exist student uuid
 			UUID:        "admin-111111111111",
 			StudentUUID: "student-111111111115",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetStudentInformWithUUID": {&model.StudentInform{}, gorm.ErrRecordNotFound},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentInformWithUUID":              {&model.StudentInform{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusNotFound,
 			ExpectedInform: &model.StudentInform{},
@@ -318,9 +491,11 @@ func Test_default_GetStudentInformWithUUID(t *testing.T) {
 			UUID:        "admin-111111111112",
 			StudentUUID: "student-111111111116",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetStudentInformWithUUID": {&model.StudentInform{}, errors.New("DB not connected")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentInformWithUUID":              {&model.StudentInform{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 			ExpectedInform: &model.StudentInform{},
@@ -364,9 +539,11 @@ func Test_default_GetStudentUUIDsWithInform(t *testing.T) {
 			UUID: "admin-111111111111",
 			Name: "이성진",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetStudentUUIDsWithInform": {[]string{"student-123412341234", "student-123412341234"}, nil},
-				"Commit":                    {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentUUIDsWithInform":             {[]string{"student-123412341234", "student-123412341234"}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:       http.StatusOK,
 			ExpectedStudentUUIDs: []string{"student-123412341234", "student-123412341234"},
@@ -376,9 +553,11 @@ func Test_default_GetStudentUUIDsWithInform(t *testing.T) {
 			Grade:         2,
 			StudentNumber: 7,
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetStudentUUIDsWithInform": {[]string{"student-111111111111"}, nil},
-				"Commit":                    {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentUUIDsWithInform":             {[]string{"student-111111111111"}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:       http.StatusOK,
 			ExpectedStudentUUIDs: []string{"student-111111111111"},
@@ -404,6 +583,12 @@ func Test_default_GetStudentUUIDsWithInform(t *testing.T) {
 			ExpectedStatus:    http.StatusProxyAuthRequired,
 		}, { // forbidden (not student)
 			UUID:           "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // no exist student uuid with that inform
 			UUID:          "student-111111111111",
@@ -411,18 +596,22 @@ func Test_default_GetStudentUUIDsWithInform(t *testing.T) {
 			Grade:         2,
 			StudentNumber: 21,
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetStudentUUIDsWithInform": {[]string{}, gorm.ErrRecordNotFound},
-				"Rollback":                  {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentUUIDsWithInform":             {[]string{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.StudentWithThatInformNoExist,
 		}, { // GetStudentInformWithUUID error return
 			UUID:          "student-111111111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetStudentUUIDsWithInform": {[]string{}, errors.New("I don't know about that error")},
-				"Rollback":                  {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentUUIDsWithInform":             {[]string{}, errors.New("I don't know about that error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -458,7 +647,9 @@ func Test_default_GetStudentInformsWithUUIDs(t *testing.T) {
 			UUID:         "student-111111111111",
 			StudentUUIDs: []string{"student-111111111111", "student-222222222222"},
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentInformsWithUUIDs": {[]*model.StudentInform{
 					{
 						Model:         gorm.Model{CreatedAt: now, UpdatedAt: now},
@@ -521,12 +712,20 @@ func Test_default_GetStudentInformsWithUUIDs(t *testing.T) {
 		}, { // forbidden (not student)
 			UUID:           "parent-111111111112",
 			StudentUUIDs:   []string{"student-111111111112"},
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // no exist student uuid
 			UUID:         "admin-111111111111",
 			StudentUUIDs: []string{"student-111111111115", "student-111111111111"},
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetStudentInformsWithUUIDs": {[]*model.StudentInform{{}, {
 					Model:         gorm.Model{CreatedAt: now, UpdatedAt: now},
 					StudentUUID:   "student-111111111111",
@@ -545,9 +744,11 @@ func Test_default_GetStudentInformsWithUUIDs(t *testing.T) {
 			UUID:         "admin-111111111112",
 			StudentUUIDs: []string{},
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                    {},
-				"GetStudentInformsWithUUIDs": {[]*model.StudentInform{}, errors.New("DB not connected")},
-				"Rollback":                   {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetStudentInformsWithUUIDs":            {[]*model.StudentInform{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -3,21 +3,18 @@ package handler
 import (
 	"auth/model"
 	proto "auth/proto/golang/auth"
-	"auth/tool/mysqlerr"
-	"auth/tool/random"
+	"auth/tool/dberr"
+	"auth/tool/hash"
 	code "auth/utils/code/golang"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	mysqlcode "github.com/VividCortex/mysqlerr"
 	"github.com/go-playground/validator/v10"
-	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/uber/jaeger-client-go"
-	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"reflect"
 )
@@ -40,31 +37,20 @@ func (h _default) CreateNewTeacher(ctx context.Context, req *proto.CreateNewTeac
 		return
 	}
 
-	tUUID, ok := ctx.Value("TeacherUUID").(string)
-	if !ok || tUUID == "" {
-		tUUID = fmt.Sprintf("teacher-%s", random.StringConsistOfIntWithLength(12))
+	if authorized, status, _code, message := h.authorize(ctx, access, "CreateNewTeacher", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
 	}
 
-	for {
-		spanForDB := h.tracer.StartSpan("GetTeacherAuthWithUUID", opentracing.ChildOf(parentSpan))
-		selectedAuth, err := access.GetTeacherAuthWithUUID(tUUID)
-		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("selectedAuth", selectedAuth), log.Error(err))
-		spanForDB.Finish()
-		if err == gorm.ErrRecordNotFound {
-			break
-		}
-		if err != nil {
-			access.Rollback()
-			resp.Status = http.StatusInternalServerError
-			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
-			return
-		}
-		tUUID = fmt.Sprintf("teacher-%s", random.StringConsistOfIntWithLength(12))
-		continue
+	if strong, status, _code, message := checkPasswordStrength(req.TeacherPW, req.TeacherID); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
 	}
 
 	spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.TeacherPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.TeacherPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -75,17 +61,21 @@ func (h _default) CreateNewTeacher(ctx context.Context, req *proto.CreateNewTeac
 		return
 	}
 
-	spanForDB := h.tracer.StartSpan("CreateTeacherAuth", opentracing.ChildOf(parentSpan))
-	resultAuth, err := access.CreateTeacherAuth(&model.TeacherAuth{
-		UUID:      model.UUID(tUUID),
-		TeacherID: model.TeacherID(req.TeacherID),
-		TeacherPW: model.TeacherPW(string(hashedBytes)),
-		Certified: false,
+	var resultAuth *model.TeacherAuth
+	tUUID, err := writeWithUniqueID(accountUUIDGenerator("teacher", ctx.Value("TeacherUUID")), accountUUIDKeys, func(tUUID string) (err error) {
+		spanForDB := h.tracer.StartSpan("CreateTeacherAuth", opentracing.ChildOf(parentSpan))
+		resultAuth, err = access.CreateTeacherAuth(&model.TeacherAuth{
+			UUID:      model.UUID(tUUID),
+			TeacherID: model.TeacherID(req.TeacherID),
+			TeacherPW: model.TeacherPW(hashedPW),
+			Certified: false,
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", resultAuth), log.Error(err))
+		spanForDB.Finish()
+		return
 	})
-	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", resultAuth), log.Error(err))
-	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -93,16 +83,11 @@ func (h _default) CreateNewTeacher(ctx context.Context, req *proto.CreateNewTeac
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for teacher auth model, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.TeacherAuthInstance.TeacherID.KeyName():
 				resp.Status = http.StatusConflict
@@ -125,7 +110,7 @@ func (h _default) CreateNewTeacher(ctx context.Context, req *proto.CreateNewTeac
 		return
 	}
 
-	spanForDB = h.tracer.StartSpan("CreateTeacherInform", opentracing.ChildOf(parentSpan))
+	spanForDB := h.tracer.StartSpan("CreateTeacherInform", opentracing.ChildOf(parentSpan))
 	resultInform, err := access.CreateTeacherInform(&model.TeacherInform{
 		TeacherUUID:   model.TeacherUUID(string(resultAuth.UUID)),
 		Grade:         model.Grade(int64(req.Grade)),
@@ -136,7 +121,7 @@ func (h _default) CreateNewTeacher(ctx context.Context, req *proto.CreateNewTeac
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedInform", resultInform), log.Error(err))
 	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -144,16 +129,11 @@ func (h _default) CreateNewTeacher(ctx context.Context, req *proto.CreateNewTeac
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for teacher inform, err: " + err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
-		switch assertedError.Number {
-		case mysqlcode.ER_DUP_ENTRY:
-			key, entry, err := mysqlerr.ParseDuplicateEntryErrorFrom(assertedError)
-			if err != nil {
-				resp.Status = http.StatusInternalServerError
-				resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to parse duplicate error, err: " + err.Error())
-				return
-			}
+		switch assertedError.Category {
+		case dberr.Duplicate:
+			key, entry := assertedError.Key, assertedError.Entry
 			switch key {
 			case model.TeacherInformInstance.PhoneNumber.KeyName():
 				resp.Status = http.StatusConflict
@@ -199,6 +179,11 @@ func (h _default) LoginTeacherAuth(ctx context.Context, req *proto.LoginTeacherA
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "LoginTeacherAuth", accountType: teacherRole, accountID: req.TeacherID, ownerUUID: resp.LoggedInTeacherUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -206,19 +191,27 @@ func (h _default) LoginTeacherAuth(ctx context.Context, req *proto.LoginTeacherA
 		return
 	}
 
+	accountKey := accountThrottleKey(string(teacherRole), req.TeacherID)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, accountKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetTeacherAuthWithID", opentracing.ChildOf(parentSpan))
 	resultAuth, err := access.GetTeacherAuthWithID(req.TeacherID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", resultAuth), log.Error(err))
 	spanForDB.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
 		case gorm.ErrRecordNotFound:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.TeacherIDNoExist
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "teacher id not exists")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " +err.Error())
 		}
@@ -234,28 +227,78 @@ func (h _default) LoginTeacherAuth(ctx context.Context, req *proto.LoginTeacherA
 	}
 
 	spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
-	err = bcrypt.CompareHashAndPassword([]byte(resultAuth.TeacherPW), []byte(req.TeacherPW))
+	err = hash.CompareHashAndPassword(string(resultAuth.TeacherPW), req.TeacherPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
 	if err != nil {
-		access.Rollback()
 		switch err {
-		case bcrypt.ErrMismatchedHashAndPassword:
+		case hash.ErrMismatchedHashAndPassword:
+			h.commitLoginFailure(ctx, access, accountKey)
 			resp.Status = http.StatusConflict
 			resp.Code = code.IncorrectTeacherPWForLogin
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
 		default:
+			access.Rollback()
 			resp.Status = http.StatusInternalServerError
 			resp.Message = fmt.Sprintf(internalServerErrorFormat, "hash compare error, err: " + err.Error())
 		}
 		return
 	}
 
+	err = h.rehashPWIfNeeded(access.ChangeTeacherPW, string(resultAuth.UUID), string(resultAuth.TeacherPW), req.TeacherPW, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	if err = h.resetLoginFailure(ctx, access, accountKey); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	// login of account with enabled TOTP is finished in LoginWithTOTP with token issued here (add in v.1.2.0)
+	totpEnabled, err := h.totpEnabled(access, string(resultAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+	if totpEnabled {
+		totpToken, err := issueTOTPToken(string(resultAuth.UUID))
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+		access.Commit()
+		resp.Status = http.StatusAccepted
+		resp.Code = code.TOTPRequired
+		resp.Message = "password is correct, please finish login with totp code"
+		resp.TOTPToken = totpToken
+		return
+	}
+
+	accessToken, refreshToken, err := h.startSession(ctx, access, string(resultAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "succeed to login teacher auth"
 	resp.LoggedInTeacherUUID = string(resultAuth.UUID)
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
 
 	return
 }
@@ -271,6 +314,11 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "LoginTeacherAuthWithPICK", accountType: teacherRole, accountID: req.TeacherID, ownerUUID: resp.LoggedInTeacherUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -278,6 +326,13 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 		return
 	}
 
+	accountKey := accountThrottleKey(string(teacherRole), req.TeacherID)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, accountKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetTeacherAuthWithID", opentracing.ChildOf(parentSpan))
 	resultAuth, err := access.GetTeacherAuthWithID(req.TeacherID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", resultAuth), log.Error(err))
@@ -285,18 +340,19 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 
 	if err == nil {
 		spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
-		err = bcrypt.CompareHashAndPassword([]byte(resultAuth.TeacherPW), []byte(req.TeacherPW))
+		err = hash.CompareHashAndPassword(string(resultAuth.TeacherPW), req.TeacherPW)
 		spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 		spanForHash.Finish()
 
 		if err != nil {
-			access.Rollback()
 			switch err {
-			case bcrypt.ErrMismatchedHashAndPassword:
+			case hash.ErrMismatchedHashAndPassword:
+				h.commitLoginFailure(ctx, access, accountKey)
 				resp.Status = http.StatusConflict
 				resp.Code = code.IncorrectTeacherPWForLogin
 				resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
 			default:
+				access.Rollback()
 				resp.Status = http.StatusInternalServerError
 				resp.Message = fmt.Sprintf(internalServerErrorFormat, "hash compare error, err: "+err.Error())
 			}
@@ -308,12 +364,62 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 			resp.Status = http.StatusConflict
 			resp.Code = code.NotCertifiedTeacherAccount
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "not certified annount")
-		} else {
+			return
+		}
+
+		err = h.rehashPWIfNeeded(access.ChangeTeacherPW, string(resultAuth.UUID), string(resultAuth.TeacherPW), req.TeacherPW, parentSpan, reqID)
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+
+		if err = h.resetLoginFailure(ctx, access, accountKey); err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+
+		// login of account with enabled TOTP is finished in LoginWithTOTP with token issued here (add in v.1.2.0)
+		totpEnabled, err := h.totpEnabled(access, string(resultAuth.UUID), parentSpan, reqID)
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+		if totpEnabled {
+			totpToken, err := issueTOTPToken(string(resultAuth.UUID))
+			if err != nil {
+				access.Rollback()
+				resp.Status = http.StatusInternalServerError
+				resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+				return
+			}
 			access.Commit()
-			resp.Status = http.StatusOK
-			resp.Message = "succeed to login teacher auth"
-			resp.LoggedInTeacherUUID = string(resultAuth.UUID)
+			resp.Status = http.StatusAccepted
+			resp.Code = code.TOTPRequired
+			resp.Message = "password is correct, please finish login with totp code"
+			resp.TOTPToken = totpToken
+			return
+		}
+
+		accessToken, refreshToken, err := h.startSession(ctx, access, string(resultAuth.UUID), parentSpan, reqID)
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
 		}
+
+		access.Commit()
+		resp.Status = http.StatusOK
+		resp.Message = "succeed to login teacher auth"
+		resp.LoggedInTeacherUUID = string(resultAuth.UUID)
+		resp.AccessToken = accessToken
+		resp.RefreshToken = refreshToken
 		return
 	} else if err == gorm.ErrRecordNotFound {
 		// continue
@@ -344,7 +450,7 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 	case http.StatusOK:
 		// continue
 	case http.StatusBadRequest:
-		access.Rollback()
+		h.commitLoginFailure(ctx, access, accountKey)
 		resp.Status = http.StatusConflict
 		resp.Code = code.TeacherAccountMismatch
 		resp.Message = fmt.Sprintf(conflictErrorFormat, "teacher account mismatch")
@@ -360,27 +466,8 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 	j := &pickRespJSON{}
 	_ = json.NewDecoder(pickResp.Body).Decode(j)
 
-	var tUUID string
-	for {
-		tUUID = fmt.Sprintf("teacher-%s", random.StringConsistOfIntWithLength(12))
-		spanForDB := h.tracer.StartSpan("GetTeacherAuthWithUUID", opentracing.ChildOf(parentSpan))
-		selectedAuth, err := access.GetTeacherAuthWithUUID(tUUID)
-		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("selectedAuth", selectedAuth), log.Error(err))
-		spanForDB.Finish()
-		if err == gorm.ErrRecordNotFound {
-			break
-		}
-		if err != nil {
-			access.Rollback()
-			resp.Status = http.StatusInternalServerError
-			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: "+err.Error())
-			return
-		}
-		continue
-	}
-
 	spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.TeacherPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.TeacherPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -391,17 +478,21 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 		return
 	}
 
-	spanForDB = h.tracer.StartSpan("CreateTeacherAuth", opentracing.ChildOf(parentSpan))
-	createdAuth, err := access.CreateTeacherAuth(&model.TeacherAuth{
-		UUID:      model.UUID(tUUID),
-		TeacherID: model.TeacherID(req.TeacherID),
-		TeacherPW: model.TeacherPW(string(hashedBytes)),
-		Certified: true,
+	var createdAuth *model.TeacherAuth
+	_, err = writeWithUniqueID(accountUUIDGenerator("teacher", ctx.Value("TeacherUUID")), accountUUIDKeys, func(tUUID string) (err error) {
+		spanForDB := h.tracer.StartSpan("CreateTeacherAuth", opentracing.ChildOf(parentSpan))
+		createdAuth, err = access.CreateTeacherAuth(&model.TeacherAuth{
+			UUID:      model.UUID(tUUID),
+			TeacherID: model.TeacherID(req.TeacherID),
+			TeacherPW: model.TeacherPW(hashedPW),
+			Certified: true,
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", createdAuth), log.Error(err))
+		spanForDB.Finish()
+		return
 	})
-	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", createdAuth), log.Error(err))
-	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -409,7 +500,7 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for teacher auth, err: "+err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
 		resp.Status = http.StatusInternalServerError
 		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected CreateTeacherAuth error, err: "+assertedError.Error())
@@ -429,7 +520,7 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedInform", createdInform), log.Error(err))
 	spanForDB.Finish()
 
-	switch assertedError := err.(type) {
+	switch assertedError := dberr.From(err).(type) {
 	case nil:
 		break
 	case validator.ValidationErrors:
@@ -437,7 +528,7 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 		resp.Status = http.StatusProxyAuthRequired
 		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for teacher inform, err: "+err.Error())
 		return
-	case *mysql.MySQLError:
+	case *dberr.Error:
 		access.Rollback()
 		resp.Status = http.StatusInternalServerError
 		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unexpected CreateTeacherInform error, err: "+assertedError.Error())
@@ -449,10 +540,20 @@ func (h _default) LoginTeacherAuthWithPICK(ctx context.Context, req *proto.Login
 		return
 	}
 
+	accessToken, refreshToken, err := h.startSession(ctx, access, string(createdAuth.UUID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "succeed to login teacher auth with PICK API"
 	resp.LoggedInTeacherUUID = string(createdAuth.UUID)
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
 	return
 }
 
@@ -464,20 +565,14 @@ func (h _default) ChangeTeacherPW(ctx context.Context, req *proto.ChangeTeacherP
 		return
 	}
 
-	switch true {
-	case teacherUUIDRegex.MatchString(req.TeacherUUID) && req.UUID == req.TeacherUUID:
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not teacher or admin uuid OR not your student uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
+	// result of this RPC is recorded in auth log, even if it fails (add in v.1.2.0)
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "ChangeTeacherPW", accountType: teacherRole, ownerUUID: req.TeacherUUID}, resp.Status, resp.Code)
+	}()
+
 	access, err := h.accessManage.BeginTx()
 	if err != nil {
 		resp.Status = http.StatusInternalServerError
@@ -485,6 +580,12 @@ func (h _default) ChangeTeacherPW(ctx context.Context, req *proto.ChangeTeacherP
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeTeacherPW", req.TeacherUUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetTeacherAuthWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetTeacherAuthWithUUID(req.TeacherUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -504,14 +605,14 @@ func (h _default) ChangeTeacherPW(ctx context.Context, req *proto.ChangeTeacherP
 	}
 
 	spanForHash := h.tracer.StartSpan("CompareHashAndPassword", opentracing.ChildOf(parentSpan))
-	err = bcrypt.CompareHashAndPassword([]byte(selectedAuth.TeacherPW), []byte(req.CurrentPW))
+	err = hash.CompareHashAndPassword(string(selectedAuth.TeacherPW), req.CurrentPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
 	if err != nil {
 		access.Rollback()
 		switch err {
-		case bcrypt.ErrMismatchedHashAndPassword:
+		case hash.ErrMismatchedHashAndPassword:
 			resp.Status = http.StatusConflict
 			resp.Code = code.IncorrectTeacherPWForChange
 			resp.Message = fmt.Sprintf(conflictErrorFormat, "mismatched hash and password")
@@ -522,8 +623,20 @@ func (h _default) ChangeTeacherPW(ctx context.Context, req *proto.ChangeTeacherP
 		return
 	}
 
+	if strong, status, _code, message := checkPasswordStrength(req.RevisionPW, string(selectedAuth.TeacherID)); !strong {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	if allowed, status, _code, message := h.checkPasswordReuse(ctx, access, string(selectedAuth.UUID), string(selectedAuth.TeacherPW), req.RevisionPW); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForHash = h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(req.RevisionPW), bcrypt.MinCost)
+	hashedPW, err := hashPolicy.GenerateFromPassword(req.RevisionPW)
 	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForHash.Finish()
 
@@ -535,7 +648,7 @@ func (h _default) ChangeTeacherPW(ctx context.Context, req *proto.ChangeTeacherP
 	}
 
 	spanForDB = h.tracer.StartSpan("ChangeTeacherPW", opentracing.ChildOf(parentSpan))
-	err = access.ChangeTeacherPW(string(selectedAuth.UUID), string(hashedBytes))
+	err = access.ChangeTeacherPW(string(selectedAuth.UUID), hashedPW)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
 	spanForDB.Finish()
 
@@ -546,6 +659,13 @@ func (h _default) ChangeTeacherPW(ctx context.Context, req *proto.ChangeTeacherP
 		return
 	}
 
+	if err = h.recordPasswordHistory(ctx, access, string(selectedAuth.UUID), string(selectedAuth.TeacherPW)); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
 	access.Commit()
 	resp.Status = http.StatusOK
 	resp.Message = "teacher pw change success"
@@ -560,21 +680,6 @@ func (h _default) GetTeacherInformWithUUID(ctx context.Context, req *proto.GetTe
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -585,6 +690,12 @@ func (h _default) GetTeacherInformWithUUID(ctx context.Context, req *proto.GetTe
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetTeacherInformWithUUID", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetTeacherInformWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetTeacherInformWithUUID(req.TeacherUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -622,20 +733,6 @@ func (h _default) GetTeacherUUIDsWithInform(ctx context.Context, req *proto.GetT
 		return
 	}
 
-	switch true {
-	case studentUUIDRegex.MatchString(req.UUID):
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	case teacherUUIDRegex.MatchString(req.UUID):
-		break
-	case parentUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not student or admin or teacher or parent uuid")
-		return
-	}
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -646,6 +743,12 @@ func (h _default) GetTeacherUUIDsWithInform(ctx context.Context, req *proto.GetT
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "GetTeacherUUIDsWithInform", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	informToSelect := &model.TeacherInform{
 		Grade:         model.Grade(int64(req.Grade)),
 		Class:         model.Class(int64(req.Group)),
@@ -692,17 +795,6 @@ func (h _default) ChangeTeacherInform(ctx context.Context, req *proto.ChangeTeac
 		return
 	}
 
-	switch true {
-	case teacherUUIDRegex.MatchString(req.TeacherUUID) && req.UUID == req.TeacherUUID:
-		break
-	case adminUUIDRegex.MatchString(req.UUID):
-		break
-	default:
-		resp.Status = http.StatusForbidden
-		resp.Message = fmt.Sprintf(forbiddenMessageFormat, "not teacher or admin uuid OR not your student uuid")
-		return
-	}
-
 	reqID := ctx.Value("X-Request-Id").(string)
 	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
 
@@ -713,6 +805,12 @@ func (h _default) ChangeTeacherInform(ctx context.Context, req *proto.ChangeTeac
 		return
 	}
 
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeTeacherInform", req.TeacherUUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
 	spanForDB := h.tracer.StartSpan("GetTeacherAuthWithUUID", opentracing.ChildOf(parentSpan))
 	selectedAuth, err := access.GetTeacherAuthWithUUID(req.TeacherUUID)
 	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedAuth", selectedAuth), log.Error(err))
@@ -740,6 +838,13 @@ func (h _default) ChangeTeacherInform(ctx context.Context, req *proto.ChangeTeac
 
 	if err != nil {
 		access.Rollback()
+		// phone number is unique key of teacher inform (add in v.1.2.0)
+		if dbErr, ok := dberr.From(err).(*dberr.Error); ok && dbErr.Category == dberr.Duplicate && dbErr.Key == model.TeacherInformInstance.PhoneNumber.KeyName() {
+			resp.Status = http.StatusConflict
+			resp.Code = code.TeacherPhoneNumberDuplicate
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "phone number duplicate, entry: " + dbErr.Entry)
+			return
+		}
 		resp.Status = http.StatusInternalServerError
 		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to update DB, err: " + err.Error())
 		return
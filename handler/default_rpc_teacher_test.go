@@ -16,19 +16,28 @@ import (
 
 func Test_default_LoginTeacherAuth(t *testing.T) {
 	hashedByte, _ := bcrypt.GenerateFromPassword([]byte("testPW"), 1)
+	lockedUntil := time.Now().Add(time.Minute)
 
 	tests := []test.LoginTeacherAuthCase{
 		{ // success case
 			TeacherID: "jinhong07191",
 			TeacherPW: "testPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetTeacherAuthWithID": {&model.TeacherAuth{
 					UUID:      "teacher-111111111111",
 					TeacherID: "jinhong07191",
 					TeacherPW: model.TeacherPW(string(hashedByte)),
+					Certified: true,
 				}, nil},
-				"Commit": {&gorm.DB{}},
+				"ChangeTeacherPW":               {nil},
+				"DeleteLoginThrottle":           {nil},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{}, gorm.ErrRecordNotFound},
+				"CreateSession":                 {&model.Session{}, nil},
+				"CreateRefreshToken":            {&model.RefreshToken{}, nil},
+				"Commit":                        {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus:              http.StatusOK,
 			ExpectedLoggedInTeacherUUID: "teacher-111111111111",
@@ -51,34 +60,58 @@ func Test_default_LoginTeacherAuth(t *testing.T) {
 		}, { // Student ID no exists
 			TeacherID: "jinhong07192",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":              {},
-				"GetTeacherAuthWithID": {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":             {&gorm.DB{}},
+				"BeginTx":                        {},
+				"GetLoginThrottleWithKey":        {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTeacherAuthWithID":           {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.TeacherIDNoExist,
 		}, { // GetStudentAuthWithID unexpected error
 			TeacherID: "jinhong07193",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":              {},
-				"GetTeacherAuthWithID": {&model.TeacherAuth{}, errors.New("unexpected error")},
-				"Rollback":             {&gorm.DB{}},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTeacherAuthWithID":    {&model.TeacherAuth{}, errors.New("unexpected error")},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // incorrect Student PW
 			TeacherID: "jinhong07194",
 			TeacherPW: "incorrectPW",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
 				"GetTeacherAuthWithID": {&model.TeacherAuth{
 					UUID:      "teacher-111111111111", // 중복 X !!
 					TeacherID: "jinhong07194",
 					TeacherPW: model.TeacherPW(string(hashedByte)),
+					Certified: true,
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectTeacherPWForLogin,
+		}, { // account locked by too many login failures
+			TeacherID: "jinhong07195",
+			TeacherPW: "testPW",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetLoginThrottleWithKey": {&model.LoginThrottle{FailureCount: 5, LockedUntil: &lockedUntil}, nil},
+				"Rollback":                {&gorm.DB{}},
+				"CreateAuthLog":           {&model.AuthLog{}, nil},
+				"Commit":                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusLocked,
+			ExpectedCode:   code.AccountLockedForLogin,
 		},
 	}
 
@@ -99,6 +132,7 @@ func Test_default_LoginTeacherAuth(t *testing.T) {
 		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
 		assert.Equalf(t, testCase.ExpectedLoggedInTeacherUUID, resp.LoggedInTeacherUUID, "logged in uuid assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedStatus == http.StatusOK, resp.AccessToken != "" && resp.RefreshToken != "", "token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
 
 		newMock.AssertExpectations(t)
 	}
@@ -114,13 +148,19 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			CurrentPW:   "testPW",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetTeacherAuthWithUUID": {&model.TeacherAuth{
 					UUID:     "teacher-111111111111",
 					TeacherPW: model.TeacherPW(string(hashedTestPW)),
 				}, nil},
-				"ChangeTeacherPW": {nil},
-				"Commit":         {&gorm.DB{}},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeTeacherPW":                         {nil},
+				"CreatePasswordHistory":                   {&model.PasswordHistory{}, nil},
+				"DeletePasswordHistoriesExceptRecent":     {nil},
+				"Commit":                                  {&gorm.DB{}},
+				"CreateAuthLog":                           {&model.AuthLog{}, nil},
 			},
 			ExpectedStatus: http.StatusOK,
 		}, { // no exist X-Request-ID -> Proxy Authorization Required
@@ -144,12 +184,28 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			TeacherUUID:    "student-111111111112",
 			CurrentPW:      "testPW",
 			RevisionPW:     "NewPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // forbidden (not my auth)
 			UUID:           "teacher-111111111113",
 			TeacherUUID:    "teacher-111111111114",
 			CurrentPW:      "testPW",
 			RevisionPW:     "NewPassword",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // not exists student
 			UUID:           "teacher-111111111115",
@@ -157,9 +213,13 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			CurrentPW:      "testPW",
 			RevisionPW:     "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetTeacherAuthWithUUID": {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherAuthWithUUID":                {&model.TeacherAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusNotFound,
 		}, { // 현재 Password 불일치
@@ -168,12 +228,16 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			CurrentPW:   "IncorrectPassword",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetTeacherAuthWithUUID": {&model.TeacherAuth{
 					UUID:      "teacher-111111111116",
 					TeacherPW: model.TeacherPW(string(hashedTestPW)),
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.IncorrectTeacherPWForChange,
@@ -183,9 +247,13 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			CurrentPW:   "testPW",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                {},
-				"GetTeacherAuthWithUUID": {&model.TeacherAuth{}, errors.New("DB not connected")},
-				"Rollback":               {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherAuthWithUUID":                {&model.TeacherAuth{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
+				"CreateAuthLog":                         {&model.AuthLog{}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // ChangeStudentPW 에러 반환
@@ -194,13 +262,18 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			CurrentPW:   "testPW",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetTeacherAuthWithUUID": {&model.TeacherAuth{
 					UUID:      "teacher-111111111118",
 					TeacherPW: model.TeacherPW(string(hashedTestPW)),
 				}, nil},
-				"ChangeTeacherPW": {errors.New("DB not connected")},
-				"Rollback":        {&gorm.DB{}},
+				"GetRecentPasswordHistoriesWithOwnerUUID": {[]*model.PasswordHistory{}, nil},
+				"ChangeTeacherPW":                         {errors.New("DB not connected")},
+				"Rollback":                                {&gorm.DB{}},
+				"CreateAuthLog":                           {&model.AuthLog{}, nil},
+				"Commit":                                  {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		}, { // GetStudentAuthWithUUID Short Hashed PW 반환
@@ -209,12 +282,16 @@ func Test_default_ChangeTeacherPW(t *testing.T) {
 			CurrentPW:   "testPW",
 			RevisionPW:  "NewPassword",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetTeacherAuthWithUUID": {&model.TeacherAuth{
 					UUID:      "teacher-111111111119",
 					TeacherPW: "TooShortHashedPasword",
 				}, nil},
-				"Rollback": {&gorm.DB{}},
+				"Rollback":      {&gorm.DB{}},
+				"CreateAuthLog": {&model.AuthLog{}, nil},
+				"Commit":        {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
@@ -249,7 +326,9 @@ func Test_default_GetTeacherInformWithUUID(t *testing.T) {
 			UUID: "teacher-111111111111",
 			TeacherUUID: "teacher-111111111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx": {},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
 				"GetTeacherInformWithUUID": {&model.TeacherInform{
 					Model:         gorm.Model{CreatedAt: now, UpdatedAt: now},
 					TeacherUUID:   "teacher-111111111111",
@@ -290,20 +369,34 @@ func Test_default_GetTeacherInformWithUUID(t *testing.T) {
 		}, { // forbidden (not student)
 			UUID:           "parent-111111111112",
 			TeacherUUID:    "parent-111111111112",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 			ExpectedInform: &model.TeacherInform{},
 		}, { // forbidden (not my auth)
 			UUID:           "teacher-111111111113",
 			TeacherUUID:    "teacher-111111111114",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 			ExpectedInform: &model.TeacherInform{},
 		}, { // no exist student uuid
 			UUID:        "admin-111111111111",
 			TeacherUUID: "teacher-111111111115",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherInformWithUUID": {&model.TeacherInform{}, gorm.ErrRecordNotFound},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherInformWithUUID":              {&model.TeacherInform{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusNotFound,
 			ExpectedInform: &model.TeacherInform{},
@@ -311,9 +404,11 @@ func Test_default_GetTeacherInformWithUUID(t *testing.T) {
 			UUID:        "admin-111111111112",
 			TeacherUUID: "teacher-111111111116",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                  {},
-				"GetTeacherInformWithUUID": {&model.TeacherInform{}, errors.New("DB not connected")},
-				"Rollback":                 {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherInformWithUUID":              {&model.TeacherInform{}, errors.New("DB not connected")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 			ExpectedInform: &model.TeacherInform{},
@@ -355,9 +450,11 @@ func Test_default_GetTeacherUUIDsWithInform(t *testing.T) {
 			UUID: "admin-111111111111",
 			Name: "이성진",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetTeacherUUIDsWithInform": {[]string{"teacher-123412341234", "teacher-123412341234"}, nil},
-				"Commit":                    {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherUUIDsWithInform":             {[]string{"teacher-123412341234", "teacher-123412341234"}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:       http.StatusOK,
 			ExpectedTeacherUUIDs: []string{"teacher-123412341234", "teacher-123412341234"},
@@ -366,9 +463,11 @@ func Test_default_GetTeacherUUIDsWithInform(t *testing.T) {
 			Class: 2,
 			Grade: 2,
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetTeacherUUIDsWithInform": {[]string{"teacher-111111111111"}, nil},
-				"Commit":                    {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherUUIDsWithInform":             {[]string{"teacher-111111111111"}, nil},
+				"Commit":                                {&gorm.DB{}},
 			},
 			ExpectedStatus:       http.StatusOK,
 			ExpectedTeacherUUIDs: []string{"teacher-111111111111"},
@@ -394,24 +493,34 @@ func Test_default_GetTeacherUUIDsWithInform(t *testing.T) {
 			ExpectedStatus:    http.StatusProxyAuthRequired,
 		}, { // forbidden (not teacher)
 			UUID:           "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
 			ExpectedStatus: http.StatusForbidden,
 		}, { // no exist parent uuid with that inform
 			UUID:  "admin-111111111111",
 			Class: 2,
 			Grade: 12,
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetTeacherUUIDsWithInform": {[]string{}, gorm.ErrRecordNotFound},
-				"Rollback":                  {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherUUIDsWithInform":             {[]string{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   code.TeacherWithThatInformNoExist,
 		}, { // GetTeacherUUIDsWithInform error return
 			UUID: "admin-111111111111",
 			ExpectedMethods: map[test.Method]test.Returns{
-				"BeginTx":                   {},
-				"GetTeacherUUIDsWithInform": {[]string{}, errors.New("I don't know about that error")},
-				"Rollback":                  {&gorm.DB{}},
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetTeacherUUIDsWithInform":             {[]string{}, errors.New("I don't know about that error")},
+				"Rollback":                              {&gorm.DB{}},
 			},
 			ExpectedStatus: http.StatusInternalServerError,
 		},
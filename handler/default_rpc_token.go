@@ -0,0 +1,365 @@
+// add file in v.1.2.0
+// this file declare method that handling RPC about token (AuthToken service) in _default struct
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/hash"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"time"
+)
+
+func (h _default) RefreshToken(ctx context.Context, req *proto.RefreshTokenRequest, resp *proto.RefreshTokenResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	tokenHash := hash.SHA256ToHex(req.RefreshToken)
+	spanForDB := h.tracer.StartSpan("GetRefreshTokenWithHash", opentracing.ChildOf(parentSpan))
+	selectedToken, err := access.GetRefreshTokenWithHash(tokenHash)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedToken", selectedToken), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		// token already rotated is looked up again, because used token is presented only if it is stolen (or replayed)
+		spanForDB = h.tracer.StartSpan("GetDeletedRefreshTokenWithHash", opentracing.ChildOf(parentSpan))
+		deletedToken, err := access.GetDeletedRefreshTokenWithHash(tokenHash)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("DeletedToken", deletedToken), log.Error(err))
+		spanForDB.Finish()
+
+		switch err {
+		case nil:
+			h.rejectReusedRefreshToken(access, deletedToken, resp, parentSpan, reqID)
+		case gorm.ErrRecordNotFound:
+			access.Rollback()
+			resp.Status = http.StatusUnauthorized
+			resp.Message = fmt.Sprintf(unauthorizedMessageFormat, "refresh token not exists")
+		default:
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	if time.Now().After(selectedToken.ExpiresAt) {
+		access.Rollback()
+		resp.Status = http.StatusUnauthorized
+		resp.Message = fmt.Sprintf(unauthorizedMessageFormat, "refresh token is expired")
+		return
+	}
+
+	// refresh token can be used only once, so delete used token and issue new one (rotation)
+	spanForDB = h.tracer.StartSpan("DeleteRefreshToken", opentracing.ChildOf(parentSpan))
+	err = access.DeleteRefreshToken(tokenHash)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		// token is rotated in other request concurrently, so it is regarded as reused
+		h.rejectReusedRefreshToken(access, selectedToken, resp, parentSpan, reqID)
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to delete used refresh token, err: " + err.Error())
+		return
+	}
+
+	// token issued before session management doesn't belong to any session
+	if selectedToken.SessionID != "" {
+		spanForDB = h.tracer.StartSpan("ModifySessionLastSeenAt", opentracing.ChildOf(parentSpan))
+		err = access.ModifySessionLastSeenAt(string(selectedToken.SessionID), time.Now())
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to update last seen time of session, err: " + err.Error())
+			return
+		}
+	}
+
+	accessToken, refreshToken, err := h.issueTokenPair(access, string(selectedToken.OwnerUUID), string(selectedToken.SessionID), parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to refresh token"
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
+	return
+}
+
+// method that revoke session of reused refresh token & set response of rejecting it, tx of access is ended in this method
+// token issued before session management doesn't belong to any session, so it is only rejected
+func (h _default) rejectReusedRefreshToken(access db.Accessor, token *model.RefreshToken, resp *proto.RefreshTokenResponse, parentSpan jaeger.SpanContext, reqID string) {
+	if token.SessionID != "" {
+		if err := h.revokeSessionOfReusedToken(access, string(token.SessionID), parentSpan, reqID); err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+	}
+
+	access.Commit()
+	resp.Status = http.StatusUnauthorized
+	resp.Message = fmt.Sprintf(unauthorizedMessageFormat, "refresh token already used, so session of the token is revoked")
+}
+
+func (h _default) IntrospectToken(ctx context.Context, req *proto.IntrospectTokenRequest, resp *proto.IntrospectTokenResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	claims, err := h.verifyAccessToken(access, req.AccessToken, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = statusForTokenError(err)
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "access token is active"
+	resp.Active = true
+	resp.UUID = claims.Subject
+	resp.Role = claims.Role
+	resp.ExpiresAt = claims.ExpiresAt
+	return
+}
+
+func (h _default) RevokeToken(ctx context.Context, req *proto.RevokeTokenRequest, resp *proto.RevokeTokenResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	claims, err := h.verifyAccessToken(access, req.AccessToken, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = statusForTokenError(err)
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("CreateRevokedToken", opentracing.ChildOf(parentSpan))
+	revokedToken, err := access.CreateRevokedToken(&model.RevokedToken{
+		TokenID:   model.TokenID(claims.ID),
+		OwnerUUID: model.OwnerUUID(claims.Subject),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("RevokedToken", revokedToken), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to create revoked token, err: " + err.Error())
+		return
+	}
+
+	// refresh token is optional, revoke it together if it is token of same owner
+	if req.RefreshToken != "" {
+		tokenHash := hash.SHA256ToHex(req.RefreshToken)
+		spanForDB = h.tracer.StartSpan("GetRefreshTokenWithHash", opentracing.ChildOf(parentSpan))
+		selectedToken, err := access.GetRefreshTokenWithHash(tokenHash)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SelectedToken", selectedToken), log.Error(err))
+		spanForDB.Finish()
+
+		switch err {
+		case nil:
+			break
+		case gorm.ErrRecordNotFound:
+			access.Commit()
+			resp.Status = http.StatusOK
+			resp.Message = "succeed to revoke access token (refresh token already not exists)"
+			return
+		default:
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+			return
+		}
+
+		if string(selectedToken.OwnerUUID) != claims.Subject {
+			access.Rollback()
+			resp.Status = http.StatusForbidden
+			resp.Message = fmt.Sprintf(forbiddenMessageFormat, "refresh token is not yours")
+			return
+		}
+
+		spanForDB = h.tracer.StartSpan("DeleteRefreshToken", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRefreshToken(tokenHash)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+
+		// token deleted in other request concurrently is already revoked
+		if err != nil && err != gorm.ErrRecordNotFound {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to delete refresh token, err: " + err.Error())
+			return
+		}
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to revoke token"
+	return
+}
+
+func (h _default) RevokeAllSessions(ctx context.Context, req *proto.RevokeAllSessionsRequest, resp *proto.RevokeAllSessionsResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	claims, err := h.verifyAccessToken(access, req.AccessToken, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = statusForTokenError(err)
+		return
+	}
+
+	// revoke sessions of token owner if target uuid is empty
+	targetUUID := req.TargetUUID
+	if targetUUID == "" {
+		targetUUID = claims.Subject
+	}
+	if caller := identityFrom(claims); !rules["RevokeAllSessions"].allows(caller, targetUUID) {
+		access.Rollback()
+		resp.Status = http.StatusForbidden
+		resp.Message = fmt.Sprintf(forbiddenMessageFormat, fmt.Sprintf("%s(%s) is not permitted to call RevokeAllSessions", caller.Role, caller.UUID))
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("CreateSessionRevocation", opentracing.ChildOf(parentSpan))
+	revocation, err := access.CreateSessionRevocation(&model.SessionRevocation{
+		OwnerUUID:     model.OwnerUUID(targetUUID),
+		RevokedBefore: time.Now(),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SessionRevocation", revocation), log.Error(err))
+	spanForDB.Finish()
+
+	switch err.(type) {
+	case nil:
+		break
+	case validator.ValidationErrors:
+		access.Rollback()
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for session revocation model, err: " + err.Error())
+		return
+	default:
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to create session revocation, err: " + err.Error())
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("DeleteRefreshTokensWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	err = access.DeleteRefreshTokensWithOwnerUUID(targetUUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to delete refresh tokens, err: " + err.Error())
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("DeleteSessionsWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	err = access.DeleteSessionsWithOwnerUUID(targetUUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to delete sessions, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to revoke all sessions"
+	return
+}
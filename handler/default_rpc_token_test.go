@@ -0,0 +1,395 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_default_RefreshToken(t *testing.T) {
+	tests := []test.RefreshTokenCase{
+		{ // success case
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx": {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"DeleteRefreshToken": {nil},
+				"CreateRefreshToken": {&model.RefreshToken{}, nil},
+				"Commit":             {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // success case (refresh token issued in login session)
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx": {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					SessionID: "8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"DeleteRefreshToken":      {nil},
+				"ModifySessionLastSeenAt": {nil},
+				"CreateRefreshToken":      {&model.RefreshToken{}, nil},
+				"Commit":                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // invalid Span-Context -> Proxy Authorization Required
+			SpanContextString: "InvalidSpanContext",
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // refresh token not exists
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetRefreshTokenWithHash":        {&model.RefreshToken{}, gorm.ErrRecordNotFound},
+				"GetDeletedRefreshTokenWithHash": {&model.RefreshToken{}, gorm.ErrRecordNotFound},
+				"Rollback":                       {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+		}, { // refresh token already used -> revoke session of the token
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{}, gorm.ErrRecordNotFound},
+				"GetDeletedRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					SessionID: "8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"DeleteSession":                    {nil},
+				"DeleteRefreshTokensWithSessionID": {nil},
+				"Commit":                           {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+		}, { // refresh token issued before session management already used -> only rejected
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{}, gorm.ErrRecordNotFound},
+				"GetDeletedRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"Commit": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+		}, { // GetDeletedRefreshTokenWithHash unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetRefreshTokenWithHash":        {&model.RefreshToken{}, gorm.ErrRecordNotFound},
+				"GetDeletedRefreshTokenWithHash": {&model.RefreshToken{}, errors.New("unexpected error")},
+				"Rollback":                       {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // DeleteSession unexpected error in revoking session of used token
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{}, gorm.ErrRecordNotFound},
+				"GetDeletedRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					SessionID: "8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"DeleteSession": {errors.New("unexpected error")},
+				"Rollback":      {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // refresh token rotated in other request concurrently -> revoke session of the token
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx": {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					SessionID: "8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"DeleteRefreshToken":               {gorm.ErrRecordNotFound},
+				"DeleteSession":                    {nil},
+				"DeleteRefreshTokensWithSessionID": {nil},
+				"Commit":                           {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+		}, { // DeleteRefreshToken unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx": {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil},
+				"DeleteRefreshToken": {errors.New("unexpected error")},
+				"Rollback":           {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // GetRefreshTokenWithHash unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                 {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{}, errors.New("unexpected error")},
+				"Rollback":                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // expired refresh token
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx": {},
+				"GetRefreshTokenWithHash": {&model.RefreshToken{
+					OwnerUUID: "student-111111111111",
+					ExpiresAt: time.Now().Add(-time.Hour),
+				}, nil},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.RefreshTokenRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.RefreshTokenResponse)
+		_ = defaultHandler.RefreshToken(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedStatus == http.StatusOK, resp.AccessToken != "" && resp.RefreshToken != "", "token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_IntrospectToken(t *testing.T) {
+	revokedAt := time.Now().Truncate(time.Second)
+	sameSecondSession := &model.Session{SessionID: "3f1d2c4b-5a6e-4f70-8b9c-1d2e3f4a5b6c", OwnerUUID: "student-111111111111", LastSeenAt: revokedAt}
+
+	tests := []test.IntrospectTokenCase{
+		{ // success case
+			AccessToken: test.AccessTokenFor("student-111111111111", "student", time.Now()),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedUUID:   "student-111111111111",
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // invalid format of access token
+			AccessToken: "invalid.access.token",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":  {},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.InvalidAccessToken,
+		}, { // expired access token
+			AccessToken: test.AccessTokenFor("student-111111111111", "student", time.Now().Add(-time.Hour)),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":  {},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.ExpiredAccessToken,
+		}, { // revoked access token
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                    {},
+				"GetRevokedTokenWithTokenID": {&model.RevokedToken{}, nil},
+				"Rollback":                   {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.RevokedAccessToken,
+		}, { // access token issued before all sessions are revoked
+			AccessToken: test.AccessTokenFor("student-111111111111", "student", time.Now().Add(-time.Minute)),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                    {},
+				"GetRevokedTokenWithTokenID": {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{
+					OwnerUUID:     "student-111111111111",
+					RevokedBefore: time.Now(),
+				}, nil},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.RevokedAccessToken,
+		}, { // access token of new session issued in the same second that all sessions are revoked
+			AccessToken: test.SessionAccessTokenFor("student-111111111111", string(sameSecondSession.SessionID), revokedAt.Add(time.Millisecond*500)),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                    {},
+				"GetRevokedTokenWithTokenID": {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{
+					OwnerUUID:     "student-111111111111",
+					RevokedBefore: revokedAt,
+				}, nil},
+				"GetSessionWithSessionID": {sameSecondSession, nil},
+				"Commit":                  {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedUUID:   "student-111111111111",
+		}, { // access token of deleted session issued in the same second that all sessions are revoked
+			AccessToken: test.SessionAccessTokenFor("student-111111111111", string(sameSecondSession.SessionID), revokedAt.Add(time.Millisecond*500)),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                    {},
+				"GetRevokedTokenWithTokenID": {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{
+					OwnerUUID:     "student-111111111111",
+					RevokedBefore: revokedAt,
+				}, nil},
+				"GetSessionWithSessionID": {&model.Session{}, gorm.ErrRecordNotFound},
+				"Rollback":                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.RevokedAccessToken,
+		}, { // access token without session issued in the same second that all sessions are revoked
+			AccessToken: test.AccessTokenFor("student-111111111111", "student", revokedAt.Add(time.Millisecond*500)),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                    {},
+				"GetRevokedTokenWithTokenID": {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{
+					OwnerUUID:     "student-111111111111",
+					RevokedBefore: revokedAt,
+				}, nil},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.RevokedAccessToken,
+		}, { // GetRevokedTokenWithTokenID unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                    {},
+				"GetRevokedTokenWithTokenID": {&model.RevokedToken{}, errors.New("unexpected error")},
+				"Rollback":                   {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.IntrospectTokenRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.IntrospectTokenResponse)
+		_ = defaultHandler.IntrospectToken(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedUUID, resp.UUID, "uuid assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_RevokeAllSessions(t *testing.T) {
+	tests := []test.RevokeAllSessionsCase{
+		{ // success case (revoke sessions of other account by admin)
+			TargetUUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // success case (revoke own sessions)
+			AccessToken: test.AccessTokenFor("parent-111111111111", "parent", time.Now()),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist Span-Context -> Proxy Authorization Required
+			SpanContextString: test.EmptyReplaceValueForString,
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // revoke sessions of other account by not admin -> forbidden
+			AccessToken: test.AccessTokenFor("parent-111111111111", "parent", time.Now()),
+			TargetUUID:  "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // expired access token
+			AccessToken: test.AccessTokenFor("admin-111111111111", "admin", time.Now().Add(-time.Hour)),
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":  {},
+				"Rollback": {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedCode:   code.ExpiredAccessToken,
+		}, { // DeleteRefreshTokensWithOwnerUUID unexpected error
+			TargetUUID: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		}, { // DeleteSessionsWithOwnerUUID unexpected error
+			TargetUUID: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"CreateSessionRevocation":               {&model.SessionRevocation{}, nil},
+				"DeleteRefreshTokensWithOwnerUUID":      {nil},
+				"DeleteSessionsWithOwnerUUID":           {errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.RevokeAllSessionsRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.RevokeAllSessionsResponse)
+		_ = defaultHandler.RevokeAllSessions(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
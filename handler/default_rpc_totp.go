@@ -0,0 +1,531 @@
+// add file in v.1.2.0
+// this file declare method that handling RPC about TOTP two-factor authentication (AuthTOTP service) in _default struct
+// admin and teacher can enroll TOTP, and login of account with enabled TOTP is finished with LoginWithTOTP after password is checked
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/hash"
+	"auth/tool/jwt"
+	"auth/tool/random"
+	"auth/tool/totp"
+	code "auth/utils/code/golang"
+	topic "auth/utils/topic/golang"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	totpIssuer          = "DMS-SMS"
+	totpTokenPurpose    = "totp"
+	totpTokenExpiration = time.Minute * 5 // 비밀번호 확인 후 TOTP 코드를 입력해야 하는 시간
+	recoveryCodeCount   = 10              // 등록 시 발급하는 복구 코드 개수
+	recoveryCodeLength  = 10
+)
+
+func totpThrottleKey(uuid string) string { return fmt.Sprintf("totp:%s", uuid) }
+
+// method that return if TOTP of account is enrolled and verified, login of that account needs second step
+func (h _default) totpEnabled(access db.Accessor, ownerUUID string, parentSpan jaeger.SpanContext, reqID string) (enabled bool, err error) {
+	spanForDB := h.tracer.StartSpan("GetTwoFactorAuthWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedAuth, err := access.GetTwoFactorAuthWithOwnerUUID(ownerUUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		enabled = selectedAuth.EnabledAt != nil
+	case gorm.ErrRecordNotFound:
+		err = nil
+	default:
+		err = errors.New(fmt.Sprintf("unable to query two factor auth, err: %v", err))
+	}
+	return
+}
+
+// function that issue token used to call LoginWithTOTP, it can't be used as access token
+func issueTOTPToken(ownerUUID string) (token string, err error) {
+	now := time.Now()
+	token, err = jwt.GenerateWithHS256(jwt.Claims{
+		ID:        uuid.New().String(),
+		Issuer:    topic.AuthServiceName,
+		Subject:   ownerUUID,
+		Role:      string(roleOf(ownerUUID)),
+		Purpose:   totpTokenPurpose,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(totpTokenExpiration).Unix(),
+	}, []byte(jwtSecretKey))
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to generate totp token, err: %v", err))
+	}
+	return
+}
+
+// method that check code with TOTP secret or recovery codes of account, and mark matched code as used
+// 6 digits code is checked as TOTP code, and other code is checked as recovery code
+func (h _default) verifySecondFactor(access db.Accessor, auth *model.TwoFactorAuth, inputCode string, parentSpan jaeger.SpanContext, reqID string) (matched bool, err error) {
+	ownerUUID := string(auth.OwnerUUID)
+
+	if len(inputCode) != totp.Digits {
+		spanForDB := h.tracer.StartSpan("DeleteRecoveryCode", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRecoveryCode(ownerUUID, hash.SHA256ToHex(strings.ToLower(strings.TrimSpace(inputCode))))
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+
+		switch err {
+		case nil:
+			matched = true
+		case gorm.ErrRecordNotFound:
+			err = nil
+		default:
+			err = errors.New(fmt.Sprintf("unable to delete recovery code, err: %v", err))
+		}
+		return
+	}
+
+	step, err := totp.MatchedStep(string(auth.Secret), inputCode, time.Now())
+	if err == totp.ErrMismatchedCode || (err == nil && step <= auth.LastUsedStep) {
+		err = nil
+		return
+	}
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to check totp code, err: %v", err))
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("ModifyTwoFactorAuth", opentracing.ChildOf(parentSpan))
+	err = access.ModifyTwoFactorAuth(ownerUUID, auth.EnabledAt, step)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int64("LastUsedStep", step), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to update two factor auth, err: %v", err))
+		return
+	}
+	matched = true
+	return
+}
+
+func (h _default) EnrollTOTP(ctx context.Context, req *proto.EnrollTOTPRequest, resp *proto.EnrollTOTPResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "EnrollTOTP", req.UUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetTwoFactorAuthWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedAuth, err := access.GetTwoFactorAuthWithOwnerUUID(req.UUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		if selectedAuth.EnabledAt != nil {
+			access.Rollback()
+			resp.Status = http.StatusConflict
+			resp.Code = code.TOTPAlreadyEnabled
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "totp is already enabled, disable it before enrolling again")
+			return
+		}
+		// secret enrolled but not verified is replaced with new one
+		spanForDB = h.tracer.StartSpan("DeleteTwoFactorAuth", opentracing.ChildOf(parentSpan))
+		err = access.DeleteTwoFactorAuth(req.UUID)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	case gorm.ErrRecordNotFound:
+		err = nil
+	}
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to generate totp secret, err: " + err.Error())
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("CreateTwoFactorAuth", opentracing.ChildOf(parentSpan))
+	createdAuth, err := access.CreateTwoFactorAuth(&model.TwoFactorAuth{
+		OwnerUUID: model.OwnerUUID(req.UUID),
+		Secret:    model.TOTPSecret(secret),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedAuth", createdAuth), log.Error(err))
+	spanForDB.Finish()
+
+	if err == nil {
+		spanForDB = h.tracer.StartSpan("DeleteRecoveryCodesWithOwnerUUID", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRecoveryCodesWithOwnerUUID(req.UUID)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	}
+
+	if err != nil {
+		access.Rollback()
+		switch assertedError := err.(type) {
+		case validator.ValidationErrors:
+			resp.Status = http.StatusProxyAuthRequired
+			resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid data for model, err: " + assertedError.Error())
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to store two factor auth, err: " + err.Error())
+		}
+		return
+	}
+
+	recoveryCodes := make([]string, recoveryCodeCount)
+	for i := range recoveryCodes {
+		if recoveryCodes[i], err = random.SecureStringConsistOfLowerAlnumWithLength(recoveryCodeLength); err != nil {
+			break
+		}
+		spanForDB = h.tracer.StartSpan("CreateRecoveryCode", opentracing.ChildOf(parentSpan))
+		_, err = access.CreateRecoveryCode(&model.RecoveryCode{
+			OwnerUUID: model.OwnerUUID(req.UUID),
+			CodeHash:  model.CodeHash(hash.SHA256ToHex(recoveryCodes[i])),
+		})
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+		if err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to issue recovery codes, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusCreated
+	resp.Message = "totp enrolled, verify it with code of authenticator app to enable"
+	resp.Secret = secret
+	resp.KeyURI = totp.KeyURI(totpIssuer, req.UUID, secret)
+	resp.RecoveryCodes = recoveryCodes
+	return
+}
+
+func (h _default) VerifyTOTP(ctx context.Context, req *proto.VerifyTOTPRequest, resp *proto.VerifyTOTPResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "VerifyTOTP", req.UUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	// code is throttled with the same key as LoginWithTOTP, so that guessing code is not possible in either of them
+	throttleKey := totpThrottleKey(req.UUID)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, throttleKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetTwoFactorAuthWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedAuth, err := access.GetTwoFactorAuthWithOwnerUUID(req.UUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		switch err {
+		case gorm.ErrRecordNotFound:
+			resp.Status = http.StatusConflict
+			resp.Code = code.TOTPNotEnrolled
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "totp is not enrolled")
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	}
+
+	if selectedAuth.EnabledAt != nil {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.TOTPAlreadyEnabled
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "totp is already enabled")
+		return
+	}
+
+	step, err := totp.MatchedStep(string(selectedAuth.Secret), req.Code, time.Now())
+	if err == totp.ErrMismatchedCode {
+		h.commitLoginFailure(ctx, access, throttleKey)
+		resp.Status = http.StatusConflict
+		resp.Code = code.IncorrectTOTPCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "incorrect totp code")
+		return
+	}
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to check totp code, err: " + err.Error())
+		return
+	}
+
+	if err = h.resetLoginFailure(ctx, access, throttleKey); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	enabledAt := time.Now()
+	spanForDB = h.tracer.StartSpan("ModifyTwoFactorAuth", opentracing.ChildOf(parentSpan))
+	err = access.ModifyTwoFactorAuth(req.UUID, &enabledAt, step)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int64("LastUsedStep", step), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to update DB, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "totp enabled, it will be required from next login"
+	return
+}
+
+func (h _default) DisableTOTP(ctx context.Context, req *proto.DisableTOTPRequest, resp *proto.DisableTOTPResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "DisableTOTP", req.UUID); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetTwoFactorAuthWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedAuth, err := access.GetTwoFactorAuthWithOwnerUUID(req.UUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		access.Rollback()
+		switch err {
+		case gorm.ErrRecordNotFound:
+			resp.Status = http.StatusConflict
+			resp.Code = code.TOTPNotEnrolled
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "totp is not enrolled")
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	}
+
+	// owner must prove possession of second factor, admin can disable TOTP of other account (ex: lost device) without code
+	if selectedAuth.EnabledAt != nil && callerOf(ctx).UUID == req.UUID {
+		matched, err := h.verifySecondFactor(access, selectedAuth, req.Code, parentSpan, reqID)
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+			return
+		}
+		if !matched {
+			access.Rollback()
+			resp.Status = http.StatusConflict
+			resp.Code = code.IncorrectTOTPCode
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "incorrect totp code or recovery code")
+			return
+		}
+	}
+
+	spanForDB = h.tracer.StartSpan("DeleteTwoFactorAuth", opentracing.ChildOf(parentSpan))
+	err = access.DeleteTwoFactorAuth(req.UUID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err == nil {
+		spanForDB = h.tracer.StartSpan("DeleteRecoveryCodesWithOwnerUUID", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRecoveryCodesWithOwnerUUID(req.UUID)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	}
+
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to delete two factor auth, err: " + err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "totp disabled"
+	return
+}
+
+func (h _default) LoginWithTOTP(ctx context.Context, req *proto.LoginWithTOTPRequest, resp *proto.LoginWithTOTPResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	claims, err := jwt.ParseWithHS256(req.TOTPToken, []byte(jwtSecretKey))
+	if err == nil && claims.Purpose != totpTokenPurpose {
+		err = jwt.ErrInvalidTokenFormat
+	}
+	if err != nil {
+		resp.Status = http.StatusUnauthorized
+		resp.Code = code.InvalidTOTPToken
+		resp.Message = fmt.Sprintf(unauthorizedMessageFormat, "invalid totp token, please login with password again, err: " + err.Error())
+		return
+	}
+
+	// result is recorded in auth log after totp token is verified, because token that can't be parsed doesn't identify account
+	defer func() {
+		h.recordAuthLog(ctx, authAttempt{action: "LoginWithTOTP", accountType: roleOf(claims.Subject), ownerUUID: claims.Subject}, resp.Status, resp.Code)
+	}()
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	throttleKey := totpThrottleKey(claims.Subject)
+	if allowed, status, _code, message := h.checkLoginThrottle(ctx, access, throttleKey); !allowed {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetTwoFactorAuthWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	selectedAuth, err := access.GetTwoFactorAuthWithOwnerUUID(claims.Subject)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err == nil && selectedAuth.EnabledAt == nil {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		access.Rollback()
+		switch err {
+		case gorm.ErrRecordNotFound:
+			resp.Status = http.StatusConflict
+			resp.Code = code.TOTPNotEnrolled
+			resp.Message = fmt.Sprintf(conflictErrorFormat, "totp is not enabled, please login with password again")
+		default:
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+		}
+		return
+	}
+
+	matched, err := h.verifySecondFactor(access, selectedAuth, req.Code, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+	if !matched {
+		h.commitLoginFailure(ctx, access, throttleKey)
+		resp.Status = http.StatusConflict
+		resp.Code = code.IncorrectTOTPCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "incorrect totp code or recovery code")
+		return
+	}
+
+	if err = h.resetLoginFailure(ctx, access, throttleKey); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	accessToken, refreshToken, err := h.startSession(ctx, access, claims.Subject, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to login with totp"
+	resp.LoggedInUUID = claims.Subject
+	resp.AccessToken = accessToken
+	resp.RefreshToken = refreshToken
+	return
+}
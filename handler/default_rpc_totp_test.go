@@ -0,0 +1,263 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"auth/tool/totp"
+	code "auth/utils/code/golang"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_default_VerifyTOTP(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	currentCode, _ := totp.CodeAt(secret, totp.StepAt(time.Now()))
+	wrongCode, _ := totp.CodeAt(secret, totp.StepAt(time.Now()) + 10)
+	enabledAt := time.Now().Add(-time.Hour)
+	lockedUntil := time.Now().Add(time.Minute)
+
+	tests := []test.VerifyTOTPCase{
+		{ // success case
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLoginThrottleWithKey":               {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID":         {&model.TwoFactorAuth{Secret: model.TOTPSecret(secret)}, nil},
+				"DeleteLoginThrottle":                   {nil},
+				"ModifyTwoFactorAuth":                   {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // no exist Span-Context -> Proxy Authorization Required
+			SpanContextString: test.EmptyReplaceValueForString,
+			ExpectedMethods:   map[test.Method]test.Returns{},
+			ExpectedStatus:    http.StatusProxyAuthRequired,
+		}, { // forbidden (student can't use TOTP)
+			UUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // forbidden (not my account)
+			UUID:       "teacher-111111111112",
+			TargetUUID: "teacher-111111111113",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // TOTP not enrolled
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLoginThrottleWithKey":               {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID":         {&model.TwoFactorAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.TOTPNotEnrolled,
+		}, { // TOTP already enabled
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLoginThrottleWithKey":               {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID":         {&model.TwoFactorAuth{Secret: model.TOTPSecret(secret), EnabledAt: &enabledAt}, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.TOTPAlreadyEnabled,
+		}, { // incorrect TOTP code
+			Code: wrongCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLoginThrottleWithKey":               {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID":         {&model.TwoFactorAuth{Secret: model.TOTPSecret(secret)}, nil},
+				"IncreaseLoginThrottle":                 {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil":        {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.IncorrectTOTPCode,
+		}, { // locked for too many incorrect codes
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetLoginThrottleWithKey":               {&model.LoginThrottle{FailureCount: 5, LockedUntil: &lockedUntil}, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusLocked,
+			ExpectedCode:   code.AccountLockedForLogin,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.VerifyTOTPRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.VerifyTOTPResponse)
+		_ = defaultHandler.VerifyTOTP(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_LoginWithTOTP(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	currentStep := totp.StepAt(time.Now())
+	currentCode, _ := totp.CodeAt(secret, currentStep)
+	enabledAt := time.Now().Add(-time.Hour)
+
+	tests := []test.LoginWithTOTPCase{
+		{ // success case with TOTP code
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                       {},
+				"GetLoginThrottleWithKey":       {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{OwnerUUID: "admin-111111111111", Secret: model.TOTPSecret(secret), EnabledAt: &enabledAt}, nil},
+				"ModifyTwoFactorAuth":           {nil},
+				"DeleteLoginThrottle":           {nil},
+				"CreateSession":                 {&model.Session{}, nil},
+				"CreateRefreshToken":            {&model.RefreshToken{}, nil},
+				"Commit":                        {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus:       http.StatusOK,
+			ExpectedLoggedInUUID: "admin-111111111111",
+		}, { // success case with recovery code
+			Code: "abcde12345",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                       {},
+				"GetLoginThrottleWithKey":       {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{OwnerUUID: "admin-111111111111", Secret: model.TOTPSecret(secret), EnabledAt: &enabledAt}, nil},
+				"DeleteRecoveryCode":            {nil},
+				"DeleteLoginThrottle":           {nil},
+				"CreateSession":                 {&model.Session{}, nil},
+				"CreateRefreshToken":            {&model.RefreshToken{}, nil},
+				"Commit":                        {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus:       http.StatusOK,
+			ExpectedLoggedInUUID: "admin-111111111111",
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // access token can't be used as TOTP token
+			TOTPToken:       test.AccessTokenFor("admin-111111111111", "admin", time.Now()),
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedCode:    code.InvalidTOTPToken,
+		}, { // expired TOTP token
+			TOTPToken:       test.TOTPTokenFor("admin-111111111111", time.Now().Add(-time.Hour)),
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedCode:    code.InvalidTOTPToken,
+		}, { // TOTP code already used (replay)
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetLoginThrottleWithKey":        {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID":  {&model.TwoFactorAuth{OwnerUUID: "admin-111111111111", Secret: model.TOTPSecret(secret), EnabledAt: &enabledAt, LastUsedStep: currentStep + 1}, nil},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.IncorrectTOTPCode,
+		}, { // incorrect recovery code
+			Code: "abcde12345",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetLoginThrottleWithKey":        {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID":  {&model.TwoFactorAuth{OwnerUUID: "admin-111111111111", Secret: model.TOTPSecret(secret), EnabledAt: &enabledAt}, nil},
+				"DeleteRecoveryCode":             {gorm.ErrRecordNotFound},
+				"IncreaseLoginThrottle":          {&model.LoginThrottle{FailureCount: 1}, nil},
+				"ModifyLoginThrottleLockedUntil": {nil},
+				"Commit":                         {&gorm.DB{}},
+				"CreateAuthLog":                  {&model.AuthLog{}, nil},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.IncorrectTOTPCode,
+		}, { // TOTP disabled after password is checked
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                       {},
+				"GetLoginThrottleWithKey":       {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{}, gorm.ErrRecordNotFound},
+				"Rollback":                      {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
+				"Commit":                        {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.TOTPNotEnrolled,
+		}, { // GetTwoFactorAuthWithOwnerUUID unexpected error
+			Code: currentCode,
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                       {},
+				"GetLoginThrottleWithKey":       {&model.LoginThrottle{}, gorm.ErrRecordNotFound},
+				"GetTwoFactorAuthWithOwnerUUID": {&model.TwoFactorAuth{}, errors.New("unexpected error")},
+				"Rollback":                      {&gorm.DB{}},
+				"CreateAuthLog":                 {&model.AuthLog{}, nil},
+				"Commit":                        {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.LoginWithTOTPRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.LoginWithTOTPResponse)
+		_ = defaultHandler.LoginWithTOTP(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedLoggedInUUID, resp.LoggedInUUID, "logged in uuid assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedStatus == http.StatusOK, resp.AccessToken != "" && resp.RefreshToken != "", "token issuing assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
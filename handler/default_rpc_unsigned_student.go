@@ -0,0 +1,327 @@
+// add file in v.1.2.0
+// this file declare method that handling auth code of unsigned student RPC (in AuthAdmin service) in _default struct
+// auth code expires at end of signup deadline of school term, admin can regenerate or resend it and change expiry of it
+
+package handler
+
+import (
+	"auth/db"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
+	"strconv"
+	"net/http"
+	"time"
+)
+
+const (
+	authCodeMinValue    = 100000
+	authCodeMaxValue    = 999999
+	authCodeMinValidity = time.Hour * 24 * 7 // auth code issued after signup deadline is valid at least for this duration
+)
+
+// function that return default expiry of auth code issued at now, it is end of signup deadline day of school term
+func authCodeExpiryOf(term *model.SchoolTerm, now time.Time) time.Time {
+	deadline := term.SignupDeadline
+	expiresAt := time.Date(deadline.Year(), deadline.Month(), deadline.Day()+1, 0, 0, 0, 0, time.Local)
+	if minExpiresAt := now.Add(authCodeMinValidity); expiresAt.Before(minExpiresAt) {
+		expiresAt = minExpiresAt
+	}
+	return expiresAt
+}
+
+// function that return end of expire date in request (YYYY-MM-DD), auth code is valid until the end of that day
+func authCodeExpiryWithDate(expireDate string) (expiresAt time.Time, err error) {
+	date, err := time.ParseInLocation(signupDeadlineLayout, expireDate, time.Local)
+	if err != nil {
+		return
+	}
+	expiresAt = date.AddDate(0, 0, 1)
+	return
+}
+
+// function that return if auth code of unsigned student is expired, auth code issued before v.1.2.0 doesn't have expiry
+func authCodeExpired(student *model.UnsignedStudent, now time.Time) bool {
+	return student.AuthCodeExpiresAt != nil && !now.Before(*student.AuthCodeExpiresAt)
+}
+
+// method that render join SMS of unsigned student with template and queue it in outbox with batch id
+func (h _default) enqueueJoinSMS(access db.Accessor, tpl messageTemplate, term *model.SchoolTerm, student *model.UnsignedStudent, batchID string, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	content, err := tpl.render(joinSMSData{
+		SchoolName:     term.SchoolName,
+		Grade:          int64(student.Grade),
+		Class:          int64(student.Class),
+		StudentNumber:  int64(student.StudentNumber),
+		Name:           string(student.Name),
+		AuthCode:       int64(student.AuthCode),
+		SignupDeadline: signupDeadlineTextOf(term),
+	})
+	if err != nil {
+		err = fmt.Errorf("unable to render join message, err: %v", err)
+		return
+	}
+
+	err = h.enqueueMessage(access, &model.OutboxMessage{
+		BatchID:     batchID,
+		Receiver:    string(student.PhoneNumber),
+		Recipient:   fmt.Sprintf("%d%d%02d %s", student.Grade, student.Class, student.StudentNumber, student.Name),
+		MessageType: tpl.MessageType,
+		Title:       tpl.Title,
+		Content:     content,
+	}, parentSpan, reqID)
+	if err != nil {
+		err = fmt.Errorf("unable to queue join message, err: %v", err)
+	}
+	return
+}
+
+// method that return unsigned students of one class or one student in class, status & code & message is set if they can't be returned
+func (h _default) unsignedStudentsInClass(access db.Accessor, grade, group, number uint32, parentSpan jaeger.SpanContext, reqID string) (students []*model.UnsignedStudent, status uint32, _code int32, message string) {
+	spanForDB := h.tracer.StartSpan("GetUnsignedStudents", opentracing.ChildOf(parentSpan))
+	students, err := access.GetUnsignedStudents(int64(grade), int64(group), int64(number))
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Int("SelectedCount", len(students)), log.Error(err))
+	spanForDB.Finish()
+
+	switch {
+	case err == gorm.ErrRecordNotFound, err == nil && len(students) == 0:
+		status = http.StatusNotFound
+		message = fmt.Sprintf(notFoundMessageFormat, "unsigned student not exists with that grade & group & number")
+	case err != nil:
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, "unable to query DB, err: " + err.Error())
+	}
+	return
+}
+
+func (h _default) RegenerateUnsignedStudentAuthCodes(ctx context.Context, req *proto.RegenerateUnsignedStudentAuthCodesRequest, resp *proto.RegenerateUnsignedStudentAuthCodesResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	// codes of whole school can't be regenerated at once by mistake, target must be one class or one student in class
+	if req.TargetGrade == 0 || req.TargetGroup == 0 {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "target grade & group must be set")
+		return
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	if req.ExpireDate != "" {
+		var err error
+		if expiresAt, err = authCodeExpiryWithDate(req.ExpireDate); err != nil || !expiresAt.After(now) {
+			resp.Status = http.StatusProxyAuthRequired
+			resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid expire date, it must be future date in YYYY-MM-DD format, date: " + req.ExpireDate)
+			return
+		}
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "RegenerateUnsignedStudentAuthCodes", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	if req.ExpireDate == "" {
+		term, status, _code, message := h.currentSchoolTerm(access, parentSpan, reqID)
+		if status != 0 {
+			access.Rollback()
+			resp.Status, resp.Code, resp.Message = status, _code, message
+			return
+		}
+		expiresAt = authCodeExpiryOf(term, now)
+	}
+
+	students, status, _code, message := h.unsignedStudentsInClass(access, req.TargetGrade, req.TargetGroup, req.TargetNumber, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	for _, student := range students {
+		_, err = writeWithUniqueID(generateAuthCode, authCodeKeys, func(newAuthCode string) (err error) {
+			parsedAuthCode, _ := strconv.ParseInt(newAuthCode, 10, 64)
+			spanForDB := h.tracer.StartSpan("ChangeUnsignedStudentAuthCode", opentracing.ChildOf(parentSpan))
+			err = access.ChangeUnsignedStudentAuthCode(int64(student.AuthCode), parsedAuthCode, expiresAt)
+			spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("ExpiresAt", expiresAt.String()), log.Error(err))
+			spanForDB.Finish()
+			return
+		})
+
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to change auth code, err: " + err.Error())
+			return
+		}
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to regenerate auth codes, previous auth codes can't be used anymore"
+	resp.RegeneratedCount = uint32(len(students))
+	resp.ExpiresAt = expiresAt.Unix()
+	return
+}
+
+func (h _default) ChangeUnsignedStudentAuthCodeExpiry(ctx context.Context, req *proto.ChangeUnsignedStudentAuthCodeExpiryRequest, resp *proto.ChangeUnsignedStudentAuthCodeExpiryResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	if req.TargetGrade == 0 || req.TargetGroup == 0 {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "target grade & group must be set")
+		return
+	}
+
+	expiresAt, err := authCodeExpiryWithDate(req.ExpireDate)
+	if err != nil || !expiresAt.After(time.Now()) {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "invalid expire date, it must be future date in YYYY-MM-DD format, date: " + req.ExpireDate)
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "ChangeUnsignedStudentAuthCodeExpiry", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	students, status, _code, message := h.unsignedStudentsInClass(access, req.TargetGrade, req.TargetGroup, req.TargetNumber, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	for _, student := range students {
+		spanForDB := h.tracer.StartSpan("ChangeUnsignedStudentAuthCodeExpiry", opentracing.ChildOf(parentSpan))
+		err = access.ChangeUnsignedStudentAuthCodeExpiry(int64(student.AuthCode), expiresAt)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.String("ExpiresAt", expiresAt.String()), log.Error(err))
+		spanForDB.Finish()
+
+		if err != nil {
+			access.Rollback()
+			resp.Status = http.StatusInternalServerError
+			resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to change expiry of auth code, err: " + err.Error())
+			return
+		}
+	}
+
+	access.Commit()
+	resp.Status = http.StatusOK
+	resp.Message = "succeed to change expiry of auth codes"
+	resp.ChangedCount = uint32(len(students))
+	return
+}
+
+func (h _default) ResendJoinSMSToUnsignedStudent(ctx context.Context, req *proto.ResendJoinSMSToUnsignedStudentRequest, resp *proto.ResendJoinSMSToUnsignedStudentResponse) (_ error) {
+	ctx, proxyAuthenticated, reason := h.getContextFromMetadata(ctx)
+	if !proxyAuthenticated {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, reason)
+		return
+	}
+
+	reqID := ctx.Value("X-Request-Id").(string)
+	parentSpan := ctx.Value("Span-Context").(jaeger.SpanContext)
+
+	if req.Grade == 0 || req.Group == 0 || req.StudentNumber == 0 {
+		resp.Status = http.StatusProxyAuthRequired
+		resp.Message = fmt.Sprintf(proxyAuthRequiredMessageFormat, "grade & group & student number must be set")
+		return
+	}
+
+	access, err := h.accessManage.BeginTx()
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "tx begin fail, err: " + err.Error())
+		return
+	}
+
+	if authorized, status, _code, message := h.authorize(ctx, access, "ResendJoinSMSToUnsignedStudent", ""); !authorized {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	term, status, _code, message := h.currentSchoolTerm(access, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+
+	students, status, _code, message := h.unsignedStudentsInClass(access, req.Grade, req.Group, req.StudentNumber, parentSpan, reqID)
+	if status != 0 {
+		access.Rollback()
+		resp.Status, resp.Code, resp.Message = status, _code, message
+		return
+	}
+	student := students[0]
+
+	if authCodeExpired(student, time.Now()) {
+		access.Rollback()
+		resp.Status = http.StatusConflict
+		resp.Code = code.ExpiredSignupAuthCode
+		resp.Message = fmt.Sprintf(conflictErrorFormat, "auth code of student is expired, regenerate it before resend")
+		return
+	}
+
+	smsTemplate, err := h.messageTemplateWithName(access, joinSMSTemplateName, parentSpan, reqID)
+	if err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, "unable to get join message template, err: " + err.Error())
+		return
+	}
+
+	batchID := uuid.New().String()
+	if err = h.enqueueJoinSMS(access, smsTemplate, term, student, batchID, parentSpan, reqID); err != nil {
+		access.Rollback()
+		resp.Status = http.StatusInternalServerError
+		resp.Message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+		return
+	}
+
+	access.Commit()
+	resp.Status = http.StatusAccepted
+	resp.Message = "succeed to queue join message, check result with GetMessageDeliveryStatus"
+	resp.BatchID = batchID
+	return
+}
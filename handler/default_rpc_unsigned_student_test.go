@@ -0,0 +1,333 @@
+package handler
+
+import (
+	test "auth/handler/for_test"
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	code "auth/utils/code/golang"
+	"errors"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func unsignedStudentsOfClass(expiresAt time.Time) []*model.UnsignedStudent {
+	return []*model.UnsignedStudent{
+		{AuthCode: 111111, Grade: 2, Class: 2, StudentNumber: 7, Name: "박진홍", PhoneNumber: "01088378347", AuthCodeExpiresAt: &expiresAt},
+		{AuthCode: 222222, Grade: 2, Class: 2, StudentNumber: 8, Name: "홍길동", PhoneNumber: "01012345678", AuthCodeExpiresAt: &expiresAt},
+	}
+}
+
+func Test_default_RegenerateUnsignedStudentAuthCodes(t *testing.T) {
+	currentTerm := &model.SchoolTerm{AcademicYear: 2022, SignupDeadline: time.Now().AddDate(0, 1, 0), SchoolName: "대덕소프트웨어마이스터고등학교"}
+
+	tests := []test.RegenerateUnsignedStudentAuthCodesCase{
+		{ // success case (expiry of school term)
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetCurrentSchoolTerm":                  {currentTerm, nil},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now()), nil},
+				"ChangeUnsignedStudentAuthCode":         {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:           http.StatusOK,
+			ExpectedRegeneratedCount: 2,
+		}, { // success case (expiry in request)
+			ExpireDate: "2099-12-31",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now()), nil},
+				"ChangeUnsignedStudentAuthCode":         {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:           http.StatusOK,
+			ExpectedRegeneratedCount: 2,
+		}, { // no target group -> Proxy Authorization Required
+			TargetGroup:     test.EmptyReplaceValueForUint32,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // past expire date -> Proxy Authorization Required
+			ExpireDate:      "2000-01-01",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "teacher-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // school term not configured -> conflict
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetCurrentSchoolTerm":                  {&model.SchoolTerm{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.SchoolTermNotConfigured,
+		}, { // no unsigned student in class -> not found
+			ExpireDate: "2099-12-31",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetUnsignedStudents":                   {[]*model.UnsignedStudent{}, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		}, { // ChangeUnsignedStudentAuthCode unexpected error
+			ExpireDate: "2099-12-31",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now()), nil},
+				"ChangeUnsignedStudentAuthCode":         {errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.RegenerateUnsignedStudentAuthCodesRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.RegenerateUnsignedStudentAuthCodesResponse)
+		_ = defaultHandler.RegenerateUnsignedStudentAuthCodes(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedRegeneratedCount, resp.RegeneratedCount, "regenerated count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_ChangeUnsignedStudentAuthCodeExpiry(t *testing.T) {
+	tests := []test.ChangeUnsignedStudentAuthCodeExpiryCase{
+		{ // success case
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now()), nil},
+				"ChangeUnsignedStudentAuthCodeExpiry":   {nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus:       http.StatusOK,
+			ExpectedChangedCount: 2,
+		}, { // no expire date -> Proxy Authorization Required
+			ExpireDate:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // invalid expire date format -> Proxy Authorization Required
+			ExpireDate:      "12/31",
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "student-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // GetUnsignedStudents unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetUnsignedStudents":                   {[]*model.UnsignedStudent{}, errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.ChangeUnsignedStudentAuthCodeExpiryRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.ChangeUnsignedStudentAuthCodeExpiryResponse)
+		_ = defaultHandler.ChangeUnsignedStudentAuthCodeExpiry(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, testCase.ExpectedChangedCount, resp.ChangedCount, "changed count assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_ResendJoinSMSToUnsignedStudent(t *testing.T) {
+	currentTerm := &model.SchoolTerm{AcademicYear: 2022, SignupDeadline: time.Now().AddDate(0, 1, 0), SchoolName: "대덕소프트웨어마이스터고등학교"}
+
+	tests := []test.ResendJoinSMSToUnsignedStudentCase{
+		{ // success case
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetCurrentSchoolTerm":                  {currentTerm, nil},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now().Add(time.Hour))[:1], nil},
+				"GetLatestMessageTemplateWithName":      {&model.MessageTemplate{}, gorm.ErrRecordNotFound},
+				"CreateOutboxMessage":                   {&model.OutboxMessage{}, nil},
+				"Commit":                                {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusAccepted,
+		}, { // no student number -> Proxy Authorization Required
+			StudentNumber:   test.EmptyReplaceValueForUint32,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // not admin -> forbidden
+			UUID: "parent-111111111111",
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusForbidden,
+		}, { // no unsigned student -> not found
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetCurrentSchoolTerm":                  {currentTerm, nil},
+				"GetUnsignedStudents":                   {[]*model.UnsignedStudent{}, nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		}, { // expired auth code -> conflict
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetCurrentSchoolTerm":                  {currentTerm, nil},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now().Add(-time.Hour))[:1], nil},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.ExpiredSignupAuthCode,
+		}, { // CreateOutboxMessage unexpected error
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                               {},
+				"GetRevokedTokenWithTokenID":            {&model.RevokedToken{}, gorm.ErrRecordNotFound},
+				"GetLastSessionRevocationWithOwnerUUID": {&model.SessionRevocation{}, gorm.ErrRecordNotFound},
+				"GetCurrentSchoolTerm":                  {currentTerm, nil},
+				"GetUnsignedStudents":                   {unsignedStudentsOfClass(time.Now().Add(time.Hour))[:1], nil},
+				"GetLatestMessageTemplateWithName":      {&model.MessageTemplate{}, gorm.ErrRecordNotFound},
+				"CreateOutboxMessage":                   {&model.OutboxMessage{}, errors.New("unexpected error")},
+				"Rollback":                              {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.ResendJoinSMSToUnsignedStudentRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.ResendJoinSMSToUnsignedStudentResponse)
+		_ = defaultHandler.ResendJoinSMSToUnsignedStudent(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
+
+func Test_default_GetUnsignedStudentWithAuthCode(t *testing.T) {
+	expiredAt, validUntil := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := []test.GetUnsignedStudentWithAuthCodeCase{
+		{ // success case
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetUnsignedStudentWithAuthCode": {&model.UnsignedStudent{AuthCode: 123456, AuthCodeExpiresAt: &validUntil}, nil},
+				"Commit":                         {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // success case (auth code issued before expiry is added)
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetUnsignedStudentWithAuthCode": {&model.UnsignedStudent{AuthCode: 123456}, nil},
+				"Commit":                         {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusOK,
+		}, { // no exist X-Request-ID -> Proxy Authorization Required
+			XRequestID:      test.EmptyReplaceValueForString,
+			ExpectedMethods: map[test.Method]test.Returns{},
+			ExpectedStatus:  http.StatusProxyAuthRequired,
+		}, { // no exist auth code -> not found
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetUnsignedStudentWithAuthCode": {&model.UnsignedStudent{}, gorm.ErrRecordNotFound},
+				"Rollback":                       {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusNotFound,
+		}, { // expired auth code -> conflict
+			ExpectedMethods: map[test.Method]test.Returns{
+				"BeginTx":                        {},
+				"GetUnsignedStudentWithAuthCode": {&model.UnsignedStudent{AuthCode: 123456, AuthCodeExpiresAt: &expiredAt}, nil},
+				"Rollback":                       {&gorm.DB{}},
+			},
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   code.ExpiredSignupAuthCode,
+		},
+	}
+
+	for _, testCase := range tests {
+		newMock, defaultHandler := generateVarForTest()
+
+		testCase.ChangeEmptyValueToValidValue()
+		testCase.ChangeEmptyReplaceValueToEmptyValue()
+		testCase.OnExpectMethods(newMock)
+
+		var req = new(proto.GetUnsignedStudentWithAuthCodeRequest)
+		testCase.SetRequestContextOf(req)
+		ctx := testCase.GetMetadataContext()
+
+		var resp = new(proto.GetUnsignedStudentWithAuthCodeResponse)
+		_ = defaultHandler.GetUnsignedStudentWithAuthCode(ctx, req, resp)
+
+		assert.Equalf(t, int(testCase.ExpectedStatus), int(resp.Status), "status assertion error (test case: %v, message: %s)", testCase, resp.Message)
+		assert.Equalf(t, int(testCase.ExpectedCode), int(resp.Code), "code assertion error (test case: %v, message: %s)", testCase, resp.Message)
+
+		newMock.AssertExpectations(t)
+	}
+}
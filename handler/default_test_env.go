@@ -3,6 +3,7 @@ package handler
 import (
 	"auth/db"
 	"auth/db/access"
+	"auth/tool/message"
 	"fmt"
 	"github.com/stretchr/testify/mock"
 	jaegercfg "github.com/uber/jaeger-client-go/config"
@@ -23,6 +24,8 @@ const (
 	proxyAuthRequiredMessageFormat = "proxy auth required (reason: %s)"
 	conflictErrorFormat = "conflict (reason: %s)"
 	internalServerErrorFormat = "internal server error (reason: %s)"
+	unauthorizedMessageFormat = "unauthorized (reason: %s)"
+	lockedMessageFormat = "locked (reason: %s)"
 
 )
 
@@ -37,8 +40,9 @@ func generateVarForTest() (newMock *mock.Mock, h _default) {
 	if err != nil { log.Fatal(fmt.Sprintf("error while creating new access manage with mock, err: %v", err)) }
 
 	h = _default{
-		accessManage: mockAccessManage,
-		tracer:       exampleTracerForRPCService,
+		accessManage:  mockAccessManage,
+		tracer:        exampleTracerForRPCService,
+		messageSender: message.NewFake(),
 	}
 
 	return
@@ -0,0 +1,69 @@
+// add file in v.1.2.0
+// this file declare function that write row with random id or code generated by crypto/rand in tool/random
+// uniqueness of id is enforced by unique constraint in DB, so row is written again with new id if it fails with duplicate entry of id key
+
+package handler
+
+import (
+	"auth/model"
+	"auth/tool/dberr"
+	"auth/tool/random"
+	"fmt"
+	"strconv"
+)
+
+const maxUniqueIDAttempts = 5 // id is 12 random digits, so collision more than this count means something is wrong
+
+// key names of unique constraint that id is checked by
+var (
+	accountUUIDKeys = []string{"PRIMARY", model.StudentAuthInstance.UUID.KeyName()}
+	authCodeKeys    = []string{model.UnsignedStudentInstance.AuthCode.KeyName()}
+)
+
+// function that return generator of account uuid with prefix (student, teacher, parent)
+// uuid set in metadata (used in test) is returned in first call instead of random uuid
+func accountUUIDGenerator(prefix string, uuidInMetadata interface{}) func() (string, error) {
+	preset, _ := uuidInMetadata.(string)
+	return func() (uuid string, err error) {
+		if preset != "" {
+			uuid, preset = preset, ""
+			return
+		}
+		return random.SecureAccountUUIDWithPrefix(prefix)
+	}
+}
+
+// function that generate 6 digits signup auth code of unsigned student in string
+func generateAuthCode() (string, error) {
+	authCode, err := random.SecureInt64InRange(authCodeMinValue, authCodeMaxValue)
+	return strconv.FormatInt(authCode, 10), err
+}
+
+// function that call write (insert or update) with id returned from generate, and call it again with new id if id is already used
+// error returned from write is returned as it is (ex: duplicate entry of other key), so caller can handle it in the same way as before
+func writeWithUniqueID(generate func() (string, error), keys []string, write func(id string) error) (id string, err error) {
+	for attempt := 1; ; attempt++ {
+		if id, err = generate(); err != nil {
+			err = fmt.Errorf("unable to generate random id, err: %v", err)
+			return
+		}
+		err = write(id)
+		if attempt >= maxUniqueIDAttempts || !isDuplicateEntryOf(err, keys) {
+			return
+		}
+	}
+}
+
+// function that return if err is duplicate entry error of one of keys
+func isDuplicateEntryOf(err error, keys []string) bool {
+	dbErr, ok := dberr.From(err).(*dberr.Error)
+	if !ok || dbErr.Category != dberr.Duplicate {
+		return false
+	}
+	for _, k := range keys {
+		if dbErr.Key == k {
+			return true
+		}
+	}
+	return false
+}
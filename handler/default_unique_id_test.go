@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"auth/model"
+	"auth/tool/mysqlerr"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_writeWithUniqueID(t *testing.T) {
+	tests := []struct {
+		WriteErrors      []error
+		ExpectedAttempts int
+		ExpectedError    error
+	}{
+		{ // success case
+			WriteErrors:      []error{nil},
+			ExpectedAttempts: 1,
+		}, { // duplicate entry of PRIMARY -> write again with new id
+			WriteErrors:      []error{mysqlerr.DuplicateEntry("PRIMARY", "student-111111111111"), nil},
+			ExpectedAttempts: 2,
+		}, { // duplicate entry of uuid (MySQL 8.0 key name) -> write again with new id
+			WriteErrors:      []error{mysqlerr.DuplicateEntry("student_auths.uuid", "student-111111111111"), nil},
+			ExpectedAttempts: 2,
+		}, { // duplicate entry of other key -> return error as it is
+			WriteErrors:      []error{mysqlerr.DuplicateEntry(model.StudentAuthInstance.StudentID.KeyName(), "jinhong0719")},
+			ExpectedAttempts: 1,
+			ExpectedError:    mysqlerr.DuplicateEntry(model.StudentAuthInstance.StudentID.KeyName(), "jinhong0719"),
+		}, { // unexpected error -> return error as it is
+			WriteErrors:      []error{errors.New("unexpected error")},
+			ExpectedAttempts: 1,
+			ExpectedError:    errors.New("unexpected error"),
+		}, { // duplicate entry of id in every attempt -> give up after max attempts
+			WriteErrors:      nil,
+			ExpectedAttempts: maxUniqueIDAttempts,
+			ExpectedError:    mysqlerr.DuplicateEntry("PRIMARY", "student-111111111111"),
+		},
+	}
+
+	for _, testCase := range tests {
+		var attempts int
+		var ids = map[string]bool{}
+		id, err := writeWithUniqueID(accountUUIDGenerator("student", nil), accountUUIDKeys, func(id string) error {
+			ids[id] = true
+			attempts++
+			if testCase.WriteErrors == nil {
+				return mysqlerr.DuplicateEntry("PRIMARY", "student-111111111111")
+			}
+			return testCase.WriteErrors[attempts-1]
+		})
+
+		assert.Equalf(t, testCase.ExpectedAttempts, attempts, "attempts assertion error (test case: %v)", testCase)
+		assert.Equalf(t, testCase.ExpectedError, err, "error assertion error (test case: %v)", testCase)
+		assert.Equalf(t, attempts, len(ids), "new id must be generated in every attempt (test case: %v)", testCase)
+		assert.Regexpf(t, "^student-\\d{12}$", id, "id format assertion error (test case: %v)", testCase)
+	}
+}
@@ -1,10 +1,25 @@
 package handler
 
 import (
+	"auth/db"
+	"auth/model"
+	"auth/tool/hash"
+	"auth/tool/jwt"
+	"auth/tool/random"
+	code "auth/utils/code/golang"
+	topic "auth/utils/topic/golang"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 	"github.com/micro/go-micro/v2/metadata"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
 	"github.com/uber/jaeger-client-go"
+	"net/http"
+	"strings"
+	"time"
 )
 
 func (_ _default) getContextFromMetadata(ctx context.Context) (parsedCtx context.Context, proxyAuthenticated bool, reason string) {
@@ -54,5 +69,273 @@ func (_ _default) getContextFromMetadata(ctx context.Context) (parsedCtx context
 	if tUUID, ok := md.Get("TeacherUUID"); ok { parsedCtx = context.WithValue(parsedCtx, "TeacherUUID", tUUID) }
 	if pUUID, ok := md.Get("ParentUUID"); ok  { parsedCtx = context.WithValue(parsedCtx, "ParentUUID", pUUID) }
 
+	// last address of X-Forwarded-For is used as source of request for login throttling (add in v.1.2.0)
+	// it is appended by gateway, while former addresses can be written by client to avoid source lock
+	if forwardedFor, ok := md.Get("X-Forwarded-For"); ok {
+		hops := strings.Split(forwardedFor, ",")
+		parsedCtx = context.WithValue(parsedCtx, "SourceIP", strings.TrimSpace(hops[len(hops)-1]))
+	}
+
+	// client information stored in login session (add in v.1.2.0)
+	if userAgent, ok := md.Get("User-Agent"); ok  { parsedCtx = context.WithValue(parsedCtx, "UserAgent", userAgent) }
+	if device, ok := md.Get("X-Device-Name"); ok { parsedCtx = context.WithValue(parsedCtx, "Device", device) }
+
+	// access token is verified in authorize method because it needs to query revocation store (add in v.1.2.0)
+	if authorization, ok := md.Get("Authorization"); ok {
+		parsedCtx = context.WithValue(parsedCtx, "AccessToken", strings.TrimPrefix(authorization, "Bearer "))
+	}
+
+	return
+}
+
+// add in v.1.2.0
+const (
+	accessTokenExpiration  = time.Minute * 30
+	refreshTokenExpiration = time.Hour * 24 * 14
+)
+
+// method that create login session of account with client information in context and issue token pair of the session (add in v.1.2.0)
+// every Login* RPC must call this method instead of issueTokenPair, so that session can be listed and revoked by owner
+func (h _default) startSession(ctx context.Context, access db.Accessor, ownerUUID string, parentSpan jaeger.SpanContext, reqID string) (accessToken, refreshToken string, err error) {
+	device, _ := ctx.Value("Device").(string)
+	userAgent, _ := ctx.Value("UserAgent").(string)
+	sourceIP, _ := ctx.Value("SourceIP").(string)
+
+	spanForDB := h.tracer.StartSpan("CreateSession", opentracing.ChildOf(parentSpan))
+	createdSession, err := access.CreateSession(&model.Session{
+		SessionID:  model.SessionID(uuid.New().String()),
+		OwnerUUID:  model.OwnerUUID(ownerUUID),
+		Device:     truncate(device, 100),
+		UserAgent:  truncate(userAgent, 500),
+		IPAddress:  truncate(sourceIP, 45),
+		LastSeenAt: time.Now(),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedSession", createdSession), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to create session, err: %v", err))
+		return
+	}
+
+	accessToken, refreshToken, err = h.issueTokenPair(access, ownerUUID, string(createdSession.SessionID), parentSpan, reqID)
+	return
+}
+
+// function that cut string to max length in unit of character, used for storing client information in fixed size column
+func truncate(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
+
+// method that issue signed access token & create refresh token of account in transaction (add in v.1.2.0)
+// sessionID is id of login session that tokens belong to, and it is included in sid claim of access token
+func (h _default) issueTokenPair(access db.Accessor, ownerUUID, sessionID string, parentSpan jaeger.SpanContext, reqID string) (accessToken, refreshToken string, err error) {
+	now := time.Now()
+	accessToken, err = jwt.GenerateWithHS256(jwt.Claims{
+		ID:        uuid.New().String(),
+		Issuer:    topic.AuthServiceName,
+		Subject:   ownerUUID,
+		Role:      string(roleOf(ownerUUID)),
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(accessTokenExpiration).Unix(),
+	}, []byte(jwtSecretKey))
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to generate access token, err: %v", err))
+		return
+	}
+
+	refreshToken, err = random.URLSafeStringWithByteLength(32)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to generate refresh token, err: %v", err))
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("CreateRefreshToken", opentracing.ChildOf(parentSpan))
+	createdToken, err := access.CreateRefreshToken(&model.RefreshToken{
+		TokenHash: model.TokenHash(hash.SHA256ToHex(refreshToken)),
+		OwnerUUID: model.OwnerUUID(ownerUUID),
+		SessionID: model.SessionID(sessionID),
+		ExpiresAt: now.Add(refreshTokenExpiration),
+	})
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("CreatedToken", createdToken), log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to create refresh token, err: %v", err))
+	}
+	return
+}
+
+// method that revoke session of refresh token used again after rotated, because the token (or the one rotated from it) may be stolen
+// tokens issued in the session after rotation are deleted together, so both of legitimate user and attacker have to login again
+func (h _default) revokeSessionOfReusedToken(access db.Accessor, sessionID string, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	spanForDB := h.tracer.StartSpan("DeleteSession", opentracing.ChildOf(parentSpan))
+	err = access.DeleteSession(sessionID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err == nil {
+		spanForDB = h.tracer.StartSpan("DeleteRefreshTokensWithSessionID", opentracing.ChildOf(parentSpan))
+		err = access.DeleteRefreshTokensWithSessionID(sessionID)
+		spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+		spanForDB.Finish()
+	}
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to revoke session of reused refresh token, err: %v", err))
+	}
+	return
+}
+
+// method that rehash pw with current hash policy if stored hash is legacy(pbkdf2) or made with other algorithm or cost (add in v.1.2.0)
+// changePW is accessor method that store pw of account (ex: access.ChangeStudentPW), so pw is migrated without password reset
+func (h _default) rehashPWIfNeeded(changePW func(uuid, pw string) error, uuid, storedHash, pw string, parentSpan jaeger.SpanContext, reqID string) (err error) {
+	if !hashPolicy.NeedsRehash(storedHash) {
+		return
+	}
+
+	spanForHash := h.tracer.StartSpan("GenerateFromPassword", opentracing.ChildOf(parentSpan))
+	hashedPW, err := hashPolicy.GenerateFromPassword(pw)
+	spanForHash.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForHash.Finish()
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to rehash pw, err: %v", err))
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("ChangePW", opentracing.ChildOf(parentSpan))
+	err = changePW(uuid, hashedPW)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Error(err))
+	spanForDB.Finish()
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to store rehashed pw, err: %v", err))
+	}
+	return
+}
+
+// function that check pw with password policy, it returns status, code and message to set in response if pw is weak (add in v.1.2.0)
+// accountID is id of account that pw will be set, pw containing it is not allowed
+func checkPasswordStrength(pw, accountID string) (strong bool, status uint32, _code int32, message string) {
+	if err := passwordPolicy.Validate(pw, accountID); err != nil {
+		status = http.StatusConflict
+		_code = code.WeakPassword
+		message = fmt.Sprintf(conflictErrorFormat, "weak password, " + err.Error())
+		return
+	}
+
+	strong = true
+	return
+}
+
+// function that return role of account with uuid, it is used only for issuing token with uuid selected from DB
+func roleOf(uuid string) (r role) {
+	switch true {
+	case adminUUIDRegex.MatchString(uuid):
+		r = adminRole
+	case studentUUIDRegex.MatchString(uuid):
+		r = studentRole
+	case teacherUUIDRegex.MatchString(uuid):
+		r = teacherRole
+	case parentUUIDRegex.MatchString(uuid):
+		r = parentRole
+	}
+	return
+}
+
+// error returned from verifyAccessToken if token is revoked by RevokeToken or RevokeAllSessions (add in v.1.2.0)
+var errRevokedToken = errors.New("token is revoked")
+
+// method that verify signature, expiration of access token and check revocation store (add in v.1.2.0)
+// it returns errors declared in jwt package or errRevokedToken if token is not active, and other error if unable to query DB
+func (h _default) verifyAccessToken(access db.Accessor, token string, parentSpan jaeger.SpanContext, reqID string) (claims jwt.Claims, err error) {
+	if claims, err = jwt.ParseWithHS256(token, []byte(jwtSecretKey)); err != nil {
+		return
+	}
+	if claims.Purpose != "" {
+		err = jwt.ErrInvalidTokenFormat // token issued for other purpose (ex: TOTP login) can't be used as access token
+		return
+	}
+
+	spanForDB := h.tracer.StartSpan("GetRevokedTokenWithTokenID", opentracing.ChildOf(parentSpan))
+	revokedToken, err := access.GetRevokedTokenWithTokenID(claims.ID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("RevokedToken", revokedToken), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		err = errRevokedToken
+		return
+	case gorm.ErrRecordNotFound:
+		break
+	default:
+		err = errors.New(fmt.Sprintf("unable to query revoked token, err: %v", err))
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("GetLastSessionRevocationWithOwnerUUID", opentracing.ChildOf(parentSpan))
+	revocation, err := access.GetLastSessionRevocationWithOwnerUUID(claims.Subject)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("SessionRevocation", revocation), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		// RevokedBefore is stored in unit of second as iat, so token issued in the same second is checked with its session below
+		// (sessions are deleted together with revocation), but token without session is regarded as revoked in that second
+		revokedBefore := revocation.RevokedBefore.Unix()
+		if claims.IssuedAt < revokedBefore || (claims.SessionID == "" && claims.IssuedAt == revokedBefore) {
+			err = errRevokedToken
+			return
+		}
+	case gorm.ErrRecordNotFound:
+		break
+	default:
+		err = errors.New(fmt.Sprintf("unable to query session revocation, err: %v", err))
+		return
+	}
+
+	// token issued in login session is revoked together when the session is deleted (ex: sign out of lost device)
+	if claims.SessionID == "" {
+		err = nil
+		return
+	}
+
+	spanForDB = h.tracer.StartSpan("GetSessionWithSessionID", opentracing.ChildOf(parentSpan))
+	session, err := access.GetSessionWithSessionID(claims.SessionID)
+	spanForDB.SetTag("X-Request-Id", reqID).LogFields(log.Object("Session", session), log.Error(err))
+	spanForDB.Finish()
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		err = errRevokedToken
+	default:
+		err = errors.New(fmt.Sprintf("unable to query session, err: %v", err))
+	}
+	return
+}
+
+// function that set status, code and message of response according to error returned from verifyAccessToken (add in v.1.2.0)
+func statusForTokenError(err error) (status uint32, _code int32, message string) {
+	switch err {
+	case jwt.ErrInvalidTokenFormat, jwt.ErrInvalidSignature, jwt.ErrUnsupportedAlg:
+		status, _code = http.StatusUnauthorized, code.InvalidAccessToken
+		message = fmt.Sprintf(unauthorizedMessageFormat, "invalid access token, err: " + err.Error())
+	case jwt.ErrExpiredToken:
+		status, _code = http.StatusUnauthorized, code.ExpiredAccessToken
+		message = fmt.Sprintf(unauthorizedMessageFormat, "access token is expired")
+	case errRevokedToken:
+		status, _code = http.StatusUnauthorized, code.RevokedAccessToken
+		message = fmt.Sprintf(unauthorizedMessageFormat, "access token is revoked")
+	default:
+		status = http.StatusInternalServerError
+		message = fmt.Sprintf(internalServerErrorFormat, err.Error())
+	}
 	return
-}
\ No newline at end of file
+}
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"auth/tool/hash"
+	"auth/tool/password"
 	"log"
 	"os"
+	"strconv"
 )
 
 var s3Bucket string
 var dmsAPIKey string
+var jwtSecretKey string // add in v.1.2.0
+var hashPolicy = hash.DefaultPolicy // add in v.1.2.0
+var passwordPolicy = password.DefaultPolicy // add in v.1.2.0
+var passwordHistoryDepth = 5 // 재사용을 막을 최근 비밀번호 개수, 0 이면 검사하지 않음 (add in v.1.2.0)
 
 func init() {
 	if s3Bucket = os.Getenv("SMS_AWS_BUCKET"); s3Bucket == "" {
@@ -15,4 +22,54 @@ func init() {
 	if dmsAPIKey = os.Getenv("DMS_API_KEY"); s3Bucket == "" {
 		log.Fatal("please set DMS_API_KEY in environment variable")
 	}
+	if jwtSecretKey = os.Getenv("JWT_SECRET_KEY"); jwtSecretKey == "" {
+		log.Fatal("please set JWT_SECRET_KEY in environment variable")
+	}
+	if err := setHashPolicyFromEnv(); err != nil {
+		log.Fatalf("unable to set password hash policy from environment variable, err: %v", err)
+	}
+	if depth := os.Getenv("PASSWORD_HISTORY_DEPTH"); depth != "" {
+		if parsed, err := strconv.Atoi(depth); err != nil || parsed < 0 {
+			log.Fatalf("PASSWORD_HISTORY_DEPTH must be non-negative integer, value: %s", depth)
+		} else {
+			passwordHistoryDepth = parsed
+		}
+	}
+}
+
+// function that set hash policy from environment variable, default policy is used for variable that is not set (add in v.1.2.0)
+// PASSWORD_HASH_ALGORITHM: bcrypt(default) or argon2id
+// PASSWORD_HASH_COST: cost of bcrypt (default 12) or iteration count of argon2id (default 3)
+// PASSWORD_HASH_MEMORY: memory of argon2id in KiB (default 65536)
+// PASSWORD_HASH_THREADS: parallelism of argon2id (default 2)
+func setHashPolicyFromEnv() (err error) {
+	if algorithm := os.Getenv("PASSWORD_HASH_ALGORITHM"); algorithm != "" {
+		hashPolicy.Algorithm = hash.Algorithm(algorithm)
+	}
+	if hashPolicy.Algorithm == hash.Argon2id {
+		hashPolicy.Cost, hashPolicy.Memory, hashPolicy.Threads = 3, 64 * 1024, 2
+	}
+
+	if cost := os.Getenv("PASSWORD_HASH_COST"); cost != "" {
+		if hashPolicy.Cost, err = strconv.Atoi(cost); err != nil {
+			return
+		}
+	}
+	if memory := os.Getenv("PASSWORD_HASH_MEMORY"); memory != "" {
+		var parsed uint64
+		if parsed, err = strconv.ParseUint(memory, 10, 32); err != nil {
+			return
+		}
+		hashPolicy.Memory = uint32(parsed)
+	}
+	if threads := os.Getenv("PASSWORD_HASH_THREADS"); threads != "" {
+		var parsed uint64
+		if parsed, err = strconv.ParseUint(threads, 10, 8); err != nil {
+			return
+		}
+		hashPolicy.Threads = uint8(parsed)
+	}
+
+	err = hashPolicy.Validate()
+	return
 }
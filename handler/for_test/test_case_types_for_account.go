@@ -0,0 +1,155 @@
+package test
+
+import (
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type DeleteStudentCase struct {
+	UUID              string
+	StudentUUID       string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *DeleteStudentCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.StudentUUID == ""       { test.StudentUUID = validStudentUUID() }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *DeleteStudentCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.StudentUUID == EmptyReplaceValueForString       { test.StudentUUID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *DeleteStudentCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *DeleteStudentCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetStudentAuthWithUUID", "DeleteStudentAuth", "DeleteStudentInform", "DeleteParentChildrenWithStudentUUID",
+		"DeleteRefreshTokensWithOwnerUUID", "DeleteSessionsWithOwnerUUID":
+		mock.On(string(method), test.StudentUUID).Return(returns...)
+	case "CreateSessionRevocation":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *DeleteStudentCase) SetRequestContextOf(req *proto.DeleteStudentRequest) {
+	req.StudentUUID = test.StudentUUID
+}
+
+func (test *DeleteStudentCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
+
+type RestoreParentCase struct {
+	UUID              string
+	ParentUUID        string
+	ParentID          string
+	DeletedAt         time.Time
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *RestoreParentCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.ParentUUID == ""        { test.ParentUUID = validParentUUID() }
+	if test.ParentID == ""          { test.ParentID = validParentID }
+	if test.DeletedAt.IsZero()      { test.DeletedAt = time.Now().Add(-time.Hour * 24) }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *RestoreParentCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.ParentUUID == EmptyReplaceValueForString        { test.ParentUUID = "" }
+	if test.ParentID == EmptyReplaceValueForString          { test.ParentID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *RestoreParentCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+// deleted parent auth returned from GetDeletedParentAuthWithUUID if returns of the method is empty in test case
+func (test *RestoreParentCase) deletedParentAuth() *model.ParentAuth {
+	deletedAt := test.DeletedAt
+	auth := &model.ParentAuth{
+		Model:    createGormModelOnCurrentTime(),
+		UUID:     model.UUID(test.ParentUUID),
+		ParentID: model.ParentID(test.ParentID),
+	}
+	auth.DeletedAt = &deletedAt
+	return auth
+}
+
+func (test *RestoreParentCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetDeletedParentAuthWithUUID":
+		if len(returns) == 0 { returns = Returns{test.deletedParentAuth(), nil} }
+		mock.On(string(method), test.ParentUUID).Return(returns...)
+	case "RestoreParentAuth", "RestoreParentInform":
+		mock.On(string(method), test.ParentUUID).Return(returns...)
+	case "RestoreParentChildrenWithParentUUID":
+		mock.On(string(method), test.ParentUUID, test.DeletedAt).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *RestoreParentCase) SetRequestContextOf(req *proto.RestoreParentRequest) {
+	req.ParentUUID = test.ParentUUID
+}
+
+func (test *RestoreParentCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
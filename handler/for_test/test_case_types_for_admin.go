@@ -8,11 +8,15 @@ import (
 	"github.com/micro/go-micro/v2/metadata"
 	"github.com/stretchr/testify/mock"
 	"log"
+	"time"
 )
 
 type Method string
 type Returns []interface{}
 
+// anyArgument is used in onMethod for argument that can't be expected in test case (ex. random generated token)
+const anyArgument = mock.Anything
+
 type CreateNewStudentCase struct {
 	UUID                 string
 	StudentID, StudentPW string
@@ -98,6 +102,9 @@ func (test *CreateNewStudentCase) onMethod(mock *mock.Mock, method Method, retur
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
 
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 
@@ -113,7 +120,6 @@ func (test *CreateNewStudentCase) getStudentAuthModel() *model.StudentAuth {
 	return &model.StudentAuth{
 		UUID:       model.UUID(test.StudentUUID),
 		StudentID:  model.StudentID(test.StudentID),
-		StudentPW:  model.StudentPW(test.StudentPW),
 		ParentUUID: model.ParentUUID(test.ParentUUID),
 	}
 }
@@ -131,7 +137,6 @@ func (test *CreateNewStudentCase) getStudentInformModel() *model.StudentInform {
 }
 
 func (test *CreateNewStudentCase) SetRequestContextOf(req *proto.CreateNewStudentRequest) {
-	req.UUID = test.UUID
 	req.StudentID = test.StudentID
 	req.StudentPW = test.StudentPW
 	req.ParentUUID = test.ParentUUID
@@ -147,6 +152,7 @@ func (test *CreateNewStudentCase) GetMetadataContext() (ctx context.Context) {
 	ctx = context.Background()
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 	ctx = metadata.Set(ctx, "StudentUUID", test.StudentUUID)
 	return
 }
@@ -227,6 +233,9 @@ func (test *CreateNewTeacherCase) onMethod(mock *mock.Mock, method Method, retur
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
 
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 
@@ -242,7 +251,6 @@ func (test *CreateNewTeacherCase) getTeacherAuthModel() *model.TeacherAuth {
 	return &model.TeacherAuth{
 		UUID:       model.UUID(test.TeacherUUID),
 		TeacherID:  model.TeacherID(test.TeacherID),
-		TeacherPW:  model.TeacherPW(test.TeacherPW),
 	}
 }
 
@@ -257,7 +265,6 @@ func (test *CreateNewTeacherCase) getTeacherInformModel() *model.TeacherInform {
 }
 
 func (test *CreateNewTeacherCase) SetRequestContextOf(req *proto.CreateNewTeacherRequest) {
-	req.UUID = test.UUID
 	req.TeacherID = test.TeacherID
 	req.TeacherPW = test.TeacherPW
 	req.Grade = test.Grade
@@ -270,6 +277,7 @@ func (test *CreateNewTeacherCase) GetMetadataContext() (ctx context.Context) {
 	ctx = context.Background()
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 	ctx = metadata.Set(ctx, "TeacherUUID", test.TeacherUUID)
 	return
 }
@@ -345,6 +353,9 @@ func (test *CreateNewParentCase) onMethod(mock *mock.Mock, method Method, return
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
 
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 
@@ -360,7 +371,6 @@ func (test *CreateNewParentCase) getParentAuthModel() *model.ParentAuth {
 	return &model.ParentAuth{
 		UUID:     model.UUID(test.ParentUUID),
 		ParentID: model.ParentID(test.ParentID),
-		ParentPW: model.ParentPW(test.ParentPW),
 	}
 }
 
@@ -373,7 +383,6 @@ func (test *CreateNewParentCase) getParentInformModel() *model.ParentInform {
 }
 
 func (test *CreateNewParentCase) SetRequestContextOf(req *proto.CreateNewParentRequest) {
-	req.UUID = test.UUID
 	req.ParentID = test.ParentID
 	req.ParentPW = test.ParentPW
 	req.Name = test.Name
@@ -384,6 +393,7 @@ func (test *CreateNewParentCase) GetMetadataContext() (ctx context.Context) {
 	ctx = context.Background()
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 	ctx = metadata.Set(ctx, "ParentUUID", test.ParentUUID)
 	return
 }
@@ -427,6 +437,20 @@ func (test *LoginAdminAuthCase) onMethod(mock *mock.Mock, method Method, returns
 		mock.On(string(method)).Return(returns...)
 	case "GetAdminAuthWithID":
 		mock.On(string(method), test.AdminID).Return(returns...)
+	case "GetLoginThrottleWithKey", "DeleteLoginThrottle":
+		mock.On(string(method), "admin:" + test.AdminID).Return(returns...)
+	case "ChangeAdminPW":
+		mock.On(string(method), anyArgument, "").Return(returns...)
+	case "GetTwoFactorAuthWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "IncreaseLoginThrottle", "ModifyLoginThrottleLockedUntil":
+		mock.On(string(method), "admin:" + test.AdminID, anyArgument).Return(returns...)
+	case "CreateSession":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateRefreshToken":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -446,4 +470,158 @@ func (test *LoginAdminAuthCase) GetMetadataContext() (ctx context.Context) {
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
 
 	return
-}
\ No newline at end of file
+}
+type GetAuthLogsCase struct {
+	UUID                 string
+	AccountType          string
+	AccountID            string
+	OwnerUUID            string
+	Outcome              string
+	Since, Until         int64
+	Limit                int32
+	XRequestID           string
+	SpanContextString    string
+	ExpectedMethods      map[Method]Returns
+	ExpectedStatus       uint32
+	ExpectedCode         int32
+	ExpectedMessage      string
+	ExpectedLimit        int
+	ExpectedAuthLogCount int
+}
+
+func (test *GetAuthLogsCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *GetAuthLogsCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *GetAuthLogsCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *GetAuthLogsCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetAuthLogs":
+		mock.On(string(method), &model.AuthLog{
+			AccountType: test.AccountType,
+			AccountID:   test.AccountID,
+			OwnerUUID:   test.OwnerUUID,
+			Outcome:     test.Outcome,
+		}, anyArgument, anyArgument, test.ExpectedLimit).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *GetAuthLogsCase) SetRequestContextOf(req *proto.GetAuthLogsRequest) {
+	req.AccountType = test.AccountType
+	req.AccountID = test.AccountID
+	req.OwnerUUID = test.OwnerUUID
+	req.Outcome = test.Outcome
+	req.Since = test.Since
+	req.Until = test.Until
+	req.Limit = test.Limit
+}
+
+func (test *GetAuthLogsCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
+
+type ListAuditEventsCase struct {
+	UUID                    string
+	ActorUUID               string
+	RPC                     string
+	Entity                  string
+	TargetKey               string
+	Action                  string
+	Cursor                  uint64
+	Limit                   int32
+	XRequestID              string
+	SpanContextString       string
+	ExpectedMethods         map[Method]Returns
+	ExpectedStatus          uint32
+	ExpectedCode            int32
+	ExpectedMessage         string
+	ExpectedLimit           int
+	ExpectedAuditEventCount int
+	ExpectedNextCursor      uint64
+}
+
+func (test *ListAuditEventsCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ListAuditEventsCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ListAuditEventsCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ListAuditEventsCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetAuditEvents":
+		mock.On(string(method), &model.AuditEvent{
+			ActorUUID: test.ActorUUID,
+			RPC:       test.RPC,
+			Entity:    test.Entity,
+			TargetKey: test.TargetKey,
+			Action:    test.Action,
+		}, uint(test.Cursor), test.ExpectedLimit).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ListAuditEventsCase) SetRequestContextOf(req *proto.ListAuditEventsRequest) {
+	req.ActorUUID = test.ActorUUID
+	req.RPC = test.RPC
+	req.Entity = test.Entity
+	req.TargetKey = test.TargetKey
+	req.Action = test.Action
+	req.Cursor = test.Cursor
+	req.Limit = test.Limit
+}
+
+func (test *ListAuditEventsCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type GetMessageDeliveryStatusCase struct {
+	UUID                 string
+	BatchID              string
+	XRequestID           string
+	SpanContextString    string
+	ExpectedMethods      map[Method]Returns
+	ExpectedStatus       uint32
+	ExpectedCode         int32
+	ExpectedMessage      string
+	ExpectedSentCount    uint32
+	ExpectedPendingCount uint32
+	ExpectedFailedCount  uint32
+}
+
+func (test *GetMessageDeliveryStatusCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.BatchID == ""           { test.BatchID = validBatchID }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *GetMessageDeliveryStatusCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.BatchID == EmptyReplaceValueForString           { test.BatchID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *GetMessageDeliveryStatusCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *GetMessageDeliveryStatusCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetOutboxMessagesWithBatchID":
+		mock.On(string(method), test.BatchID).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *GetMessageDeliveryStatusCase) SetRequestContextOf(req *proto.GetMessageDeliveryStatusRequest) {
+	req.BatchID = test.BatchID
+}
+
+func (test *GetMessageDeliveryStatusCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
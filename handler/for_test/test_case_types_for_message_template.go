@@ -0,0 +1,82 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type ChangeMessageTemplateCase struct {
+	UUID              string
+	Name              string
+	MessageType       string
+	Title             string
+	Body              string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+	ExpectedVersion   uint32
+}
+
+func (test *ChangeMessageTemplateCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.Name == ""              { test.Name = validMessageTemplateName }
+	if test.MessageType == ""       { test.MessageType = "SMS" }
+	if test.Body == ""              { test.Body = validMessageTemplateBody }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ChangeMessageTemplateCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.Name == EmptyReplaceValueForString              { test.Name = "" }
+	if test.MessageType == EmptyReplaceValueForString       { test.MessageType = "" }
+	if test.Body == EmptyReplaceValueForString              { test.Body = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ChangeMessageTemplateCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ChangeMessageTemplateCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetLatestMessageTemplateWithName":
+		mock.On(string(method), test.Name).Return(returns...)
+	case "CreateMessageTemplate":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ChangeMessageTemplateCase) SetRequestContextOf(req *proto.ChangeMessageTemplateRequest) {
+	req.Name = test.Name
+	req.MessageType = test.MessageType
+	req.Title = test.Title
+	req.Body = test.Body
+}
+
+func (test *ChangeMessageTemplateCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/micro/go-micro/v2/metadata"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type LoginParentAuthCase struct {
@@ -44,6 +45,18 @@ func (test *LoginParentAuthCase) onMethod(mock *mock.Mock, method Method, return
 		mock.On(string(method)).Return(returns...)
 	case "GetParentAuthWithID":
 		mock.On(string(method), test.ParentID).Return(returns...)
+	case "GetLoginThrottleWithKey", "DeleteLoginThrottle":
+		mock.On(string(method), "parent:" + test.ParentID).Return(returns...)
+	case "ChangeParentPW":
+		mock.On(string(method), anyArgument, "").Return(returns...)
+	case "IncreaseLoginThrottle", "ModifyLoginThrottleLockedUntil":
+		mock.On(string(method), "parent:" + test.ParentID, anyArgument).Return(returns...)
+	case "CreateSession":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateRefreshToken":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -96,10 +109,18 @@ func (test *ChangeParentPWCase) onMethod(mock *mock.Mock, method Method, returns
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetParentAuthWithUUID":
 		mock.On(string(method), test.ParentUUID).Return(returns...)
 	case "ChangeParentPW":
 		mock.On(string(method), test.ParentUUID, "").Return(returns...)
+	case "GetRecentPasswordHistoriesWithOwnerUUID", "DeletePasswordHistoriesExceptRecent":
+		mock.On(string(method), test.ParentUUID, anyArgument).Return(returns...)
+	case "CreatePasswordHistory":
+		mock.On(string(method), &model.PasswordHistory{OwnerUUID: model.OwnerUUID(test.ParentUUID)}).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -108,7 +129,6 @@ func (test *ChangeParentPWCase) onMethod(mock *mock.Mock, method Method, returns
 }
 
 func (test *ChangeParentPWCase) SetRequestContextOf(req *proto.ChangeParentPWRequest) {
-	req.UUID = test.UUID
 	req.ParentUUID = test.ParentUUID
 	req.CurrentPW = test.CurrentPW
 	req.RevisionPW = test.RevisionPW
@@ -119,6 +139,7 @@ func (test *ChangeParentPWCase) GetMetadataContext() (ctx context.Context) {
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -154,6 +175,8 @@ func (test *GetParentInformWithUUIDCase) onMethod(mock *mock.Mock, method Method
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetParentInformWithUUID":
 		mock.On(string(method), test.ParentUUID).Return(returns...)
 	case "Commit":
@@ -164,7 +187,6 @@ func (test *GetParentInformWithUUIDCase) onMethod(mock *mock.Mock, method Method
 }
 
 func (test *GetParentInformWithUUIDCase) SetRequestContextOf(req *proto.GetParentInformWithUUIDRequest) {
-	req.UUID = test.UUID
 	req.ParentUUID = test.ParentUUID
 }
 
@@ -173,6 +195,7 @@ func (test *GetParentInformWithUUIDCase) GetMetadataContext() (ctx context.Conte
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -209,6 +232,8 @@ func (test *GetParentUUIDsWithInformCase) onMethod(mock *mock.Mock, method Metho
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetParentUUIDsWithInform":
 		mock.On(string(method), &model.ParentInform{
 			Name:          model.Name(test.Name),
@@ -222,7 +247,6 @@ func (test *GetParentUUIDsWithInformCase) onMethod(mock *mock.Mock, method Metho
 }
 
 func (test *GetParentUUIDsWithInformCase) SetRequestContextOf(req *proto.GetParentUUIDsWithInformRequest) {
-	req.UUID = test.UUID
 	req.Name = test.Name
 	req.PhoneNumber = test.PhoneNumber
 }
@@ -232,6 +256,7 @@ func (test *GetParentUUIDsWithInformCase) GetMetadataContext() (ctx context.Cont
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -0,0 +1,88 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+)
+
+type ConfirmPasswordResetCase struct {
+	AccountType       string
+	AccountID         string
+	Code              string
+	NewPW             string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *ConfirmPasswordResetCase) ChangeEmptyValueToValidValue() {
+	if test.AccountType == ""       { test.AccountType = "student" }
+	if test.AccountID == ""         { test.AccountID = validStudentID }
+	if test.Code == ""              { test.Code = validPasswordResetCode }
+	if test.NewPW == ""             { test.NewPW = validRevisionPW }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ConfirmPasswordResetCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.AccountType == EmptyReplaceValueForString       { test.AccountType = "" }
+	if test.AccountID == EmptyReplaceValueForString         { test.AccountID = "" }
+	if test.Code == EmptyReplaceValueForString              { test.Code = "" }
+	if test.NewPW == EmptyReplaceValueForString             { test.NewPW = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ConfirmPasswordResetCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ConfirmPasswordResetCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetStudentAuthWithID", "GetTeacherAuthWithID", "GetParentAuthWithID":
+		mock.On(string(method), test.AccountID).Return(returns...)
+	case "GetPasswordResetWithOwnerUUID", "DeletePasswordReset", "DeleteRefreshTokensWithOwnerUUID", "DeleteSessionsWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "IncreasePasswordResetAttemptCount":
+		mock.On(string(method), anyArgument, anyArgument).Return(returns...)
+	case "GetRecentPasswordHistoriesWithOwnerUUID", "DeletePasswordHistoriesExceptRecent":
+		mock.On(string(method), anyArgument, anyArgument).Return(returns...)
+	case "CreatePasswordHistory":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "ChangeStudentPW", "ChangeTeacherPW", "ChangeParentPW":
+		mock.On(string(method), anyArgument, "").Return(returns...)
+	case "CreateSessionRevocation":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "DeleteLoginThrottle":
+		mock.On(string(method), test.AccountType + ":" + test.AccountID).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ConfirmPasswordResetCase) SetRequestContextOf(req *proto.ConfirmPasswordResetRequest) {
+	req.AccountType = test.AccountType
+	req.AccountID = test.AccountID
+	req.Code = test.Code
+	req.NewPW = test.NewPW
+}
+
+func (test *ConfirmPasswordResetCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+
+	return
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"auth/model"
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type RolloverAcademicYearCase struct {
+	UUID                   string
+	AcademicYear           int32
+	AssignmentFile         string
+	DryRun                 bool
+	XRequestID             string
+	SpanContextString      string
+	ExpectedMethods        map[Method]Returns
+	ExpectedChildRevisions map[string]*model.ParentChildren // revision of parent children expected for each promoted student uuid
+	ExpectedStatus         uint32
+	ExpectedCode           int32
+	ExpectedMessage        string
+	ExpectedPromotedCount  uint32
+	ExpectedGraduatedCount uint32
+	ExpectedConflictCount  int
+}
+
+func (test *RolloverAcademicYearCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.AcademicYear == 0       { test.AcademicYear = validAcademicYear }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *RolloverAcademicYearCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *RolloverAcademicYearCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *RolloverAcademicYearCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx", "GetAllStudentInformsForUpdate":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateGraduatedStudent", "GetStudentAuthWithUUID", "DeleteStudentAuth", "DeleteStudentInform", "DeleteParentChildrenWithStudentUUID",
+		"CreateSessionRevocation", "DeleteRefreshTokensWithOwnerUUID", "DeleteSessionsWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "ModifyStudentInform":
+		mock.On(string(method), anyArgument, anyArgument).Return(returns...)
+	case "ModifyParentChildrenWithStudentUUID":
+		if len(test.ExpectedChildRevisions) == 0 {
+			mock.On(string(method), anyArgument, anyArgument).Return(returns...)
+		}
+		for studentUUID, revision := range test.ExpectedChildRevisions {
+			mock.On(string(method), studentUUID, revision).Return(returns...)
+		}
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *RolloverAcademicYearCase) SetRequestContextOf(req *proto.RolloverAcademicYearRequest) {
+	req.AcademicYear = test.AcademicYear
+	req.AssignmentFile = []byte(test.AssignmentFile)
+	req.DryRun = test.DryRun
+}
+
+func (test *RolloverAcademicYearCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
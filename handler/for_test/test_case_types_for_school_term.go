@@ -0,0 +1,77 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type ChangeSchoolTermCase struct {
+	UUID              string
+	AcademicYear      uint32
+	SignupDeadline    string
+	SchoolName        string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *ChangeSchoolTermCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.AcademicYear == 0       { test.AcademicYear = validAcademicYear }
+	if test.SignupDeadline == ""    { test.SignupDeadline = validSignupDeadline }
+	if test.SchoolName == ""        { test.SchoolName = validSchoolName }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ChangeSchoolTermCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.AcademicYear == EmptyReplaceValueForUint32      { test.AcademicYear = 0 }
+	if test.SignupDeadline == EmptyReplaceValueForString    { test.SignupDeadline = "" }
+	if test.SchoolName == EmptyReplaceValueForString        { test.SchoolName = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ChangeSchoolTermCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ChangeSchoolTermCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateSchoolTerm":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ChangeSchoolTermCase) SetRequestContextOf(req *proto.ChangeSchoolTermRequest) {
+	req.AcademicYear = test.AcademicYear
+	req.SignupDeadline = test.SignupDeadline
+	req.SchoolName = test.SchoolName
+}
+
+func (test *ChangeSchoolTermCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type ListMySessionsCase struct {
+	UUID, TargetUUID         string
+	SessionID                string // session id in access token of caller
+	XRequestID               string
+	SpanContextString        string
+	ExpectedMethods          map[Method]Returns
+	ExpectedStatus           uint32
+	ExpectedCode             int32
+	ExpectedMessage          string
+	ExpectedSessionCount     int
+	ExpectedCurrentSessionID string
+}
+
+func (test *ListMySessionsCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validStudentUUID() }
+	if test.TargetUUID == ""        { test.TargetUUID = test.UUID }
+	if test.SessionID == ""         { test.SessionID = validSessionID }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ListMySessionsCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.TargetUUID == EmptyReplaceValueForString        { test.TargetUUID = "" }
+	if test.SessionID == EmptyReplaceValueForString         { test.SessionID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ListMySessionsCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ListMySessionsCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetSessionWithSessionID":
+		mock.On(string(method), test.SessionID).Return(returns...)
+	case "GetSessionsWithOwnerUUID":
+		mock.On(string(method), test.TargetUUID).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ListMySessionsCase) SetRequestContextOf(req *proto.ListMySessionsRequest) {
+	req.UUID = test.TargetUUID
+}
+
+func (test *ListMySessionsCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + SessionAccessTokenFor(test.UUID, test.SessionID, time.Now())) }
+
+	return
+}
+
+type RevokeSessionCase struct {
+	UUID, TargetUUID  string
+	SessionID         string // session id in access token of caller
+	TargetSessionID   string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *RevokeSessionCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validParentUUID() }
+	if test.TargetUUID == ""        { test.TargetUUID = test.UUID }
+	if test.SessionID == ""         { test.SessionID = validSessionID }
+	if test.TargetSessionID == ""   { test.TargetSessionID = test.SessionID }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *RevokeSessionCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.TargetUUID == EmptyReplaceValueForString        { test.TargetUUID = "" }
+	if test.SessionID == EmptyReplaceValueForString         { test.SessionID = "" }
+	if test.TargetSessionID == EmptyReplaceValueForString   { test.TargetSessionID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *RevokeSessionCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *RevokeSessionCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetSessionWithSessionID": // called with session id of caller and target session id
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "DeleteSession", "DeleteRefreshTokensWithSessionID":
+		mock.On(string(method), test.TargetSessionID).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *RevokeSessionCase) SetRequestContextOf(req *proto.RevokeSessionRequest) {
+	req.UUID = test.TargetUUID
+	req.SessionID = test.TargetSessionID
+}
+
+func (test *RevokeSessionCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + SessionAccessTokenFor(test.UUID, test.SessionID, time.Now())) }
+
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/micro/go-micro/v2/metadata"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type LoginStudentAuthCase struct {
@@ -46,6 +47,18 @@ func (test *LoginStudentAuthCase) onMethod(mock *mock.Mock, method Method, retur
 		mock.On(string(method)).Return(returns...)
 	case "GetStudentAuthWithID":
 		mock.On(string(method), test.StudentID).Return(returns...)
+	case "GetLoginThrottleWithKey", "DeleteLoginThrottle":
+		mock.On(string(method), "student:" + test.StudentID).Return(returns...)
+	case "ChangeStudentPW":
+		mock.On(string(method), anyArgument, "").Return(returns...)
+	case "IncreaseLoginThrottle", "ModifyLoginThrottleLockedUntil":
+		mock.On(string(method), "student:" + test.StudentID, anyArgument).Return(returns...)
+	case "CreateSession":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateRefreshToken":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -98,10 +111,18 @@ func (test *ChangeStudentPWCase) onMethod(mock *mock.Mock, method Method, return
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetStudentAuthWithUUID": // 추가 구현 필요
 		mock.On(string(method), test.StudentUUID).Return(returns...)
 	case "ChangeStudentPW":
 		mock.On(string(method), test.StudentUUID, "").Return(returns...)
+	case "GetRecentPasswordHistoriesWithOwnerUUID", "DeletePasswordHistoriesExceptRecent":
+		mock.On(string(method), test.StudentUUID, anyArgument).Return(returns...)
+	case "CreatePasswordHistory":
+		mock.On(string(method), &model.PasswordHistory{OwnerUUID: model.OwnerUUID(test.StudentUUID)}).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -110,7 +131,6 @@ func (test *ChangeStudentPWCase) onMethod(mock *mock.Mock, method Method, return
 }
 
 func (test *ChangeStudentPWCase) SetRequestContextOf(req *proto.ChangeStudentPWRequest) {
-	req.UUID = test.UUID
 	req.StudentUUID = test.StudentUUID
 	req.CurrentPW = test.CurrentPW
 	req.RevisionPW = test.RevisionPW
@@ -121,6 +141,7 @@ func (test *ChangeStudentPWCase) GetMetadataContext() (ctx context.Context) {
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -156,6 +177,8 @@ func (test *GetStudentInformWithUUIDCase) onMethod(mock *mock.Mock, method Metho
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetStudentInformWithUUID":
 		mock.On(string(method), test.StudentUUID).Return(returns...)
 	case "Commit":
@@ -166,7 +189,6 @@ func (test *GetStudentInformWithUUIDCase) onMethod(mock *mock.Mock, method Metho
 }
 
 func (test *GetStudentInformWithUUIDCase) SetRequestContextOf(req *proto.GetStudentInformWithUUIDRequest) {
-	req.UUID = test.UUID
 	req.StudentUUID = test.StudentUUID
 }
 
@@ -175,6 +197,7 @@ func (test *GetStudentInformWithUUIDCase) GetMetadataContext() (ctx context.Cont
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -214,6 +237,8 @@ func (test *GetStudentUUIDsWithInformCase) onMethod(mock *mock.Mock, method Meth
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetStudentUUIDsWithInform":
 		mock.On(string(method), &model.StudentInform{
 			Grade:         model.Grade(test.Grade),
@@ -231,7 +256,6 @@ func (test *GetStudentUUIDsWithInformCase) onMethod(mock *mock.Mock, method Meth
 }
 
 func (test *GetStudentUUIDsWithInformCase) SetRequestContextOf(req *proto.GetStudentUUIDsWithInformRequest) {
-	req.UUID = test.UUID
 	req.Grade = uint32(test.Grade)
 	req.Group = uint32(test.Class)
 	req.StudentNumber = uint32(test.StudentNumber)
@@ -245,6 +269,7 @@ func (test *GetStudentUUIDsWithInformCase) GetMetadataContext() (ctx context.Con
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -281,6 +306,8 @@ func (test *GetStudentInformsWithUUIDsCase) onMethod(mock *mock.Mock, method Met
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetStudentInformsWithUUIDs":
 		mock.On(string(method), test.StudentUUIDs).Return(returns...)
 	case "Commit":
@@ -291,7 +318,6 @@ func (test *GetStudentInformsWithUUIDsCase) onMethod(mock *mock.Mock, method Met
 }
 
 func (test *GetStudentInformsWithUUIDsCase) SetRequestContextOf(req *proto.GetStudentInformsWithUUIDsRequest) {
-	req.UUID = test.UUID
 	req.StudentUUIDs = test.StudentUUIDs
 }
 
@@ -300,6 +326,7 @@ func (test *GetStudentInformsWithUUIDsCase) GetMetadataContext() (ctx context.Co
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
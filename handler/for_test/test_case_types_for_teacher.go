@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/micro/go-micro/v2/metadata"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type LoginTeacherAuthCase struct {
@@ -44,6 +45,20 @@ func (test *LoginTeacherAuthCase) onMethod(mock *mock.Mock, method Method, retur
 		mock.On(string(method)).Return(returns...)
 	case "GetTeacherAuthWithID":
 		mock.On(string(method), test.TeacherID).Return(returns...)
+	case "GetLoginThrottleWithKey", "DeleteLoginThrottle":
+		mock.On(string(method), "teacher:" + test.TeacherID).Return(returns...)
+	case "GetTwoFactorAuthWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "ChangeTeacherPW":
+		mock.On(string(method), anyArgument, "").Return(returns...)
+	case "IncreaseLoginThrottle", "ModifyLoginThrottleLockedUntil":
+		mock.On(string(method), "teacher:" + test.TeacherID, anyArgument).Return(returns...)
+	case "CreateSession":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateRefreshToken":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -96,10 +111,18 @@ func (test *ChangeTeacherPWCase) onMethod(mock *mock.Mock, method Method, return
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetTeacherAuthWithUUID":
 		mock.On(string(method), test.TeacherUUID).Return(returns...)
 	case "ChangeTeacherPW":
 		mock.On(string(method), test.TeacherUUID, "").Return(returns...)
+	case "GetRecentPasswordHistoriesWithOwnerUUID", "DeletePasswordHistoriesExceptRecent":
+		mock.On(string(method), test.TeacherUUID, anyArgument).Return(returns...)
+	case "CreatePasswordHistory":
+		mock.On(string(method), &model.PasswordHistory{OwnerUUID: model.OwnerUUID(test.TeacherUUID)}).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "Commit":
 		mock.On(string(method)).Return(returns...)
 	case "Rollback":
@@ -108,7 +131,6 @@ func (test *ChangeTeacherPWCase) onMethod(mock *mock.Mock, method Method, return
 }
 
 func (test *ChangeTeacherPWCase) SetRequestContextOf(req *proto.ChangeTeacherPWRequest) {
-	req.UUID = test.UUID
 	req.TeacherUUID = test.TeacherUUID
 	req.CurrentPW = test.CurrentPW
 	req.RevisionPW = test.RevisionPW
@@ -119,6 +141,7 @@ func (test *ChangeTeacherPWCase) GetMetadataContext() (ctx context.Context) {
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -154,6 +177,8 @@ func (test *GetTeacherInformWithUUIDCase) onMethod(mock *mock.Mock, method Metho
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetTeacherInformWithUUID":
 		mock.On(string(method), test.TeacherUUID).Return(returns...)
 	case "Commit":
@@ -164,7 +189,6 @@ func (test *GetTeacherInformWithUUIDCase) onMethod(mock *mock.Mock, method Metho
 }
 
 func (test *GetTeacherInformWithUUIDCase) SetRequestContextOf(req *proto.GetTeacherInformWithUUIDRequest) {
-	req.UUID = test.UUID
 	req.TeacherUUID = test.TeacherUUID
 }
 
@@ -173,6 +197,7 @@ func (test *GetTeacherInformWithUUIDCase) GetMetadataContext() (ctx context.Cont
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -210,6 +235,8 @@ func (test *GetTeacherUUIDsWithInformCase) onMethod(mock *mock.Mock, method Meth
 	switch method {
 	case "BeginTx":
 		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
 	case "GetTeacherUUIDsWithInform":
 		mock.On(string(method), &model.TeacherInform{
 			Grade:         model.Grade(test.Grade),
@@ -225,7 +252,6 @@ func (test *GetTeacherUUIDsWithInformCase) onMethod(mock *mock.Mock, method Meth
 }
 
 func (test *GetTeacherUUIDsWithInformCase) SetRequestContextOf(req *proto.GetTeacherUUIDsWithInformRequest) {
-	req.UUID = test.UUID
 	req.Grade = uint32(test.Grade)
 	req.Group = uint32(test.Class)
 	req.Name = test.Name
@@ -237,6 +263,7 @@ func (test *GetTeacherUUIDsWithInformCase) GetMetadataContext() (ctx context.Con
 
 	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
 	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
 
 	return
 }
@@ -0,0 +1,194 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"auth/tool/hash"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type RefreshTokenCase struct {
+	RefreshToken      string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *RefreshTokenCase) ChangeEmptyValueToValidValue() {
+	if test.RefreshToken == ""      { test.RefreshToken = validRefreshToken }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *RefreshTokenCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.RefreshToken == EmptyReplaceValueForString      { test.RefreshToken = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *RefreshTokenCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *RefreshTokenCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRefreshTokenWithHash", "GetDeletedRefreshTokenWithHash", "DeleteRefreshToken":
+		mock.On(string(method), hash.SHA256ToHex(test.RefreshToken)).Return(returns...)
+	case "DeleteSession", "DeleteRefreshTokensWithSessionID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "ModifySessionLastSeenAt":
+		mock.On(string(method), anyArgument, anyArgument).Return(returns...)
+	case "CreateRefreshToken":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *RefreshTokenCase) SetRequestContextOf(req *proto.RefreshTokenRequest) {
+	req.RefreshToken = test.RefreshToken
+}
+
+func (test *RefreshTokenCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+
+	return
+}
+
+type IntrospectTokenCase struct {
+	AccessToken       string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+	ExpectedUUID      string
+}
+
+func (test *IntrospectTokenCase) ChangeEmptyValueToValidValue() {
+	if test.AccessToken == ""       { test.AccessToken = AccessTokenFor(validStudentUUID(), "student", time.Now()) }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *IntrospectTokenCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.AccessToken == EmptyReplaceValueForString       { test.AccessToken = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *IntrospectTokenCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *IntrospectTokenCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetSessionWithSessionID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *IntrospectTokenCase) SetRequestContextOf(req *proto.IntrospectTokenRequest) {
+	req.AccessToken = test.AccessToken
+}
+
+func (test *IntrospectTokenCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+
+	return
+}
+
+type RevokeAllSessionsCase struct {
+	AccessToken       string
+	TargetUUID        string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *RevokeAllSessionsCase) ChangeEmptyValueToValidValue() {
+	if test.AccessToken == ""       { test.AccessToken = AccessTokenFor(validAdminUUID, "admin", time.Now()) }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *RevokeAllSessionsCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.AccessToken == EmptyReplaceValueForString       { test.AccessToken = "" }
+	if test.TargetUUID == EmptyReplaceValueForString        { test.TargetUUID = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *RevokeAllSessionsCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *RevokeAllSessionsCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateSessionRevocation":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "DeleteRefreshTokensWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "DeleteSessionsWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *RevokeAllSessionsCase) SetRequestContextOf(req *proto.RevokeAllSessionsRequest) {
+	req.AccessToken = test.AccessToken
+	req.TargetUUID = test.TargetUUID
+}
+
+func (test *RevokeAllSessionsCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+
+	return
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type VerifyTOTPCase struct {
+	UUID, TargetUUID  string
+	Code              string
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *VerifyTOTPCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validTeacherUUID() }
+	if test.TargetUUID == ""        { test.TargetUUID = test.UUID }
+	if test.Code == ""              { test.Code = validTOTPCode }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *VerifyTOTPCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.TargetUUID == EmptyReplaceValueForString        { test.TargetUUID = "" }
+	if test.Code == EmptyReplaceValueForString              { test.Code = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *VerifyTOTPCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *VerifyTOTPCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetLoginThrottleWithKey", "DeleteLoginThrottle":
+		mock.On(string(method), "totp:" + test.TargetUUID).Return(returns...)
+	case "IncreaseLoginThrottle", "ModifyLoginThrottleLockedUntil":
+		mock.On(string(method), "totp:" + test.TargetUUID, anyArgument).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetTwoFactorAuthWithOwnerUUID":
+		mock.On(string(method), test.TargetUUID).Return(returns...)
+	case "ModifyTwoFactorAuth":
+		mock.On(string(method), test.TargetUUID, anyArgument, anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *VerifyTOTPCase) SetRequestContextOf(req *proto.VerifyTOTPRequest) {
+	req.UUID = test.TargetUUID
+	req.Code = test.Code
+}
+
+func (test *VerifyTOTPCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
+
+type LoginWithTOTPCase struct {
+	UUID                 string
+	TOTPToken            string
+	Code                 string
+	XRequestID           string
+	SpanContextString    string
+	ExpectedMethods      map[Method]Returns
+	ExpectedStatus       uint32
+	ExpectedCode         int32
+	ExpectedMessage      string
+	ExpectedLoggedInUUID string
+}
+
+func (test *LoginWithTOTPCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.TOTPToken == ""         { test.TOTPToken = TOTPTokenFor(test.UUID, time.Now()) }
+	if test.Code == ""              { test.Code = validTOTPCode }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *LoginWithTOTPCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.TOTPToken == EmptyReplaceValueForString         { test.TOTPToken = "" }
+	if test.Code == EmptyReplaceValueForString              { test.Code = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *LoginWithTOTPCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *LoginWithTOTPCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetLoginThrottleWithKey", "DeleteLoginThrottle":
+		mock.On(string(method), "totp:" + test.UUID).Return(returns...)
+	case "IncreaseLoginThrottle", "ModifyLoginThrottleLockedUntil":
+		mock.On(string(method), "totp:" + test.UUID, anyArgument).Return(returns...)
+	case "GetTwoFactorAuthWithOwnerUUID", "DeleteRecoveryCodesWithOwnerUUID":
+		mock.On(string(method), test.UUID).Return(returns...)
+	case "ModifyTwoFactorAuth":
+		mock.On(string(method), test.UUID, anyArgument, anyArgument).Return(returns...)
+	case "DeleteRecoveryCode":
+		mock.On(string(method), test.UUID, anyArgument).Return(returns...)
+	case "CreateSession":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateRefreshToken":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateAuthLog":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *LoginWithTOTPCase) SetRequestContextOf(req *proto.LoginWithTOTPRequest) {
+	req.TOTPToken = test.TOTPToken
+	req.Code = test.Code
+}
+
+func (test *LoginWithTOTPCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+
+	return
+}
@@ -0,0 +1,283 @@
+package test
+
+import (
+	proto "auth/proto/golang/auth"
+	"context"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type RegenerateUnsignedStudentAuthCodesCase struct {
+	UUID                     string
+	TargetGrade              uint32
+	TargetGroup              uint32
+	TargetNumber             uint32
+	ExpireDate               string
+	XRequestID               string
+	SpanContextString        string
+	ExpectedMethods          map[Method]Returns
+	ExpectedStatus           uint32
+	ExpectedCode             int32
+	ExpectedMessage          string
+	ExpectedRegeneratedCount uint32
+}
+
+func (test *RegenerateUnsignedStudentAuthCodesCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.TargetGrade == 0        { test.TargetGrade = validGrade }
+	if test.TargetGroup == 0        { test.TargetGroup = validClass }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *RegenerateUnsignedStudentAuthCodesCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.TargetGrade == EmptyReplaceValueForUint32       { test.TargetGrade = 0 }
+	if test.TargetGroup == EmptyReplaceValueForUint32       { test.TargetGroup = 0 }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *RegenerateUnsignedStudentAuthCodesCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *RegenerateUnsignedStudentAuthCodesCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetCurrentSchoolTerm":
+		mock.On(string(method)).Return(returns...)
+	case "GetUnsignedStudents":
+		mock.On(string(method), int64(test.TargetGrade), int64(test.TargetGroup)).Return(returns...)
+	case "ChangeUnsignedStudentAuthCode":
+		mock.On(string(method), anyArgument, anyArgument, anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *RegenerateUnsignedStudentAuthCodesCase) SetRequestContextOf(req *proto.RegenerateUnsignedStudentAuthCodesRequest) {
+	req.TargetGrade = test.TargetGrade
+	req.TargetGroup = test.TargetGroup
+	req.TargetNumber = test.TargetNumber
+	req.ExpireDate = test.ExpireDate
+}
+
+func (test *RegenerateUnsignedStudentAuthCodesCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
+
+type ChangeUnsignedStudentAuthCodeExpiryCase struct {
+	UUID                 string
+	TargetGrade          uint32
+	TargetGroup          uint32
+	TargetNumber         uint32
+	ExpireDate           string
+	XRequestID           string
+	SpanContextString    string
+	ExpectedMethods      map[Method]Returns
+	ExpectedStatus       uint32
+	ExpectedCode         int32
+	ExpectedMessage      string
+	ExpectedChangedCount uint32
+}
+
+func (test *ChangeUnsignedStudentAuthCodeExpiryCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.TargetGrade == 0        { test.TargetGrade = validGrade }
+	if test.TargetGroup == 0        { test.TargetGroup = validClass }
+	if test.ExpireDate == ""        { test.ExpireDate = validAuthCodeExpireDate }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ChangeUnsignedStudentAuthCodeExpiryCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.TargetGrade == EmptyReplaceValueForUint32       { test.TargetGrade = 0 }
+	if test.TargetGroup == EmptyReplaceValueForUint32       { test.TargetGroup = 0 }
+	if test.ExpireDate == EmptyReplaceValueForString        { test.ExpireDate = "" }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ChangeUnsignedStudentAuthCodeExpiryCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ChangeUnsignedStudentAuthCodeExpiryCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetUnsignedStudents":
+		mock.On(string(method), int64(test.TargetGrade), int64(test.TargetGroup)).Return(returns...)
+	case "ChangeUnsignedStudentAuthCodeExpiry":
+		mock.On(string(method), anyArgument, anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ChangeUnsignedStudentAuthCodeExpiryCase) SetRequestContextOf(req *proto.ChangeUnsignedStudentAuthCodeExpiryRequest) {
+	req.TargetGrade = test.TargetGrade
+	req.TargetGroup = test.TargetGroup
+	req.TargetNumber = test.TargetNumber
+	req.ExpireDate = test.ExpireDate
+}
+
+func (test *ChangeUnsignedStudentAuthCodeExpiryCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
+
+type ResendJoinSMSToUnsignedStudentCase struct {
+	UUID              string
+	Grade             uint32
+	Group             uint32
+	StudentNumber     uint32
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *ResendJoinSMSToUnsignedStudentCase) ChangeEmptyValueToValidValue() {
+	if test.UUID == ""              { test.UUID = validAdminUUID }
+	if test.Grade == 0              { test.Grade = validGrade }
+	if test.Group == 0              { test.Group = validClass }
+	if test.StudentNumber == 0      { test.StudentNumber = validStudentNumber }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *ResendJoinSMSToUnsignedStudentCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.UUID == EmptyReplaceValueForString              { test.UUID = "" }
+	if test.Grade == EmptyReplaceValueForUint32             { test.Grade = 0 }
+	if test.Group == EmptyReplaceValueForUint32             { test.Group = 0 }
+	if test.StudentNumber == EmptyReplaceValueForUint32     { test.StudentNumber = 0 }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *ResendJoinSMSToUnsignedStudentCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *ResendJoinSMSToUnsignedStudentCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetRevokedTokenWithTokenID", "GetLastSessionRevocationWithOwnerUUID":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "GetCurrentSchoolTerm":
+		mock.On(string(method)).Return(returns...)
+	case "GetUnsignedStudents":
+		mock.On(string(method), int64(test.Grade), int64(test.Group)).Return(returns...)
+	case "GetLatestMessageTemplateWithName":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "CreateOutboxMessage":
+		mock.On(string(method), anyArgument).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *ResendJoinSMSToUnsignedStudentCase) SetRequestContextOf(req *proto.ResendJoinSMSToUnsignedStudentRequest) {
+	req.Grade = test.Grade
+	req.Group = test.Group
+	req.StudentNumber = test.StudentNumber
+}
+
+func (test *ResendJoinSMSToUnsignedStudentCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+	if test.UUID != "" { ctx = metadata.Set(ctx, "Authorization", "Bearer " + AccessTokenFor(test.UUID, roleOf(test.UUID), time.Now())) }
+
+	return
+}
+
+type GetUnsignedStudentWithAuthCodeCase struct {
+	AuthCode          uint32
+	XRequestID        string
+	SpanContextString string
+	ExpectedMethods   map[Method]Returns
+	ExpectedStatus    uint32
+	ExpectedCode      int32
+	ExpectedMessage   string
+}
+
+func (test *GetUnsignedStudentWithAuthCodeCase) ChangeEmptyValueToValidValue() {
+	if test.AuthCode == 0           { test.AuthCode = validAuthCode }
+	if test.SpanContextString == "" { test.SpanContextString = validSpanContextString }
+	if test.XRequestID == ""        { test.XRequestID = validXRequestID }
+}
+
+func (test *GetUnsignedStudentWithAuthCodeCase) ChangeEmptyReplaceValueToEmptyValue() {
+	if test.AuthCode == EmptyReplaceValueForUint32          { test.AuthCode = 0 }
+	if test.SpanContextString == EmptyReplaceValueForString { test.SpanContextString = "" }
+	if test.XRequestID == EmptyReplaceValueForString        { test.XRequestID = "" }
+}
+
+func (test *GetUnsignedStudentWithAuthCodeCase) OnExpectMethods(mock *mock.Mock) {
+	for method, returns := range test.ExpectedMethods {
+		test.onMethod(mock, method, returns)
+	}
+}
+
+func (test *GetUnsignedStudentWithAuthCodeCase) onMethod(mock *mock.Mock, method Method, returns Returns) {
+	switch method {
+	case "BeginTx":
+		mock.On(string(method)).Return(returns...)
+	case "GetUnsignedStudentWithAuthCode":
+		mock.On(string(method), int64(test.AuthCode)).Return(returns...)
+	case "Commit":
+		mock.On(string(method)).Return(returns...)
+	case "Rollback":
+		mock.On(string(method)).Return(returns...)
+	}
+}
+
+func (test *GetUnsignedStudentWithAuthCodeCase) SetRequestContextOf(req *proto.GetUnsignedStudentWithAuthCodeRequest) {
+	req.AuthCode = test.AuthCode
+}
+
+func (test *GetUnsignedStudentWithAuthCodeCase) GetMetadataContext() (ctx context.Context) {
+	ctx = context.Background()
+
+	ctx = metadata.Set(ctx, "X-Request-Id", test.XRequestID)
+	ctx = metadata.Set(ctx, "Span-Context", test.SpanContextString)
+
+	return
+}
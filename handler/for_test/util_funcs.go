@@ -1,8 +1,12 @@
 package test
 
 import (
+	"auth/tool/jwt"
 	"auth/tool/random"
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -14,4 +18,47 @@ func createGormModelOnCurrentTime() gorm.Model {
 		UpdatedAt: currentTime,
 		DeletedAt: nil,
 	}
-}
\ No newline at end of file
+}
+
+// function that generate access token signed with key in JWT_SECRET_KEY env for test case (add in v.1.2.0)
+func AccessTokenFor(ownerUUID, role string, issuedAt time.Time) (token string) {
+	token, _ = jwt.GenerateWithHS256(jwt.Claims{
+		ID:        uuid.New().String(),
+		Subject:   ownerUUID,
+		Role:      role,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(time.Minute * 30).Unix(),
+	}, []byte(os.Getenv("JWT_SECRET_KEY")))
+	return
+}
+
+// function that return role of uuid that is used for claim of access token in test case (add in v.1.2.0)
+func roleOf(uuid string) string {
+	return strings.SplitN(uuid, "-", 2)[0]
+}
+
+// function that generate token for second step of login (LoginWithTOTP) for test case (add in v.1.2.0)
+func TOTPTokenFor(ownerUUID string, issuedAt time.Time) (token string) {
+	token, _ = jwt.GenerateWithHS256(jwt.Claims{
+		ID:        uuid.New().String(),
+		Subject:   ownerUUID,
+		Role:      roleOf(ownerUUID),
+		Purpose:   "totp",
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(time.Minute * 5).Unix(),
+	}, []byte(os.Getenv("JWT_SECRET_KEY")))
+	return
+}
+
+// function that generate access token issued in login session for test case (add in v.1.2.0)
+func SessionAccessTokenFor(ownerUUID, sessionID string, issuedAt time.Time) (token string) {
+	token, _ = jwt.GenerateWithHS256(jwt.Claims{
+		ID:        uuid.New().String(),
+		Subject:   ownerUUID,
+		Role:      roleOf(ownerUUID),
+		SessionID: sessionID,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(time.Minute * 30).Unix(),
+	}, []byte(os.Getenv("JWT_SECRET_KEY")))
+	return
+}
@@ -16,19 +16,33 @@ const (
 	validAdminUUID = "admin-111111111111"
 
 	validAdminID = "adminID"
-	validAdminPW = ""
+	validAdminPW = "adminPW1234"
 	validStudentID = "jinhong0719"
-	validStudentPW = ""
+	validStudentPW = "studentPW1234"
 	validTeacherID = "teacherID"
-	validTeacherPW = ""
+	validTeacherPW = "teacherPW1234"
 	validParentID = "parentID"
-	validParentPW = ""
+	validParentPW = "parentPW1234"
 
 	validGrade = 2
 	validClass = 2
 	validStudentNumber = 7
 	validName = "박진홍"
 	validPhoneNumber = "01088378347"
+
+	validRefreshToken = "0Wc1WIpp-ZNkHl5Hw4xXcLmSPP2Sy4u0S3tYq6Xkr1s"
+	validPasswordResetCode = "123456"
+	validRevisionPW = "newPassword"
+	validTOTPCode = "123456"
+	validSessionID = "3f1d2c4b-5a6e-4f70-8b9c-1d2e3f4a5b6c"
+	validAcademicYear = 2022
+	validSignupDeadline = "2022-03-11"
+	validSchoolName = "대덕소프트웨어마이스터고등학교"
+	validMessageTemplateName = "password_reset_sms"
+	validMessageTemplateBody = "[DSM] 인증 번호는 [{{.ResetCode}}] 입니다. {{.ExpirationMinutes}}분 안에 입력해주세요."
+	validBatchID = "9b2e6c1a-3f4d-4e5b-8a7c-0d1e2f3a4b5c"
+	validAuthCode = 123456
+	validAuthCodeExpireDate = "2099-12-31"
 )
 
 var (
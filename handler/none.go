@@ -12,6 +12,25 @@ func(n None) CreateNewStudent(context.Context, *proto.CreateNewStudentRequest, *
 func(n None) CreateNewTeacher(context.Context, *proto.CreateNewTeacherRequest, *proto.CreateNewTeacherResponse) (err error) { return }
 func(n None) CreateNewParent(context.Context, *proto.CreateNewParentRequest, *proto.CreateNewParentResponse) (err error) { return }
 func(n None) LoginAdminAuth(context.Context, *proto.LoginAdminAuthRequest, *proto.LoginAdminAuthResponse) (err error) { return }
+func(n None) UnlockAccount(context.Context, *proto.UnlockAccountRequest, *proto.UnlockAccountResponse) (err error) { return }
+func(n None) GetAuthLogs(context.Context, *proto.GetAuthLogsRequest, *proto.GetAuthLogsResponse) (err error) { return }
+func(n None) ListAuditEvents(context.Context, *proto.ListAuditEventsRequest, *proto.ListAuditEventsResponse) (err error) { return }
+func(n None) DeleteStudent(context.Context, *proto.DeleteStudentRequest, *proto.DeleteStudentResponse) (err error) { return }
+func(n None) DeleteTeacher(context.Context, *proto.DeleteTeacherRequest, *proto.DeleteTeacherResponse) (err error) { return }
+func(n None) DeleteParent(context.Context, *proto.DeleteParentRequest, *proto.DeleteParentResponse) (err error) { return }
+func(n None) RestoreStudent(context.Context, *proto.RestoreStudentRequest, *proto.RestoreStudentResponse) (err error) { return }
+func(n None) RestoreTeacher(context.Context, *proto.RestoreTeacherRequest, *proto.RestoreTeacherResponse) (err error) { return }
+func(n None) RestoreParent(context.Context, *proto.RestoreParentRequest, *proto.RestoreParentResponse) (err error) { return }
+func(n None) RolloverAcademicYear(context.Context, *proto.RolloverAcademicYearRequest, *proto.RolloverAcademicYearResponse) (err error) { return }
+func(n None) GetSchoolTerm(context.Context, *proto.GetSchoolTermRequest, *proto.GetSchoolTermResponse) (err error) { return }
+func(n None) ChangeSchoolTerm(context.Context, *proto.ChangeSchoolTermRequest, *proto.ChangeSchoolTermResponse) (err error) { return }
+func(n None) GetMessageTemplate(context.Context, *proto.GetMessageTemplateRequest, *proto.GetMessageTemplateResponse) (err error) { return }
+func(n None) ChangeMessageTemplate(context.Context, *proto.ChangeMessageTemplateRequest, *proto.ChangeMessageTemplateResponse) (err error) { return }
+func(n None) PreviewMessageTemplate(context.Context, *proto.PreviewMessageTemplateRequest, *proto.PreviewMessageTemplateResponse) (err error) { return }
+func(n None) RegenerateUnsignedStudentAuthCodes(context.Context, *proto.RegenerateUnsignedStudentAuthCodesRequest, *proto.RegenerateUnsignedStudentAuthCodesResponse) (err error) { return }
+func(n None) ChangeUnsignedStudentAuthCodeExpiry(context.Context, *proto.ChangeUnsignedStudentAuthCodeExpiryRequest, *proto.ChangeUnsignedStudentAuthCodeExpiryResponse) (err error) { return }
+func(n None) ResendJoinSMSToUnsignedStudent(context.Context, *proto.ResendJoinSMSToUnsignedStudentRequest, *proto.ResendJoinSMSToUnsignedStudentResponse) (err error) { return }
+func(n None) GetMessageDeliveryStatus(context.Context, *proto.GetMessageDeliveryStatusRequest, *proto.GetMessageDeliveryStatusResponse) (err error) { return }
 
 // About Student RPC Service
 func(n None) LoginStudentAuth(context.Context, *proto.LoginStudentAuthRequest, *proto.LoginStudentAuthResponse) (err error) { return }
@@ -31,3 +50,23 @@ func(n None) LoginParentAuth(context.Context, *proto.LoginParentAuthRequest, *pr
 func(n None) ChangeParentPW(context.Context, *proto.ChangeParentPWRequest, *proto.ChangeParentPWResponse) (err error) { return }
 func(n None) GetParentInformWithUUID(context.Context, *proto.GetParentInformWithUUIDRequest, *proto.GetParentInformWithUUIDResponse) (err error) { return }
 func(n None) GetParentUUIDsWithInform(context.Context, *proto.GetParentUUIDsWithInformRequest, *proto.GetParentUUIDsWithInformResponse) (err error) { return }
+
+// About Token RPC Service
+func(n None) RefreshToken(context.Context, *proto.RefreshTokenRequest, *proto.RefreshTokenResponse) (err error) { return }
+func(n None) IntrospectToken(context.Context, *proto.IntrospectTokenRequest, *proto.IntrospectTokenResponse) (err error) { return }
+func(n None) RevokeToken(context.Context, *proto.RevokeTokenRequest, *proto.RevokeTokenResponse) (err error) { return }
+func(n None) RevokeAllSessions(context.Context, *proto.RevokeAllSessionsRequest, *proto.RevokeAllSessionsResponse) (err error) { return }
+
+// About Password RPC Service
+func(n None) RequestPasswordReset(context.Context, *proto.RequestPasswordResetRequest, *proto.RequestPasswordResetResponse) (err error) { return }
+func(n None) ConfirmPasswordReset(context.Context, *proto.ConfirmPasswordResetRequest, *proto.ConfirmPasswordResetResponse) (err error) { return }
+
+// About TOTP RPC Service
+func(n None) EnrollTOTP(context.Context, *proto.EnrollTOTPRequest, *proto.EnrollTOTPResponse) (err error) { return }
+func(n None) VerifyTOTP(context.Context, *proto.VerifyTOTPRequest, *proto.VerifyTOTPResponse) (err error) { return }
+func(n None) DisableTOTP(context.Context, *proto.DisableTOTPRequest, *proto.DisableTOTPResponse) (err error) { return }
+func(n None) LoginWithTOTP(context.Context, *proto.LoginWithTOTPRequest, *proto.LoginWithTOTPResponse) (err error) { return }
+
+// About Session RPC Service
+func(n None) ListMySessions(context.Context, *proto.ListMySessionsRequest, *proto.ListMySessionsResponse) (err error) { return }
+func(n None) RevokeSession(context.Context, *proto.RevokeSessionRequest, *proto.RevokeSessionResponse) (err error) { return }
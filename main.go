@@ -9,6 +9,7 @@ import (
 	proto "auth/proto/golang/auth"
 	"auth/subscriber"
 	"auth/tool/closure"
+	"auth/tool/message"
 	"auth/tool/network"
 	topic "auth/utils/topic/golang"
 	"fmt"
@@ -26,6 +27,7 @@ import (
 	"github.com/uber/jaeger-client-go"
 	jaegercfg "github.com/uber/jaeger-client-go/config"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -34,7 +36,7 @@ func main() {
 	port := network.GetRandomPortNotInUsedWithRange(10000, 10100) // change from function to method (in v.1.1.6)
 	service := micro.NewService(
 		micro.Name(topic.AuthServiceName),
-		micro.Version("1.1.6"),
+		micro.Version("1.2.0"),
 		micro.Transport(grpc.NewTransport()),
 		micro.Address(fmt.Sprintf(":%d", port)),
 	)
@@ -59,11 +61,24 @@ func main() {
 	)
 
 	// create db access manager
-	dbc, _, err := db.ConnectWithConsul(consulCli, "db/auth/local")
+	dbc, dbConf, err := db.ConnectWithConsul(consulCli, "db/auth/local")
 	if err != nil {
 		log.Fatalf("db connect fail, err: %v", err)
 	}
-	db.Migrate(dbc)
+	log.Infof("db connected with config (%s)", dbConf) // add in v.1.2.0
+
+	// run migrate command instead of service if binary is executed with migrate argument (add in v.1.2.0)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = runMigrateCommand(dbc, os.Args[2:])
+		_ = dbc.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err = db.Migrate(dbc); err != nil {
+		log.Fatalf("db migrate fail, err: %v", err)
+	}
 	accessManage, err := db.NewAccessorManage(access.Default(dbc))
 	if err != nil {
 		log.Fatalf("db accessor create fail, err: %v", err)
@@ -108,14 +123,34 @@ func main() {
 		log.Fatalf("error while creating new aws session, err: %v", err)
 	}
 
+	// create message sender, fake sender writing messages to file is used if SMS_FAKE_SENDER_FILE is set (add in v.1.2.0)
+	var messageSender message.Sender
+	if fakeSenderFile := os.Getenv("SMS_FAKE_SENDER_FILE"); fakeSenderFile != "" {
+		messageSender = message.NewFileFake(fakeSenderFile)
+		log.Printf("messages are not sent but written to %s", fakeSenderFile)
+	} else if messageSender, err = message.AligoFromEnv(); err != nil {
+		log.Fatal(err)
+	}
+
 	// create gRPC handler
 	defaultHandler := handler.Default(
 		handler.Manager(accessManage),
 		handler.Tracer(authSrvTracer),
 		handler.AWSSession(awsSession),
 		handler.ConsulAgent(consulAgent),
+		handler.MessageSender(messageSender),
 	)
 
+	// get retention window of deleted accounts (add in v.1.2.0)
+	accountRetention := time.Hour * 24 * 30
+	if retentionDays := os.Getenv("DELETED_ACCOUNT_RETENTION_DAYS"); retentionDays != "" {
+		days, err := strconv.Atoi(retentionDays)
+		if err != nil || days <= 0 {
+			log.Fatalf("DELETED_ACCOUNT_RETENTION_DAYS must be positive integer, value: %s", retentionDays)
+		}
+		accountRetention = time.Hour * 24 * time.Duration(days)
+	}
+
 	// create subscriber & register listener (add in v.1.1.6)
 	consulChangeQueue := os.Getenv("CHANGE_CONSUL_SQS_AUTH")
 	if consulChangeQueue == "" {
@@ -138,6 +173,8 @@ func main() {
 		micro.BeforeStart(consulAgent.ChangeAllServiceNodes),
 		micro.AfterStart(consulAgent.ChangeAllServiceNodes),
 		micro.AfterStart(defaultSubscriber.StartListening),
+		micro.AfterStart(defaultHandler.AccountPurger(accountRetention, time.Hour)), // add in v.1.2.0
+		micro.AfterStart(defaultHandler.OutboxWorker(time.Second * 10, 100)),         // add in v.1.2.0
 		micro.AfterStart(consulAgent.ServiceNodeRegistry(service.Server())),
 		micro.BeforeStop(consulAgent.ServiceNodeDeregistry(service.Server())),
 	)
@@ -148,6 +185,10 @@ func main() {
 	_ = proto.RegisterAuthTeacherHandler(service.Server(), defaultHandler)
 	_ = proto.RegisterAuthParentHandler(service.Server(), defaultHandler)
 	_ = proto.RegisterAuthEventHandler(service.Server(), defaultHandler)
+	_ = proto.RegisterAuthTokenHandler(service.Server(), defaultHandler) // add in v.1.2.0
+	_ = proto.RegisterAuthPasswordHandler(service.Server(), defaultHandler) // add in v.1.2.0
+	_ = proto.RegisterAuthTOTPHandler(service.Server(), defaultHandler) // add in v.1.2.0
+	_ = proto.RegisterAuthSessionHandler(service.Server(), defaultHandler) // add in v.1.2.0
 
 	// run DB Health checker
 	h := health.New()
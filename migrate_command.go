@@ -0,0 +1,67 @@
+// add file in v.1.2.0
+// this file declare migrate command executed instead of service when binary is run with migrate argument
+// usage: auth-service migrate up [steps] | down <steps> | status
+
+package main
+
+import (
+	"auth/db"
+	"errors"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateCommandUsage = "usage: auth-service migrate up [steps] | down <steps> | status"
+
+func runMigrateCommand(dbc *gorm.DB, args []string) (err error) {
+	if len(args) == 0 {
+		err = errors.New(migrateCommandUsage)
+		return
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+			err = fmt.Errorf("steps must be positive integer, value: %s\n%s", args[1], migrateCommandUsage)
+			return
+		}
+	}
+
+	switch args[0] {
+	case "up":
+		err = db.MigrateUp(dbc, steps)
+	case "down":
+		if steps == 0 {
+			err = errors.New(migrateCommandUsage)
+			return
+		}
+		err = db.MigrateDown(dbc, steps)
+	case "status":
+	default:
+		err = errors.New(migrateCommandUsage)
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	statuses, err := db.MigrationStatuses(dbc)
+	if err != nil {
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	err = w.Flush()
+	return
+}
@@ -11,4 +11,20 @@ var (
 	ParentAuthInstance = new(ParentAuth)
 	ParentInformInstance = new(ParentInform)
 	ParentChildrenInstance = new(ParentChildren)
+
+	RefreshTokenInstance = new(RefreshToken)
+	RevokedTokenInstance = new(RevokedToken)
+	SessionRevocationInstance = new(SessionRevocation)
+	SessionInstance = new(Session)
+	AuthLogInstance = new(AuthLog)
+	AuditEventInstance = new(AuditEvent)
+	GraduatedStudentInstance = new(GraduatedStudent)
+	SchoolTermInstance = new(SchoolTerm)
+	MessageTemplateInstance = new(MessageTemplate)
+	OutboxMessageInstance = new(OutboxMessage)
+	LoginThrottleInstance = new(LoginThrottle)
+	PasswordResetInstance = new(PasswordReset)
+	PasswordHistoryInstance = new(PasswordHistory)
+	TwoFactorAuthInstance = new(TwoFactorAuth)
+	RecoveryCodeInstance = new(RecoveryCode)
 )
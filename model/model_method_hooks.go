@@ -2,9 +2,8 @@ package model
 
 import (
 	"auth/model/validate"
-	"auth/tool/mysqlerr"
-	"fmt"
 	"github.com/jinzhu/gorm"
+	"time"
 )
 
 const (
@@ -23,66 +22,19 @@ const (
 )
 
 func (sa *StudentAuth) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(sa); err != nil {
-		return
-	}
-
-	query := tx.Where("student_id = ?", sa.StudentID).Find(&StudentAuth{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(StudentAuthInstance.StudentID.KeyName(), string(sa.StudentID))
-	}
-	return
+	return validate.DBValidator.Struct(sa)
 }
 
 func (ta *TeacherAuth) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(ta); err != nil {
-		return
-	}
-
-	query := tx.Where("teacher_id = ?", ta.TeacherID).Find(&TeacherAuth{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(TeacherAuthInstance.TeacherID.KeyName(), string(ta.TeacherID))
-	}
-	return
+	return validate.DBValidator.Struct(ta)
 }
 
 func (pa *ParentAuth) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(pa); err != nil {
-		return
-	}
-
-	query := tx.Where("parent_id = ?", pa.ParentID).Find(&ParentAuth{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(ParentAuthInstance.ParentID.KeyName(), string(pa.ParentID))
-	}
-	return
+	return validate.DBValidator.Struct(pa)
 }
 
 func (si *StudentInform) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(si); err != nil {
-		return
-	}
-
-	query := tx.Where("phone_number = ?", si.PhoneNumber).Find(&StudentInform{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(StudentInformInstance.PhoneNumber.KeyName(), string(si.PhoneNumber))
-		return
-	}
-
-	query = tx.Where("profile_uri = ?", si.ProfileURI).Find(&StudentInform{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(StudentInformInstance.ProfileURI.KeyName(), string(si.ProfileURI))
-		return
-	}
-
-	query = tx.Where("grade = ? AND class = ? AND student_number = ?", si.Grade, si.Class, si.StudentNumber).Find(&StudentInform{})
-	if query.RowsAffected != 0 {
-		// number와 같은 key들 상수로 선언 및 관리 필요
-		err = mysqlerr.DuplicateEntry(si.StudentNumber.KeyName(), fmt.Sprintf("%d%d%02d", si.Grade, si.Class, si.StudentNumber))
-		return
-	}
-
-	return
+	return validate.DBValidator.Struct(si)
 }
 
 func (si *StudentInform) BeforeUpdate(tx *gorm.DB) (err error) {
@@ -96,37 +48,11 @@ func (si *StudentInform) BeforeUpdate(tx *gorm.DB) (err error) {
 	if informForValidate.PhoneNumber == emptyString { informForValidate.PhoneNumber = validPhoneNumber }
 	if informForValidate.ProfileURI == emptyString  { informForValidate.ProfileURI = validProfileURI }
 
-	if err = validate.DBValidator.Struct(informForValidate); err != nil {
-		return
-	}
-
-	if si.Grade != emptyInt && si.Class != emptyInt && si.StudentNumber != emptyInt {
-		studentNumberTable := tx.Where("grade = ? AND class = ? AND student_number = ?", si.Grade, si.Class, si.StudentNumber).Find(&StudentInform{})
-		if studentNumberTable.RowsAffected != 0 {
-			err = mysqlerr.DuplicateEntry(si.StudentNumber.KeyName(), fmt.Sprintf("%d%d%02d", si.Grade, si.Class, si.StudentNumber))
-		}
-	}
-	return
+	return validate.DBValidator.Struct(informForValidate)
 }
 
 func (ti *TeacherInform) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(ti); err != nil {
-		return
-	}
-
-	query := tx.Where("phone_number = ?", ti.PhoneNumber).Find(&TeacherInform{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(TeacherInformInstance.PhoneNumber.KeyName(), string(ti.PhoneNumber))
-	}
-
-	if ti.Grade != emptyInt && ti.Class != emptyInt  {
-		query = tx.Where("grade = ? AND class = ?", ti.Grade, ti.Class).Find(&TeacherInform{})
-		if query.RowsAffected != 0 {
-			err = mysqlerr.DuplicateEntry(ti.Class.KeyName(), fmt.Sprintf("%d%d", ti.Grade, ti.Class))
-			return
-		}
-	}
-	return
+	return validate.DBValidator.Struct(ti)
 }
 
 func (ti *TeacherInform) BeforeUpdate() (err error) {
@@ -142,15 +68,7 @@ func (ti *TeacherInform) BeforeUpdate() (err error) {
 }
 
 func (pi *ParentInform) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(pi); err != nil {
-		return
-	}
-
-	query := tx.Where("phone_number = ?", pi.PhoneNumber).Find(&ParentInform{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(ParentInformInstance.PhoneNumber.KeyName(), string(pi.PhoneNumber))
-	}
-	return
+	return validate.DBValidator.Struct(pi)
 }
 
 func (pi *ParentInform) BeforeUpdate() (err error) {
@@ -164,47 +82,71 @@ func (pi *ParentInform) BeforeUpdate() (err error) {
 }
 
 func (us *UnsignedStudent) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(us); err != nil {
-		return
-	}
+	return validate.DBValidator.Struct(us)
+}
 
-	query := tx.Where("auth_code = ?", us.AuthCode).Find(&UnsignedStudent{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(UnsignedStudentInstance.AuthCode.KeyName(), string(us.AuthCode))
-		return
-	}
+func (pc *ParentChildren) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(pc)
+}
 
-	query = tx.Where("phone_number = ?", us.PhoneNumber).Find(&UnsignedStudent{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(UnsignedStudentInstance.PhoneNumber.KeyName(), string(us.PhoneNumber))
-		return
-	}
+func (rt *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(rt)
+}
 
-	query = tx.Where("pre_profile_uri = ?", us.PreProfileURI).Find(&UnsignedStudent{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(UnsignedStudentInstance.PreProfileURI.KeyName(), string(us.PreProfileURI))
-		return
-	}
+func (rt *RevokedToken) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(rt)
+}
 
-	query = tx.Where("grade = ? AND class = ? AND student_number = ?", us.Grade, us.Class, us.StudentNumber).Find(&UnsignedStudent{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(us.StudentNumber.KeyName(), fmt.Sprintf("%d%d%02d", us.Grade, us.Class, us.StudentNumber))
-		return
-	}
+func (sr *SessionRevocation) BeforeCreate(tx *gorm.DB) (err error) {
+	// datetime column may round fraction of second up, so revocation time is truncated to second of iat claim
+	sr.RevokedBefore = sr.RevokedBefore.Truncate(time.Second)
+	return validate.DBValidator.Struct(sr)
+}
 
-	return
+func (s *Session) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(s)
 }
 
-func (pc *ParentChildren) BeforeCreate(tx *gorm.DB) (err error) {
-	if err = validate.DBValidator.Struct(pc); err != nil {
-		return
-	}
+func (al *AuthLog) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(al)
+}
+
+func (gs *GraduatedStudent) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(gs)
+}
+
+func (st *SchoolTerm) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(st)
+}
+
+func (mt *MessageTemplate) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(mt)
+}
+
+func (om *OutboxMessage) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(om)
+}
 
-	query := tx.Where("grade = ? AND class = ? AND student_number = ? AND name = ?", pc.Grade, pc.Class, pc.StudentNumber, pc.Name).Find(&ParentChildren{})
-	if query.RowsAffected != 0 {
-		err = mysqlerr.DuplicateEntry(pc.StudentNumber.KeyName(), fmt.Sprintf("%d%d%02d %s", pc.Grade, pc.Class, pc.StudentNumber, pc.Name))
-		return
-	}
+func (ae *AuditEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(ae)
+}
+
+func (lt *LoginThrottle) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(lt)
+}
+
+func (pr *PasswordReset) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(pr)
+}
+
+func (ph *PasswordHistory) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(ph)
+}
+
+func (tfa *TwoFactorAuth) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(tfa)
+}
 
-	return
+func (rc *RecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	return validate.DBValidator.Struct(rc)
 }
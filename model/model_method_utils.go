@@ -57,3 +57,18 @@ func (ti *TeacherInform)   TableName() string { return "teacher_informs" }
 func (pi *ParentInform)    TableName() string { return "parent_informs" }
 func (us *UnsignedStudent) TableName() string { return "unsigned_students" }
 func (pc *ParentChildren)  TableName() string { return "parent_children" }
+func (rt *RefreshToken)    TableName() string { return "refresh_tokens" }
+func (rt *RevokedToken)    TableName() string { return "revoked_tokens" }
+func (sr *SessionRevocation) TableName() string { return "session_revocations" }
+func (s *Session)          TableName() string { return "sessions" }
+func (al *AuthLog)         TableName() string { return "auth_logs" }
+func (ae *AuditEvent)      TableName() string { return "audit_events" }
+func (gs *GraduatedStudent) TableName() string { return "graduated_students" }
+func (st *SchoolTerm)      TableName() string { return "school_terms" }
+func (mt *MessageTemplate) TableName() string { return "message_templates" }
+func (om *OutboxMessage)   TableName() string { return "outbox_messages" }
+func (lt *LoginThrottle)   TableName() string { return "login_throttles" }
+func (pr *PasswordReset)   TableName() string { return "password_resets" }
+func (ph *PasswordHistory) TableName() string { return "password_histories" }
+func (tfa *TwoFactorAuth)  TableName() string { return "two_factor_auths" }
+func (rc *RecoveryCode)    TableName() string { return "recovery_codes" }
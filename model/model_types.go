@@ -55,35 +55,35 @@ func (sn studentNumber) KeyName() string { return "student_number" }
 type uuid string
 func UUID(s string) uuid { return uuid(s) }
 func (u uuid) Value() (driver.Value, error) { return string(u), nil }
-func (u *uuid) Scan(src interface{}) (err error) { *u = uuid(src.([]uint8)); return }
+func (u *uuid) Scan(src interface{}) (err error) { *u = uuid(convertToString(src)); return }
 func (u uuid) KeyName() string { return "uuid" }
 
 // StudentID 필드에서 사용할 사용자 정의 타입
 type studentID string
 func StudentID(s string) studentID { return studentID(s) }
 func (si studentID) Value() (driver.Value, error) { return string(si), nil }
-func (si *studentID) Scan(src interface{}) (err error) { *si = studentID(src.([]uint8)); return }
+func (si *studentID) Scan(src interface{}) (err error) { *si = studentID(convertToString(src)); return }
 func (si studentID) KeyName() string { return "student_id" }
 
 // StudentPW 필드에서 사용할 사용자 정의 타입
 type studentPW string
 func StudentPW(s string) studentPW { return studentPW(s) }
 func (sp studentPW) Value() (driver.Value, error) { return string(sp), nil }
-func (sp *studentPW) Scan(src interface{}) (err error) { *sp = studentPW(src.([]uint8)); return }
+func (sp *studentPW) Scan(src interface{}) (err error) { *sp = studentPW(convertToString(src)); return }
 func (sp studentPW) KeyName() string { return "student_pw" }
 
 // TeacherID 필드에서 사용할 사용자 정의 타입
 type teacherID string
 func TeacherID(s string) teacherID { return teacherID(s) }
 func (ti teacherID) Value() (driver.Value, error) { return string(ti), nil }
-func (ti *teacherID) Scan(src interface{}) (err error) { *ti = teacherID(src.([]uint8)); return }
+func (ti *teacherID) Scan(src interface{}) (err error) { *ti = teacherID(convertToString(src)); return }
 func (ti teacherID) KeyName() string { return "teacher_id" }
 
 // TeacherPW 필드에서 사용할 사용자 정의 타입
 type teacherPW string
 func TeacherPW(s string) teacherPW { return teacherPW(s) }
 func (tp teacherPW) Value() (driver.Value, error) { return string(tp), nil }
-func (tp *teacherPW) Scan(src interface{}) (err error) { *tp = teacherPW(src.([]uint8)); return }
+func (tp *teacherPW) Scan(src interface{}) (err error) { *tp = teacherPW(convertToString(src)); return }
 func (tp teacherPW) KeyName() string { return "teacher_pw" }
 
 // TeacherPW 필드에서 사용할 사용자 정의 타입
@@ -97,28 +97,28 @@ func (c certified) KeyName() string { return "certified" }
 type parentID string
 func ParentID(s string) parentID { return parentID(s) }
 func (pi parentID) Value() (driver.Value, error) { return string(pi), nil }
-func (pi *parentID) Scan(src interface{}) (err error) { *pi = parentID(src.([]uint8)); return }
+func (pi *parentID) Scan(src interface{}) (err error) { *pi = parentID(convertToString(src)); return }
 func (pi parentID) KeyName() string { return "parent_id" }
 
 // ParentPW 필드에서 사용할 사용자 정의 타입
 type parentPW string
 func ParentPW(s string) parentPW { return parentPW(s) }
 func (pp parentPW) Value() (driver.Value, error) { return string(pp), nil }
-func (pp *parentPW) Scan(src interface{}) (err error) { *pp = parentPW(src.([]uint8)); return }
+func (pp *parentPW) Scan(src interface{}) (err error) { *pp = parentPW(convertToString(src)); return }
 func (pp parentPW) KeyName() string { return "parent_pw" }
 
 // AdminID 필드에서 사용할 사용자 정의 타입
 type adminID string
 func AdminID(s string) adminID { return adminID(s) }
 func (ai adminID) Value() (driver.Value, error) { return string(ai), nil }
-func (ai *adminID) Scan(src interface{}) (err error) { *ai = adminID(src.([]uint8)); return }
+func (ai *adminID) Scan(src interface{}) (err error) { *ai = adminID(convertToString(src)); return }
 func (ai adminID) KeyName() string { return "admin_id" }
 
 // AdminPW 필드에서 사용할 사용자 정의 타입
 type adminPW string
 func AdminPW(s string) adminPW { return adminPW(s) }
 func (ap adminPW) Value() (driver.Value, error) { return string(ap), nil }
-func (ap *adminPW) Scan(src interface{}) (err error) { *ap = adminPW(src.([]uint8)); return }
+func (ap *adminPW) Scan(src interface{}) (err error) { *ap = adminPW(convertToString(src)); return }
 func (ap adminPW) KeyName() string { return "admin_pw" }
 
 // StudentUUID 필드에서 사용할 사용자 정의 타입
@@ -129,14 +129,14 @@ func (su studentUUID) Value() (value driver.Value, err error) {
 	if value == "" { value = nil }
 	return
 }
-func (su *studentUUID) Scan(src interface{}) (err error) { *su = studentUUID(src.([]uint8)); return }
+func (su *studentUUID) Scan(src interface{}) (err error) { *su = studentUUID(convertToString(src)); return }
 func (su studentUUID) KeyName() string { return "student_uuid" }
 
 // TeacherUUID 필드에서 사용할 사용자 정의 타입
 type teacherUUID string
 func TeacherUUID(s string) teacherUUID { return teacherUUID(s) }
 func (tu teacherUUID) Value() (driver.Value, error) { return string(tu), nil }
-func (tu *teacherUUID) Scan(src interface{}) (err error) { *tu = teacherUUID(src.([]uint8)); return }
+func (tu *teacherUUID) Scan(src interface{}) (err error) { *tu = teacherUUID(convertToString(src)); return }
 func (tu teacherUUID) KeyName() string { return "teacher_uuid" }
 
 // ParentUUID 필드에서 사용할 사용자 정의 타입
@@ -147,7 +147,7 @@ func (pu parentUUID) Value() (value driver.Value, err error) {
 	if value == "" { value = nil }
 	return
 }
-func (pu *parentUUID) Scan(src interface{}) (err error) { *pu = parentUUID(src.([]uint8)); return }
+func (pu *parentUUID) Scan(src interface{}) (err error) { *pu = parentUUID(convertToString(src)); return }
 func (pu parentUUID) KeyName() string { return "parent_uuid" }
 func (pu parentUUID) NullReplaceValue() string { return nullReplaceValueForParentUUID }
 
@@ -155,28 +155,28 @@ func (pu parentUUID) NullReplaceValue() string { return nullReplaceValueForParen
 type name string
 func Name(s string) name { return name(s) }
 func (n name) Value() (driver.Value, error) { return string(n), nil }
-func (n *name) Scan(src interface{}) (err error) { *n = name(src.([]uint8)); return }
+func (n *name) Scan(src interface{}) (err error) { *n = name(convertToString(src)); return }
 func (n name) KeyName() string { return "name" }
 
 // PhoneNumber 필드에서 사용할 사용자 정의 타입
 type phoneNumber string
 func PhoneNumber(s string) phoneNumber { return phoneNumber(s) }
 func (pn phoneNumber) Value() (driver.Value, error) { return string(pn), nil }
-func (pn *phoneNumber) Scan(src interface{}) (err error) { *pn = phoneNumber(src.([]uint8)); return }
+func (pn *phoneNumber) Scan(src interface{}) (err error) { *pn = phoneNumber(convertToString(src)); return }
 func (pn phoneNumber) KeyName() string { return "phone_number" }
 
 // ProfileURI 필드에서 사용할 사용자 정의 타입
 type profileURI string
 func ProfileURI(s string) profileURI { return profileURI(s) }
 func (pu profileURI) Value() (driver.Value, error) { return string(pu), nil }
-func (pu *profileURI) Scan(src interface{}) (err error) { *pu = profileURI(src.([]uint8)); return }
+func (pu *profileURI) Scan(src interface{}) (err error) { *pu = profileURI(convertToString(src)); return }
 func (pu profileURI) KeyName() string { return "profile_uri" }
 
 // parentStatus 필드에서 사용할 사용자 정의 타입
 type parentStatus string
 func ParentStatus(s string) parentStatus { return parentStatus(s) }
 func (ps parentStatus) Value() (driver.Value, error) { return string(ps), nil }
-func (ps *parentStatus) Scan(src interface{}) (err error) { *ps = parentStatus(src.([]uint8)); return }
+func (ps *parentStatus) Scan(src interface{}) (err error) { *ps = parentStatus(convertToString(src)); return }
 func (ps parentStatus) KeyName() string { return "parent_status" }
 func (ps *parentStatus) SetWithBool(conn, notify bool) {
 	if !conn && !notify {
@@ -208,7 +208,7 @@ func (ps parentStatus) GetBool() (conn, notify bool) {
 type preProfileURI string
 func PreProfileURI(s string) preProfileURI { return preProfileURI(s) }
 func (pu preProfileURI) Value() (driver.Value, error) { return string(pu), nil }
-func (pu *preProfileURI) Scan(src interface{}) (err error) { *pu = preProfileURI(src.([]uint8)); return }
+func (pu *preProfileURI) Scan(src interface{}) (err error) { *pu = preProfileURI(convertToString(src)); return }
 func (pu preProfileURI) KeyName() string { return "pre_profile_uri" }
 
 // AuthCode 필드에서 사용할 사용자 정의 타입
@@ -222,6 +222,62 @@ func (ac authCode) Value() (value driver.Value, err error) {
 func (ac *authCode) Scan(src interface{}) (err error) { *ac = authCode(convertToInt64(src)); return }
 func (ac authCode) KeyName() string { return "auth_code" }
 
+// TokenHash 필드에서 사용할 사용자 정의 타입
+type tokenHash string
+func TokenHash(s string) tokenHash { return tokenHash(s) }
+func (th tokenHash) Value() (driver.Value, error) { return string(th), nil }
+func (th *tokenHash) Scan(src interface{}) (err error) { *th = tokenHash(convertToString(src)); return }
+func (th tokenHash) KeyName() string { return "token_hash" }
+
+// OwnerUUID 필드에서 사용할 사용자 정의 타입
+type ownerUUID string
+func OwnerUUID(s string) ownerUUID { return ownerUUID(s) }
+func (ou ownerUUID) Value() (driver.Value, error) { return string(ou), nil }
+func (ou *ownerUUID) Scan(src interface{}) (err error) { *ou = ownerUUID(convertToString(src)); return }
+func (ou ownerUUID) KeyName() string { return "owner_uuid" }
+
+// TokenID 필드에서 사용할 사용자 정의 타입
+type tokenID string
+func TokenID(s string) tokenID { return tokenID(s) }
+func (ti tokenID) Value() (driver.Value, error) { return string(ti), nil }
+func (ti *tokenID) Scan(src interface{}) (err error) { *ti = tokenID(convertToString(src)); return }
+func (ti tokenID) KeyName() string { return "token_id" }
+
+// SessionID 필드에서 사용할 사용자 정의 타입
+type sessionID string
+func SessionID(s string) sessionID { return sessionID(s) }
+func (si sessionID) Value() (driver.Value, error) { return string(si), nil }
+func (si *sessionID) Scan(src interface{}) (err error) { *si = sessionID(convertToString(src)); return }
+func (si sessionID) KeyName() string { return "session_id" }
+
+// ThrottleKey 필드에서 사용할 사용자 정의 타입
+type throttleKey string
+func ThrottleKey(s string) throttleKey { return throttleKey(s) }
+func (tk throttleKey) Value() (driver.Value, error) { return string(tk), nil }
+func (tk *throttleKey) Scan(src interface{}) (err error) { *tk = throttleKey(convertToString(src)); return }
+func (tk throttleKey) KeyName() string { return "throttle_key" }
+
+// CodeHash 필드에서 사용할 사용자 정의 타입
+type codeHash string
+func CodeHash(s string) codeHash { return codeHash(s) }
+func (ch codeHash) Value() (driver.Value, error) { return string(ch), nil }
+func (ch *codeHash) Scan(src interface{}) (err error) { *ch = codeHash(convertToString(src)); return }
+func (ch codeHash) KeyName() string { return "code_hash" }
+
+// HashedPW 필드에서 사용할 사용자 정의 타입
+type hashedPW string
+func HashedPW(s string) hashedPW { return hashedPW(s) }
+func (hp hashedPW) Value() (driver.Value, error) { return string(hp), nil }
+func (hp *hashedPW) Scan(src interface{}) (err error) { *hp = hashedPW(convertToString(src)); return }
+func (hp hashedPW) KeyName() string { return "hashed_pw" }
+
+// Secret(TOTP) 필드에서 사용할 사용자 정의 타입
+type totpSecret string
+func TOTPSecret(s string) totpSecret { return totpSecret(s) }
+func (ts totpSecret) Value() (driver.Value, error) { return string(ts), nil }
+func (ts *totpSecret) Scan(src interface{}) (err error) { *ts = totpSecret(convertToString(src)); return }
+func (ts totpSecret) KeyName() string { return "secret" }
+
 func convertToInt64(src interface{}) int64 {
 	switch src := src.(type) {
 	case int64:
@@ -237,7 +293,23 @@ func convertToInt64(src interface{}) int64 {
 	}
 }
 
+// string column is scanned as string in SQLite, instead of []uint8 in MySQL (add in v.1.2.0)
+func convertToString(src interface{}) string {
+	switch src := src.(type) {
+	case []uint8:
+		return string(src)
+	case string:
+		return src
+	default:
+		panic(fmt.Sprintf("cannot convert interface{} to string, src: %v, type: %s", src, reflect.TypeOf(src).String()))
+	}
+}
+
 func convertToBool(src interface{}) bool {
+	// boolean column is scanned as bool in SQLite (add in v.1.2.0)
+	if src, ok := src.(bool); ok {
+		return src
+	}
 	switch src := src.(int64); src {
 	case 0:
 		return false
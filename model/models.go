@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/jinzhu/gorm"
+	"time"
 )
 
 // 학생 계정 테이블
@@ -17,25 +18,26 @@ type StudentAuth struct {
 type StudentInform struct {
 	gorm.Model
 	StudentUUID   studentUUID   `gorm:"Type:char(20);UNIQUE;NOT NULL" validate:"uuid=student,len=20"` // 형식 => 'student-' + 12자리 랜덤 수 (20자)
-	Grade         grade         `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~3"`                // 1~3 사이 값
-	Class         class         `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~4"`                // 1~4 사이 값
-	StudentNumber studentNumber `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~21"`               // 1~21 사이 값
+	Grade         grade         `gorm:"Type:smallint;NOT NULL" validate:"range=1~3"`                  // 1~3 사이 값
+	Class         class         `gorm:"Type:smallint;NOT NULL" validate:"range=1~4"`                  // 1~4 사이 값
+	StudentNumber studentNumber `gorm:"Type:smallint;NOT NULL" validate:"range=1~21"`                 // 1~21 사이 값
 	Name          name          `gorm:"Type:varchar(4);NOT NULL" validate:"min=2,max=4,korean"`       // 2~4자 사이 한글
 	PhoneNumber   phoneNumber   `gorm:"Type:char(11);NOT NULL" validate:"len=11,phone_number"`        // 11자
-	ProfileURI    profileURI    `gorm:"Type:varchar(150);NOT NULL"`                                   // 제약 조건 나중에 추가 예정
-	ParentStatus  parentStatus  `gorm:"varchar(30);default:OK_CONN_OK_NOTIFY;NOT NULL"`
+	ProfileURI    profileURI    `gorm:"Type:varchar(150);NOT NULL"`                                   // 삭제되지 않은 학생 사이에서 유일 (add in v.1.2.0)
+	ParentStatus  parentStatus  `gorm:"varchar(30);default:'OK_CONN_OK_NOTIFY';NOT NULL"`
 }
 
 // 계정 생성 전 사전에 인증된 사용자 정보 테이블
 type UnsignedStudent struct {
 	gorm.Model
-	AuthCode      authCode      `gorm:"Type:int(11);NOT NULL" validate:"range=100000~999999"`   // 6자리 숫자
-	Grade         grade         `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~3"`          // 1~3 사이 값
-	Class         class         `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~4"`          // 1~4 사이 값
-	StudentNumber studentNumber `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~21"`         // 1~21 사이 값
-	Name          name          `gorm:"Type:varchar(4);NOT NULL" validate:"min=2,max=4,korean"` // 2~4자 사이 한글
-	PhoneNumber   phoneNumber   `gorm:"Type:char(11);NOT NULL" validate:"len=11,phone_number"`  // 11자
-	PreProfileURI preProfileURI `gorm:"Type:varchar(150);NOT NULL"`
+	AuthCode          authCode      `gorm:"Type:integer;NOT NULL;UNIQUE" validate:"range=100000~999999"` // 6자리 숫자
+	Grade             grade         `gorm:"Type:smallint;NOT NULL" validate:"range=1~3"`                 // 1~3 사이 값
+	Class             class         `gorm:"Type:smallint;NOT NULL" validate:"range=1~4"`                 // 1~4 사이 값
+	StudentNumber     studentNumber `gorm:"Type:smallint;NOT NULL" validate:"range=1~21"`                // 1~21 사이 값
+	Name              name          `gorm:"Type:varchar(4);NOT NULL" validate:"min=2,max=4,korean"`      // 2~4자 사이 한글
+	PhoneNumber       phoneNumber   `gorm:"Type:char(11);NOT NULL" validate:"len=11,phone_number"`       // 11자
+	PreProfileURI     preProfileURI `gorm:"Type:varchar(150);NOT NULL"`
+	AuthCodeExpiresAt *time.Time    // 인증 번호 만료 시간, v.1.2.0 이전에 발급된 인증 번호라면 NULL (만료되지 않음) (add in v.1.2.0)
 }
 
 // 선생님 계정 테이블
@@ -52,8 +54,8 @@ type TeacherInform struct {
 	gorm.Model
 	TeacherUUID teacherUUID `gorm:"Type:char(20);UNIQUE;NOT NULL'" validate:"uuid=teacher,len=20"` // 형식 => 'teacher-' + 12자리 랜덤 수 (20자)
 	Name        name        `gorm:"Type:varchar(4);NOT NULL" validate:"min=2,max=4"`               // 2~4자 사이 (원래 한글, PICK에서는 아니라서 지움)
-	Grade       grade       `gorm:"Type:tinyint(1);" validate:"range=0~3"`                         // in (1~3)
-	Class       class       `gorm:"Type:tinyint(1);" validate:"range=0~4"`                         // in (1~4)
+	Grade       grade       `gorm:"Type:smallint;" validate:"range=0~3"`                           // in (1~3)
+	Class       class       `gorm:"Type:smallint;" validate:"range=0~4"`                           // in (1~4)
 	PhoneNumber phoneNumber `gorm:"Type:char(11)" validate:"phone_number"`                         // 휴대전화 형식
 }
 
@@ -77,9 +79,9 @@ type ParentInform struct {
 type ParentChildren struct {
 	gorm.Model
 	ParentUUID    parentUUID    `gorm:"Type:char(19);NOT NULL" validate:"uuid=parent,len=19"` // 형식 => 'parent-' + 12자리 랜덤 수 (19자)
-	Grade         grade         `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~3"`               // 1~3 사이 값
-	Class         class         `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~4"`               // 1~4 사이 값
-	StudentNumber studentNumber `gorm:"Type:tinyint(1);NOT NULL" validate:"range=1~21"`              // 1~21 사이 값
+	Grade         grade         `gorm:"Type:smallint;NOT NULL" validate:"range=1~3"`                 // 1~3 사이 값
+	Class         class         `gorm:"Type:smallint;NOT NULL" validate:"range=1~4"`                 // 1~4 사이 값
+	StudentNumber studentNumber `gorm:"Type:smallint;NOT NULL" validate:"range=1~21"`                // 1~21 사이 값
 	Name          name          `gorm:"Type:varchar(4);NOT NULL" validate:"min=2,max=4,korean"`      // 2~4자 사이 한글
 	StudentUUID   studentUUID   `gorm:"Type:char(20)" validate:"uuid=student"`
 }
@@ -91,3 +93,153 @@ type AdminAuth struct {
 	AdminID adminID `gorm:"varchar(20);NOT NULL;UNIQUE" validate:"min=4,max=20,ascii"`
 	AdminPW adminPW `gorm:"varchar(100):NOT NULL;"`
 }
+
+// 리프레시 토큰 테이블 (add in v.1.2.0)
+type RefreshToken struct {
+	gorm.Model
+	TokenHash tokenHash `gorm:"Type:char(64);UNIQUE;NOT NULL" validate:"len=64,hexadecimal"`           // 토큰 원문 대신 SHA256 digest(64자) 저장
+	OwnerUUID ownerUUID `gorm:"Type:varchar(20);NOT NULL;INDEX" validate:"required,uuid=account,max=20"` // 토큰을 발급 받은 계정의 uuid
+	SessionID sessionID `gorm:"Type:char(36);INDEX" validate:"omitempty,len=36"`                       // 토큰이 발급된 로그인 세션의 id
+	ExpiresAt time.Time `gorm:"NOT NULL"`
+}
+
+// 폐기된 access 토큰 테이블 (add in v.1.2.0)
+type RevokedToken struct {
+	gorm.Model
+	TokenID   tokenID   `gorm:"Type:char(36);UNIQUE;NOT NULL" validate:"len=36"`                       // 폐기된 access 토큰의 jti
+	OwnerUUID ownerUUID `gorm:"Type:varchar(20);NOT NULL;INDEX" validate:"required,uuid=account,max=20"`
+	ExpiresAt time.Time `gorm:"NOT NULL"` // 토큰 만료 시간, 이후로는 폐기 기록이 필요 없음
+}
+
+// 로그인 실패 횟수 및 잠금 기록 테이블 (add in v.1.2.0)
+type LoginThrottle struct {
+	gorm.Model
+	ThrottleKey  throttleKey `gorm:"Type:varchar(100);UNIQUE;NOT NULL" validate:"required,max=100"` // ex) student:jinhong0719, source:127.0.0.1
+	FailureCount int64       `gorm:"NOT NULL"`
+	LockedUntil  *time.Time  // 잠금 해제 시간, 잠기지 않았다면 NULL
+}
+
+// 비밀번호 재설정 요청 테이블, 계정 당 하나의 요청만 유지 (add in v.1.2.0)
+type PasswordReset struct {
+	gorm.Model
+	OwnerUUID    ownerUUID `gorm:"Type:varchar(20);UNIQUE;NOT NULL" validate:"required,uuid=account,max=20"` // 비밀번호를 재설정할 계정의 uuid
+	CodeHash     codeHash  `gorm:"Type:char(64);NOT NULL" validate:"len=64,hexadecimal"`                    // 인증 코드 원문 대신 SHA256 digest(64자) 저장
+	ExpiresAt    time.Time `gorm:"NOT NULL"`
+	AttemptCount int64     `gorm:"NOT NULL"` // 잘못된 인증 코드 입력 횟수
+	SendCount    int64     `gorm:"NOT NULL"` // 인증 코드 발송 횟수, 마지막 발송 후 하루가 지나면 초기화
+	LastSentAt   time.Time `gorm:"NOT NULL"`
+}
+
+// 비밀번호 변경 이력 테이블, 계정 별로 최근에 사용한 비밀번호 해시를 보관 (add in v.1.2.0)
+type PasswordHistory struct {
+	gorm.Model
+	OwnerUUID ownerUUID `gorm:"Type:varchar(20);NOT NULL;INDEX" validate:"required,uuid=account,max=20"` // 비밀번호를 변경한 계정의 uuid
+	HashedPW  hashedPW  `gorm:"Type:varchar(150);NOT NULL" validate:"required,max=150"`                 // 변경되기 전 비밀번호의 해시 값
+}
+
+// TOTP 2단계 인증 정보 테이블, admin 과 teacher 계정만 등록 가능 (add in v.1.2.0)
+type TwoFactorAuth struct {
+	gorm.Model
+	OwnerUUID    ownerUUID  `gorm:"Type:varchar(20);UNIQUE;NOT NULL" validate:"required,uuid=account,max=20"` // 2단계 인증을 등록한 계정의 uuid
+	Secret       totpSecret `gorm:"Type:varchar(64);NOT NULL" validate:"required,max=64"`                      // base32 인코딩 된 TOTP secret
+	EnabledAt    *time.Time // 등록 후 첫 코드 확인 시간, 확인 전 이라면 NULL (로그인에 사용되지 않음)
+	LastUsedStep int64      `gorm:"NOT NULL"` // 마지막으로 사용된 코드의 time step, 같은 코드 재사용 방지
+}
+
+// TOTP 복구 코드 테이블, 사용된 코드는 삭제 (add in v.1.2.0)
+type RecoveryCode struct {
+	gorm.Model
+	OwnerUUID ownerUUID `gorm:"Type:varchar(20);NOT NULL;INDEX" validate:"required,uuid=account,max=20"`
+	CodeHash  codeHash  `gorm:"Type:char(64);NOT NULL" validate:"len=64,hexadecimal"` // 복구 코드 원문 대신 SHA256 digest(64자) 저장
+}
+
+// 로그인 세션(기기) 테이블, Login* RPC 호출 마다 생성되고 로그아웃 시 삭제 (add in v.1.2.0)
+type Session struct {
+	gorm.Model // CreatedAt 필드가 로그인 시간
+	SessionID  sessionID `gorm:"Type:char(36);UNIQUE;NOT NULL" validate:"len=36"`                       // access 토큰의 sid, refresh 토큰의 session_id
+	OwnerUUID  ownerUUID `gorm:"Type:varchar(20);NOT NULL;INDEX" validate:"required,uuid=account,max=20"` // 로그인 한 계정의 uuid
+	Device     string    `gorm:"Type:varchar(100)" validate:"max=100"`                                  // 클라이언트가 알려준 기기 이름 (ex: iPhone 12)
+	UserAgent  string    `gorm:"Type:varchar(500)" validate:"max=500"`
+	IPAddress  string    `gorm:"Type:varchar(45)" validate:"max=45"` // IPv6 최대 길이
+	LastSeenAt time.Time `gorm:"NOT NULL"`                           // 마지막으로 토큰을 갱신한 시간
+}
+
+// 인증 감사 로그 테이블, Login* 및 Change*PW RPC 호출 마다 결과와 함께 기록 (add in v.1.2.0)
+type AuthLog struct {
+	gorm.Model  // CreatedAt 필드가 요청 처리 시간
+	Action      string `gorm:"Type:varchar(30);NOT NULL;INDEX" validate:"required,max=30"` // 호출된 RPC 이름 (ex: LoginStudentAuth, ChangeStudentPW)
+	AccountType string `gorm:"Type:varchar(10)" validate:"max=10"`                        // admin, student, teacher, parent 중 하나
+	AccountID   string `gorm:"Type:varchar(20);INDEX" validate:"max=20"`                  // 로그인 시 입력한 계정 ID, 존재하지 않는 ID 일 수 있음
+	OwnerUUID   string `gorm:"Type:varchar(20);INDEX" validate:"max=20"`                  // 요청 대상 계정의 uuid, 로그인 실패 시 빈 값
+	Outcome     string `gorm:"Type:varchar(10);NOT NULL;INDEX" validate:"oneof=success failure"`
+	Status      uint32 `gorm:"NOT NULL"` // 응답 status
+	Code        int32  `gorm:"NOT NULL"` // 응답 code
+	RequestID   string `gorm:"Type:char(36)" validate:"max=36"` // X-Request-Id, Jaeger 에서 상세 내용을 찾을 때 사용
+	SourceIP    string `gorm:"Type:varchar(45)" validate:"max=45"`
+	UserAgent   string `gorm:"Type:varchar(500)" validate:"max=500"`
+}
+
+// 졸업생 보관 테이블, 학년도 전환 시 졸업하는 3학년 학생의 마지막 학적 정보 저장 (add in v.1.2.0)
+type GraduatedStudent struct {
+	gorm.Model
+	StudentUUID   studentUUID `gorm:"Type:char(20);NOT NULL;INDEX" validate:"uuid=student,len=20"`
+	AcademicYear  int64       `gorm:"NOT NULL;INDEX" validate:"min=2000,max=2100"`            // 졸업한 학년도 (ex: 2021)
+	Grade         grade       `gorm:"Type:smallint;NOT NULL" validate:"range=1~3"`            // 1~3 사이 값
+	Class         class       `gorm:"Type:smallint;NOT NULL" validate:"range=1~4"`            // 1~4 사이 값
+	StudentNumber studentNumber `gorm:"Type:smallint;NOT NULL" validate:"range=1~21"`         // 1~21 사이 값
+	Name          name        `gorm:"Type:varchar(4);NOT NULL" validate:"min=2,max=4,korean"` // 2~4자 사이 한글
+}
+
+// 학사 일정 설정 테이블, 가장 최근에 생성된 행이 현재 학년도 설정 (add in v.1.2.0)
+type SchoolTerm struct {
+	gorm.Model
+	AcademicYear   int64     `gorm:"NOT NULL" validate:"min=2000,max=2100"`                  // 학년도 (ex: 2021), S3 프로필 경로에 사용
+	SignupDeadline time.Time `gorm:"NOT NULL"`                                               // 재학생 회원가입 마감일, 회원가입 안내 문자에 사용
+	SchoolName     string    `gorm:"Type:varchar(50);NOT NULL" validate:"required,max=50"` // 회원가입 안내 문자 머리말에 사용
+}
+
+// 문자 메시지 템플릿 테이블, 수정 시 마다 버전을 올려 새 행을 생성하고 가장 높은 버전을 사용 (add in v.1.2.0)
+type MessageTemplate struct {
+	gorm.Model
+	Name        string `gorm:"Type:varchar(50);NOT NULL;UNIQUE_INDEX:idx_message_template_version" validate:"required,max=50"` // 템플릿 이름 (ex: join_sms)
+	Version     int64  `gorm:"NOT NULL;UNIQUE_INDEX:idx_message_template_version" validate:"min=1"`
+	MessageType string `gorm:"Type:char(3);NOT NULL" validate:"oneof=SMS LMS"`
+	Title       string `gorm:"Type:varchar(100)" validate:"max=100"`             // LMS 제목, SMS 일 경우 빈 값
+	Body        string `gorm:"Type:text;NOT NULL" validate:"required,max=2000"` // text/template 문법으로 작성된 본문
+	EditorUUID  string `gorm:"Type:varchar(20)" validate:"max=20"`              // 해당 버전을 작성한 관리자 uuid
+}
+
+// 문자 발송 대기열 테이블, 수신자 한 명 당 한 행이며 백그라운드 작업이 발송 후 결과 기록 (add in v.1.2.0)
+type OutboxMessage struct {
+	gorm.Model
+	BatchID       string     `gorm:"Type:char(36);NOT NULL;INDEX" validate:"len=36"`                        // 같은 요청으로 생성된 메시지 묶음 id
+	Receiver      string     `gorm:"Type:char(11);NOT NULL" validate:"len=11,phone_number"`                  // 수신 휴대전화 번호
+	Recipient     string     `gorm:"Type:varchar(50)" validate:"max=50"`                                     // 발송 현황에 표시할 수신자 설명 (ex: 2107 홍길동)
+	MessageType   string     `gorm:"Type:char(3);NOT NULL" validate:"oneof=SMS LMS"`
+	Title         string     `gorm:"Type:varchar(100)" validate:"max=100"`
+	Content       string     `gorm:"Type:text;NOT NULL" validate:"required"` // 발송 완료 또는 실패 후에는 인증 번호가 남지 않도록 가려서 저장
+	Status        string     `gorm:"Type:varchar(10);NOT NULL;INDEX" validate:"oneof=pending sent failed"`
+	AttemptCount  int64      `gorm:"NOT NULL"`
+	NextAttemptAt time.Time  `gorm:"NOT NULL;INDEX"`                   // 이 시간 이후에 발송(재시도) 대상, 발송 중에는 임대 만료 시간
+	MsgID         string     `gorm:"Type:varchar(30)" validate:"max=30"` // 문자 서비스가 발급한 메시지 id
+	LastError     string     `gorm:"Type:varchar(500)" validate:"max=500"`
+	SentAt        *time.Time // 발송 성공 시간, 성공 전이라면 NULL
+}
+
+// 관리 감사 로그 테이블, db.Accessor 를 통한 업무 데이터(계정, 사용자 정보, 예비 계정) 변경 마다 같은 tx 에서 기록 (add in v.1.2.0)
+type AuditEvent struct {
+	gorm.Model // CreatedAt 필드가 변경 시간
+	ActorUUID  string `gorm:"Type:varchar(20);INDEX" validate:"max=20"`                  // 변경을 요청한 계정의 uuid, 인증 없이 호출되는 RPC 에서는 빈 값
+	RPC        string `gorm:"Type:varchar(50);INDEX" validate:"max=50"`                  // 변경을 일으킨 RPC 이름 (ex: CreateNewStudent)
+	Entity     string `gorm:"Type:varchar(30);NOT NULL;INDEX" validate:"required,max=30"` // 변경된 테이블 이름 (ex: student_auths)
+	TargetKey  string `gorm:"Type:varchar(50);NOT NULL;INDEX" validate:"required,max=50"` // 변경된 행을 식별하는 값 (ex: uuid)
+	Action     string `gorm:"Type:varchar(10);NOT NULL" validate:"oneof=create update delete restore purge"`
+	Diff       string `gorm:"Type:text"` // {"컬럼": {"before": 값, "after": 값}} 형태의 JSON, 비밀번호와 인증 코드는 가려서 저장
+}
+
+// 계정 전체 세션 폐기 기록 테이블 (add in v.1.2.0)
+type SessionRevocation struct {
+	gorm.Model
+	OwnerUUID     ownerUUID `gorm:"Type:varchar(20);NOT NULL;INDEX" validate:"required,uuid=account,max=20"`
+	RevokedBefore time.Time `gorm:"NOT NULL"` // 이 시간 이전에 발급된 access 토큰은 모두 폐기된 것으로 간주, 초 단위로 버려서 저장 (같은 초에 발급된 토큰은 세션으로 판단)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
@@ -18,6 +19,7 @@ func init() {
 	if err := DBValidator.RegisterValidation("korean", isKoreanString);      err != nil { log.Fatal(err) } // 문자열 전용
 	if err := DBValidator.RegisterValidation("phone_number", isPhoneNumber); err != nil { log.Fatal(err) } // 문자열 전용
 	if err := DBValidator.RegisterValidation("range", isWithinRange);        err != nil { log.Fatal(err) } // 정수 전용
+	if err := DBValidator.RegisterValidation("timezone", isTimezone);        err != nil { log.Fatal(err) } // 문자열 전용 (add in v.1.2.0)
 }
 
 func isValidateUUID(fl validator.FieldLevel) bool {
@@ -34,6 +36,9 @@ func isValidateUUID(fl validator.FieldLevel) bool {
 		return teacherUUIDRegex.MatchString(fl.Field().String())
 	case "parent":
 		return parentUUIDRegex.MatchString(fl.Field().String())
+	case "account":
+		return adminUUIDRegex.MatchString(fl.Field().String()) || studentUUIDRegex.MatchString(fl.Field().String()) ||
+			teacherUUIDRegex.MatchString(fl.Field().String()) || parentUUIDRegex.MatchString(fl.Field().String())
 	}
 	return false
 }
@@ -75,4 +80,12 @@ func isWithinRange(fl validator.FieldLevel) bool {
 
 	field := int(fl.Field().Int())
 	return field >= start && field <= end
-}
\ No newline at end of file
+}
+
+func isTimezone(fl validator.FieldLevel) bool {
+	if fl.Field().String() == "" {
+		return true
+	}
+	_, err := time.LoadLocation(fl.Field().String())
+	return err == nil
+}
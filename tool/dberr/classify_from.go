@@ -0,0 +1,94 @@
+package dberr
+
+import (
+	"auth/tool/mysqlerr"
+	"database/sql"
+	"fmt"
+	mysqlcode "github.com/VividCortex/mysqlerr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	"regexp"
+	"strings"
+)
+
+// detail of PostgreSQL constraint error (ex: Key (grade, class, student_number)=(2, 2, 7) already exists.)
+var regexForPostgresKeyDetail = regexp.MustCompile(`^Key \((.*?)\)=\((.*?)\)`)
+
+// function that return *Error classified from err if err is returned from DB driver (or gorm), or err itself if not
+// SQLite error is returned as MySQL error from accessor (see db/sqlite.go), so it is classified as MySQL error
+func From(err error) error {
+	switch assertedError := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return assertedError
+	case *mysql.MySQLError:
+		return fromMySQL(assertedError)
+	case *pq.Error:
+		return fromPostgres(assertedError)
+	}
+
+	if gorm.IsRecordNotFoundError(err) || err == sql.ErrNoRows {
+		return &Error{Category: NotFound, Err: err}
+	}
+	return err
+}
+
+func fromMySQL(mysqlErr *mysql.MySQLError) *Error {
+	dbErr := &Error{Category: Other, Err: mysqlErr}
+
+	switch mysqlErr.Number {
+	case mysqlcode.ER_DUP_ENTRY:
+		dbErr.Category = Duplicate
+		dbErr.Key, dbErr.Entry, _ = mysqlerr.ParseDuplicateEntryErrorFrom(mysqlErr)
+	case mysqlcode.ER_NO_REFERENCED_ROW_2:
+		dbErr.Category = FKViolation
+		if fk, _, err := mysqlerr.ParseFKConstraintFailErrorFrom(mysqlErr); err == nil {
+			dbErr.Constraint, dbErr.Table, dbErr.Column = fk.ConstraintName, fk.TableName, fk.AttrName
+		}
+	case mysqlcode.ER_ROW_IS_REFERENCED_2:
+		dbErr.Category = FKViolation
+	}
+	return dbErr
+}
+
+func fromPostgres(pqErr *pq.Error) *Error {
+	dbErr := &Error{Category: Other, Err: pqErr}
+
+	columns, values := parsePostgresKeyDetail(pqErr.Detail)
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		dbErr.Category = Duplicate
+		dbErr.Key = keyNameOfPostgresConstraint(pqErr.Table, pqErr.Constraint)
+		dbErr.Entry = strings.Join(values, "-") // same format with entry of MySQL generated column (see db/migrations.go)
+	case "foreign_key_violation":
+		dbErr.Category = FKViolation
+		dbErr.Constraint, dbErr.Table = pqErr.Constraint, pqErr.Table
+		dbErr.Column = strings.Join(columns, ",")
+	}
+	return dbErr
+}
+
+// function that return name of columns & values in detail of PostgreSQL constraint error
+func parsePostgresKeyDetail(detail string) (columns, values []string) {
+	matched := regexForPostgresKeyDetail.FindStringSubmatch(detail)
+	if len(matched) != 3 {
+		return
+	}
+	columns = strings.Split(matched[1], ", ")
+	values = strings.Split(matched[2], ", ")
+	return
+}
+
+// constraint name is unique in whole schema in PostgreSQL, so table name is prefixed to it (ex: student_auths_pkey, student_auths_uuid_key)
+// prefix & suffix is removed to return the same key name with MySQL (ex: PRIMARY, uuid)
+func keyNameOfPostgresConstraint(table, constraint string) string {
+	if constraint == fmt.Sprintf("%s_pkey", table) {
+		return "PRIMARY"
+	}
+	if !strings.HasPrefix(constraint, table+"_") {
+		return constraint
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(constraint, table+"_"), "_key")
+}
@@ -0,0 +1,151 @@
+package dberr
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_From(t *testing.T) {
+	mysql8Duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'jinhong0719' for key 'student_auths.student_id'"}
+	mysql5Duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '2-2-7' for key 'student_number'"}
+	mysqlFKViolation := &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails " +
+		"(`auth`.`student_informs`, CONSTRAINT `student_informs_student_uuid_student_auths_uuid_foreign` FOREIGN KEY (`student_uuid`) " +
+		"REFERENCES `student_auths` (`uuid`) ON DELETE CASCADE ON UPDATE CASCADE)"}
+	pqDuplicate := &pq.Error{Code: "23505", Table: "student_auths", Constraint: "student_auths_student_id", Detail: "Key (student_id)=(jinhong0719) already exists."}
+	unexpectedError := errors.New("unexpected error")
+
+	tests := []struct {
+		Description   string
+		Err           error
+		ExpectedError error
+	}{
+		{
+			Description:   "MySQL 8 duplicate entry with table name prefixed to key name",
+			Err:           mysql8Duplicate,
+			ExpectedError: &Error{Category: Duplicate, Key: "student_id", Entry: "jinhong0719", Err: mysql8Duplicate},
+		}, {
+			Description:   "MySQL 5.7 duplicate entry",
+			Err:           mysql5Duplicate,
+			ExpectedError: &Error{Category: Duplicate, Key: "student_number", Entry: "2-2-7", Err: mysql5Duplicate},
+		}, {
+			Description: "MySQL FK violation",
+			Err:         mysqlFKViolation,
+			ExpectedError: &Error{Category: FKViolation, Constraint: "student_informs_student_uuid_student_auths_uuid_foreign",
+				Table: "student_informs", Column: "student_uuid", Err: mysqlFKViolation},
+		}, {
+			Description:   "PostgreSQL error",
+			Err:           pqDuplicate,
+			ExpectedError: &Error{Category: Duplicate, Key: "student_id", Entry: "jinhong0719", Err: pqDuplicate},
+		}, {
+			Description:   "record not found error of gorm",
+			Err:           gorm.ErrRecordNotFound,
+			ExpectedError: &Error{Category: NotFound, Err: gorm.ErrRecordNotFound},
+		}, {
+			Description:   "error not returned from DB is returned as it is",
+			Err:           unexpectedError,
+			ExpectedError: unexpectedError,
+		}, {
+			Description:   "nil",
+			Err:           nil,
+			ExpectedError: nil,
+		},
+	}
+
+	for _, test := range tests {
+		err := From(test.Err)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %s)", test.Description)
+	}
+}
+
+func Test_fromPostgres(t *testing.T) {
+	tests := []struct {
+		Description   string
+		PqErr         *pq.Error
+		ExpectedError *Error
+	}{
+		{
+			Description:   "primary key violation",
+			PqErr:         &pq.Error{Code: "23505", Table: "student_auths", Constraint: "student_auths_pkey", Detail: "Key (uuid)=(student-111111111111) already exists."},
+			ExpectedError: &Error{Category: Duplicate, Key: "PRIMARY", Entry: "student-111111111111"},
+		}, {
+			Description:   "partial unique index of alive rows named <table>_<name>",
+			PqErr:         &pq.Error{Code: "23505", Table: "parent_auths", Constraint: "parent_auths_parent_id", Detail: "Key (parent_id)=(jinhong0719) already exists."},
+			ExpectedError: &Error{Category: Duplicate, Key: "parent_id", Entry: "jinhong0719"},
+		}, {
+			Description: "unique key on multiple columns",
+			PqErr: &pq.Error{Code: "23505", Table: "student_informs", Constraint: "student_informs_student_number",
+				Detail: "Key (grade, class, student_number)=(2, 2, 7) already exists."},
+			ExpectedError: &Error{Category: Duplicate, Key: "student_number", Entry: "2-2-7"},
+		}, {
+			Description: "FK violation",
+			PqErr: &pq.Error{Code: "23503", Table: "student_informs", Constraint: "student_informs_student_uuid_student_auths_uuid_foreign",
+				Detail: "Key (student_uuid)=(student-111111111111) is not present in table \"student_auths\"."},
+			ExpectedError: &Error{Category: FKViolation, Constraint: "student_informs_student_uuid_student_auths_uuid_foreign",
+				Table: "student_informs", Column: "student_uuid"},
+		}, {
+			Description:   "error not classified",
+			PqErr:         &pq.Error{Code: "23502", Table: "student_auths", Column: "student_id"},
+			ExpectedError: &Error{Category: Other},
+		},
+	}
+
+	for _, test := range tests {
+		test.ExpectedError.Err = test.PqErr
+		err := fromPostgres(test.PqErr)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %s)", test.Description)
+	}
+}
+
+func Test_parsePostgresKeyDetail(t *testing.T) {
+	tests := []struct {
+		Detail          string
+		ExpectedColumns []string
+		ExpectedValues  []string
+	}{
+		{
+			Detail:          "Key (student_id)=(jinhong0719) already exists.",
+			ExpectedColumns: []string{"student_id"},
+			ExpectedValues:  []string{"jinhong0719"},
+		}, {
+			Detail:          "Key (grade, class, student_number)=(2, 2, 7) already exists.",
+			ExpectedColumns: []string{"grade", "class", "student_number"},
+			ExpectedValues:  []string{"2", "2", "7"},
+		}, {
+			Detail:          "Key (student_uuid)=(student-111111111111) is not present in table \"student_auths\".",
+			ExpectedColumns: []string{"student_uuid"},
+			ExpectedValues:  []string{"student-111111111111"},
+		}, {
+			Detail: "Failing row contains (null).",
+		}, {
+			Detail: "",
+		},
+	}
+
+	for _, test := range tests {
+		columns, values := parsePostgresKeyDetail(test.Detail)
+		assert.Equalf(t, test.ExpectedColumns, columns, "columns assertion error (detail: %s)", test.Detail)
+		assert.Equalf(t, test.ExpectedValues, values, "values assertion error (detail: %s)", test.Detail)
+	}
+}
+
+func Test_keyNameOfPostgresConstraint(t *testing.T) {
+	tests := []struct {
+		Table, Constraint string
+		ExpectedKey       string
+	}{
+		{Table: "student_auths", Constraint: "student_auths_pkey", ExpectedKey: "PRIMARY"},
+		{Table: "student_auths", Constraint: "student_auths_uuid_key", ExpectedKey: "uuid"},
+		{Table: "student_auths", Constraint: "student_auths_student_id", ExpectedKey: "student_id"},
+		{Table: "parent_children", Constraint: "parent_children_student_number", ExpectedKey: "student_number"},
+		{Table: "unsigned_students", Constraint: "auth_code", ExpectedKey: "auth_code"},
+	}
+
+	for _, test := range tests {
+		key := keyNameOfPostgresConstraint(test.Table, test.Constraint)
+		assert.Equalf(t, test.ExpectedKey, key, "key name assertion error (test case: %v)", test)
+	}
+}
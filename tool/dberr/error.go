@@ -0,0 +1,34 @@
+// add file in v.1.2.0
+// this file declare driver-neutral DB error, so that handler handles error of MySQL, PostgreSQL (and SQLite) in the same way
+
+package dberr
+
+type Category int
+
+const (
+	Other       Category = iota // error of DB driver not classified into category below
+	Duplicate                   // unique key (or primary key) is violated
+	FKViolation                 // referenced row doesn't exist, or referencing row exists in deleting parent row
+	NotFound                    // record is not found
+)
+
+type Error struct {
+	Category Category
+
+	// key name & value of duplicate entry in Duplicate (ex: student_number, 2-2-7)
+	Key, Entry string
+
+	// constraint name & table, column of FK in FKViolation (may be empty if driver doesn't report them)
+	Constraint, Table, Column string
+
+	// error returned from driver
+	Err error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+// function that return if err is classified into category
+func Is(err error, category Category) bool {
+	dbErr, ok := From(err).(*Error)
+	return ok && dbErr.Category == category
+}
@@ -0,0 +1,85 @@
+// add file in v.1.2.0
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+// argon2id hash is encoded in PHC string format, ex) $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+const (
+	argon2idPrefix       = "$argon2id$"
+	argon2idSaltLen      = 16
+	argon2idKeyLength    = 32
+	argon2idMinKeyLength = 16 // key shorter than this is not accepted in parsing hash
+)
+
+var errInvalidArgon2idHash = errors.New("invalid argon2id hash format")
+
+type argon2idParams struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func generateArgon2idHash(pw string, params argon2idParams) (hashedPW string, err error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err = rand.Read(salt); err != nil {
+		return
+	}
+
+	key := argon2.IDKey([]byte(pw), salt, params.time, params.memory, params.threads, argon2idKeyLength)
+	hashedPW = fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+	return
+}
+
+func parseArgon2idHash(hashedPW string) (params argon2idParams, salt, key []byte, err error) {
+	sep := strings.Split(hashedPW, "$")
+	if len(sep) != 6 {
+		err = errInvalidArgon2idHash
+		return
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(sep[2], "v=%d", &version); err != nil || version != argon2.Version {
+		err = errInvalidArgon2idHash
+		return
+	}
+	if _, err = fmt.Sscanf(sep[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		err = errInvalidArgon2idHash
+		return
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(sep[4]); err != nil {
+		err = errInvalidArgon2idHash
+		return
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(sep[5]); err != nil {
+		err = errInvalidArgon2idHash
+		return
+	}
+
+	// empty key would be matched with any password, and argon2 panics with zero time or threads
+	if len(salt) == 0 || len(key) < argon2idMinKeyLength || params.time < 1 || params.threads < 1 || params.memory < 8 * uint32(params.threads) {
+		err = errInvalidArgon2idHash
+	}
+	return
+}
+
+func compareArgon2idHashAndPassword(hashedPW, pw string) (err error) {
+	params, salt, key, err := parseArgon2idHash(hashedPW)
+	if err != nil {
+		return
+	}
+
+	compared := argon2.IDKey([]byte(pw), salt, params.time, params.memory, params.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, compared) != 1 {
+		err = ErrMismatchedHashAndPassword
+	}
+	return
+}
@@ -0,0 +1,137 @@
+package hash
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// low cost params used in test to run fast, not allowed in service
+var argon2idParamsForTest = argon2idParams{time: 1, memory: 64, threads: 1}
+
+func Test_parseArgon2idHash(t *testing.T) {
+	salt := base64.RawStdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	key := base64.RawStdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+	tests := []struct {
+		Description    string
+		HashedPW       string
+		ExpectedParams argon2idParams
+		ExpectedError  error
+	}{
+		{
+			Description:    "valid hash",
+			HashedPW:       "$argon2id$v=19$m=65536,t=3,p=2$" + salt + "$" + key,
+			ExpectedParams: argon2idParams{time: 3, memory: 65536, threads: 2},
+		}, {
+			Description:   "part missing",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=2$" + salt,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "unsupported version",
+			HashedPW:      "$argon2id$v=16$m=65536,t=3,p=2$" + salt + "$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "params not in m,t,p format",
+			HashedPW:      "$argon2id$v=19$t=3,m=65536,p=2$" + salt + "$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "threads overflowing uint8",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=256$" + salt + "$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "zero time",
+			HashedPW:      "$argon2id$v=19$m=65536,t=0,p=2$" + salt + "$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "zero threads",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=0$" + salt + "$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "memory less than 8 KiB per thread",
+			HashedPW:      "$argon2id$v=19$m=8,t=3,p=2$" + salt + "$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "salt not encoded in base64",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=2$!salt!$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "key not encoded in base64",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=2$" + salt + "$!key!",
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "empty salt",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=2$$" + key,
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "empty key",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=2$" + salt + "$",
+			ExpectedError: errInvalidArgon2idHash,
+		}, {
+			Description:   "too short key",
+			HashedPW:      "$argon2id$v=19$m=65536,t=3,p=2$" + salt + "$" + base64.RawStdEncoding.EncodeToString([]byte("short")),
+			ExpectedError: errInvalidArgon2idHash,
+		},
+	}
+
+	for _, test := range tests {
+		params, _, _, err := parseArgon2idHash(test.HashedPW)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %s)", test.Description)
+		if test.ExpectedError == nil {
+			assert.Equalf(t, test.ExpectedParams, params, "params assertion error (test case: %s)", test.Description)
+		}
+	}
+}
+
+func Test_compareArgon2idHashAndPassword(t *testing.T) {
+	hashedPW, err := generateArgon2idHash("testPW", argon2idParamsForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHashedPW, err := generateArgon2idHash("testPW", argon2idParamsForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqualf(t, hashedPW, otherHashedPW, "salt assertion error, hashes of the same pw must be different")
+
+	// hash without key part, ex) $argon2id$v=19$m=64,t=1,p=1$<salt>$
+	withoutKey := hashedPW[:strings.LastIndex(hashedPW, "$")+1]
+	tamperedKey, err := base64.RawStdEncoding.DecodeString(hashedPW[len(withoutKey):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperedKey[0] ^= 1
+
+	tests := []struct {
+		Description   string
+		HashedPW, PW  string
+		ExpectedError error
+	}{
+		{
+			Description: "correct pw",
+			HashedPW:    hashedPW,
+			PW:          "testPW",
+		}, {
+			Description:   "incorrect pw",
+			HashedPW:      hashedPW,
+			PW:            "incorrectPW",
+			ExpectedError: ErrMismatchedHashAndPassword,
+		}, {
+			Description:   "key tampered",
+			HashedPW:      withoutKey + base64.RawStdEncoding.EncodeToString(tamperedKey),
+			PW:            "testPW",
+			ExpectedError: ErrMismatchedHashAndPassword,
+		}, {
+			Description:   "empty key must not match any pw",
+			HashedPW:      withoutKey,
+			PW:            "anyPW",
+			ExpectedError: errInvalidArgon2idHash,
+		},
+	}
+
+	for _, test := range tests {
+		err := CompareHashAndPassword(test.HashedPW, test.PW)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %s)", test.Description)
+	}
+}
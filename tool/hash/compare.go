@@ -22,6 +22,8 @@ func CompareHashAndPassword(hashedPW, pw string) (err error) {
 		if !checkPbkdf2PasswordHash(hashedPW, pw) {
 			err = ErrMismatchedHashAndPassword
 		}
+	case strings.HasPrefix(hashedPW, argon2idPrefix): // add in v.1.2.0
+		err = compareArgon2idHashAndPassword(hashedPW, pw)
 	default:
 		if err = bcrypt.CompareHashAndPassword([]byte(hashedPW), []byte(pw)); err == bcrypt.ErrMismatchedHashAndPassword {
 			err = ErrMismatchedHashAndPassword
@@ -0,0 +1,14 @@
+// add file in v.1.2.0
+// digest.go is file that declare function to get digest of value which is not password (ex. refresh token)
+
+package hash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+func SHA256ToHex(value string) string {
+	digest := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,76 @@
+// add file in v.1.2.0
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+type Algorithm string
+
+const (
+	Bcrypt   Algorithm = "bcrypt"
+	Argon2id Algorithm = "argon2id"
+)
+
+// Policy decide algorithm and cost used to hash password
+// Cost means cost of bcrypt, or iteration(time) count of argon2id
+// Memory(KiB) and Threads are used only in argon2id
+type Policy struct {
+	Algorithm Algorithm
+	Cost      int
+	Memory    uint32
+	Threads   uint8
+}
+
+var (
+	DefaultPolicy = Policy{Algorithm: Bcrypt, Cost: 12}
+	ErrInvalidPolicy = errors.New("invalid hash policy")
+)
+
+func (p Policy) Validate() (err error) {
+	switch p.Algorithm {
+	case Bcrypt:
+		if p.Cost < bcrypt.MinCost || p.Cost > bcrypt.MaxCost {
+			err = fmt.Errorf("%w, bcrypt cost must be between %d and %d", ErrInvalidPolicy, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case Argon2id:
+		if p.Cost < 1 || p.Memory < 8 * uint32(p.Threads) || p.Threads < 1 {
+			err = fmt.Errorf("%w, argon2id needs time >= 1, threads >= 1 and memory >= 8 * threads KiB", ErrInvalidPolicy)
+		}
+	default:
+		err = fmt.Errorf("%w, unsupported algorithm: %s", ErrInvalidPolicy, p.Algorithm)
+	}
+	return
+}
+
+func (p Policy) GenerateFromPassword(pw string) (hashedPW string, err error) {
+	switch p.Algorithm {
+	case Bcrypt:
+		var hashedBytes []byte
+		hashedBytes, err = bcrypt.GenerateFromPassword([]byte(pw), p.Cost)
+		hashedPW = string(hashedBytes)
+	case Argon2id:
+		hashedPW, err = generateArgon2idHash(pw, argon2idParams{time: uint32(p.Cost), memory: p.Memory, threads: p.Threads})
+	default:
+		err = fmt.Errorf("%w, unsupported algorithm: %s", ErrInvalidPolicy, p.Algorithm)
+	}
+	return
+}
+
+// NeedsRehash return true if hashedPW was hashed with legacy algorithm(pbkdf2) or different algorithm and cost with policy
+func (p Policy) NeedsRehash(hashedPW string) bool {
+	switch true {
+	case pbkdf2Regex.MatchString(hashedPW):
+		return true
+	case strings.HasPrefix(hashedPW, argon2idPrefix):
+		params, _, _, err := parseArgon2idHash(hashedPW)
+		return err != nil || p.Algorithm != Argon2id ||
+			params != argon2idParams{time: uint32(p.Cost), memory: p.Memory, threads: p.Threads}
+	default:
+		cost, err := bcrypt.Cost([]byte(hashedPW))
+		return err != nil || p.Algorithm != Bcrypt || cost != p.Cost
+	}
+}
@@ -0,0 +1,130 @@
+package hash
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+func Test_Policy_Validate(t *testing.T) {
+	tests := []struct {
+		Policy      Policy
+		ExpectValid bool
+	}{
+		{Policy: DefaultPolicy, ExpectValid: true},
+		{Policy: Policy{Algorithm: Bcrypt, Cost: bcrypt.MinCost - 1}},
+		{Policy: Policy{Algorithm: Bcrypt, Cost: bcrypt.MaxCost + 1}},
+		{Policy: Policy{Algorithm: Argon2id, Cost: 3, Memory: 65536, Threads: 2}, ExpectValid: true},
+		{Policy: Policy{Algorithm: Argon2id, Cost: 0, Memory: 65536, Threads: 2}},
+		{Policy: Policy{Algorithm: Argon2id, Cost: 3, Memory: 65536, Threads: 0}},
+		{Policy: Policy{Algorithm: Argon2id, Cost: 3, Memory: 8, Threads: 2}},
+		{Policy: Policy{Algorithm: "scrypt", Cost: 3}},
+	}
+
+	for _, test := range tests {
+		err := test.Policy.Validate()
+		assert.Equalf(t, test.ExpectValid, err == nil, "validation assertion error (test case: %v, err: %v)", test, err)
+		if err != nil {
+			assert.Truef(t, errors.Is(err, ErrInvalidPolicy), "error wrapping assertion error (test case: %v, err: %v)", test, err)
+		}
+	}
+}
+
+func Test_Policy_GenerateFromPassword(t *testing.T) {
+	for _, policy := range []Policy{
+		{Algorithm: Bcrypt, Cost: bcrypt.MinCost},
+		{Algorithm: Argon2id, Cost: 1, Memory: 64, Threads: 1},
+	} {
+		hashedPW, err := policy.GenerateFromPassword("testPW")
+		assert.Equalf(t, nil, err, "error assertion error (policy: %v)", policy)
+		assert.Equalf(t, nil, CompareHashAndPassword(hashedPW, "testPW"), "correct pw assertion error (policy: %v)", policy)
+		assert.Equalf(t, ErrMismatchedHashAndPassword, CompareHashAndPassword(hashedPW, "incorrectPW"), "incorrect pw assertion error (policy: %v)", policy)
+		assert.Falsef(t, policy.NeedsRehash(hashedPW), "rehash assertion error of hash generated with the same policy (policy: %v)", policy)
+	}
+
+	_, err := Policy{Algorithm: "scrypt"}.GenerateFromPassword("testPW")
+	assert.Truef(t, errors.Is(err, ErrInvalidPolicy), "unsupported algorithm error assertion error (err: %v)", err)
+}
+
+func Test_Policy_NeedsRehash(t *testing.T) {
+	bcryptPolicy := Policy{Algorithm: Bcrypt, Cost: bcrypt.MinCost}
+	argon2idPolicy := Policy{Algorithm: Argon2id, Cost: 1, Memory: 64, Threads: 1}
+
+	bcryptHash, err := bcryptPolicy.GenerateFromPassword("testPW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon2idHash, err := argon2idPolicy.GenerateFromPassword("testPW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pbkdf2Hash = "pbkdf2:sha256:150000$salt$0123456789abcdef"
+
+	tests := []struct {
+		Description       string
+		Policy            Policy
+		HashedPW          string
+		ExpectNeedsRehash bool
+	}{
+		{
+			Description: "bcrypt hash with the same cost",
+			Policy:      bcryptPolicy,
+			HashedPW:    bcryptHash,
+		}, {
+			Description:       "bcrypt hash with other cost",
+			Policy:            Policy{Algorithm: Bcrypt, Cost: bcrypt.MinCost + 1},
+			HashedPW:          bcryptHash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "bcrypt hash in argon2id policy",
+			Policy:            argon2idPolicy,
+			HashedPW:          bcryptHash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description: "argon2id hash with the same params",
+			Policy:      argon2idPolicy,
+			HashedPW:    argon2idHash,
+		}, {
+			Description:       "argon2id hash with other time",
+			Policy:            Policy{Algorithm: Argon2id, Cost: 2, Memory: 64, Threads: 1},
+			HashedPW:          argon2idHash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "argon2id hash with other memory",
+			Policy:            Policy{Algorithm: Argon2id, Cost: 1, Memory: 128, Threads: 1},
+			HashedPW:          argon2idHash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "argon2id hash with other threads",
+			Policy:            Policy{Algorithm: Argon2id, Cost: 1, Memory: 64, Threads: 2},
+			HashedPW:          argon2idHash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "argon2id hash in bcrypt policy",
+			Policy:            bcryptPolicy,
+			HashedPW:          argon2idHash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "malformed argon2id hash",
+			Policy:            argon2idPolicy,
+			HashedPW:          "$argon2id$v=19$m=64,t=1,p=1$$",
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "legacy pbkdf2 hash",
+			Policy:            bcryptPolicy,
+			HashedPW:          pbkdf2Hash,
+			ExpectNeedsRehash: true,
+		}, {
+			Description:       "hash of unknown format",
+			Policy:            bcryptPolicy,
+			HashedPW:          "plain text",
+			ExpectNeedsRehash: true,
+		},
+	}
+
+	for _, test := range tests {
+		needsRehash := test.Policy.NeedsRehash(test.HashedPW)
+		assert.Equalf(t, test.ExpectNeedsRehash, needsRehash, "rehash assertion error (test case: %s)", test.Description)
+	}
+}
@@ -0,0 +1,40 @@
+// add package in v.1.2.0
+// jwt package is used for issuing and parsing HS256 signed json web token without external library
+// claims.go is file that declare claims struct included in payload of token
+
+package jwt
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidTokenFormat = errors.New("token must be consist of header, payload, signature")
+	ErrInvalidSignature   = errors.New("token signature is invalid")
+	ErrUnsupportedAlg     = errors.New("only HS256 algorithm is supported")
+	ErrExpiredToken       = errors.New("token is expired")
+	ErrTokenNotYetValid   = errors.New("token is not valid yet")
+)
+
+type Claims struct {
+	ID        string `json:"jti"`
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`  // uuid of account that own token
+	Role      string `json:"role"` // one of admin, student, teacher, parent
+	Purpose   string `json:"purpose,omitempty"` // empty in access token, "totp" in token that is only allowed to finish two-step login
+	SessionID string `json:"sid,omitempty"`     // id of login session that token is issued in
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf,omitempty"` // token is rejected before this time if not zero
+	ExpiresAt int64  `json:"exp"`
+}
+
+func (c Claims) Valid(now time.Time) error {
+	if c.ExpiresAt != 0 && now.Unix() >= c.ExpiresAt {
+		return ErrExpiredToken
+	}
+	if c.NotBefore != 0 && now.Unix() < c.NotBefore {
+		return ErrTokenNotYetValid
+	}
+	return nil
+}
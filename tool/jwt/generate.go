@@ -0,0 +1,47 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var encoding = base64.RawURLEncoding
+
+func GenerateWithHS256(claims Claims, key []byte) (token string, err error) {
+	if len(key) == 0 {
+		err = errors.New("key for signing token must not be empty")
+		return
+	}
+
+	headerJson, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to marshal header, err: %v", err))
+		return
+	}
+
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("unable to marshal claims, err: %v", err))
+		return
+	}
+
+	unsigned := strings.Join([]string{encoding.EncodeToString(headerJson), encoding.EncodeToString(claimsJson)}, ".")
+	token = strings.Join([]string{unsigned, encoding.EncodeToString(signWithHS256(unsigned, key))}, ".")
+	return
+}
+
+func signWithHS256(unsigned string, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
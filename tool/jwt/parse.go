@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+func ParseWithHS256(token string, key []byte) (claims Claims, err error) {
+	const (
+		headerIndex = iota
+		claimsIndex
+		signatureIndex
+	)
+
+	// any token would be verified with signature made by empty key, so it is rejected as generating is
+	if len(key) == 0 {
+		err = errors.New("key for verifying token must not be empty")
+		return
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		err = ErrInvalidTokenFormat
+		return
+	}
+
+	headerJson, err := encoding.DecodeString(parts[headerIndex])
+	if err != nil {
+		err = ErrInvalidTokenFormat
+		return
+	}
+
+	h := header{}
+	if err = json.Unmarshal(headerJson, &h); err != nil {
+		err = ErrInvalidTokenFormat
+		return
+	}
+	if h.Alg != "HS256" {
+		err = ErrUnsupportedAlg
+		return
+	}
+
+	signature, err := encoding.DecodeString(parts[signatureIndex])
+	if err != nil {
+		err = ErrInvalidTokenFormat
+		return
+	}
+
+	unsigned := strings.Join(parts[:signatureIndex], ".")
+	if !hmac.Equal(signature, signWithHS256(unsigned, key)) {
+		err = ErrInvalidSignature
+		return
+	}
+
+	claimsJson, err := encoding.DecodeString(parts[claimsIndex])
+	if err != nil {
+		err = ErrInvalidTokenFormat
+		return
+	}
+
+	if err = json.Unmarshal(claimsJson, &claims); err != nil {
+		err = ErrInvalidTokenFormat
+		return
+	}
+
+	err = claims.Valid(time.Now())
+	return
+}
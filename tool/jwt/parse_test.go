@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var keyForTest = []byte("secret-key-for-test")
+
+// function that return token signed with HS256 regardless of alg in header, used for making token that GenerateWithHS256 doesn't make
+func signedTokenForTest(t *testing.T, h header, claims Claims, key []byte) string {
+	headerJson, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := encoding.EncodeToString(headerJson) + "." + encoding.EncodeToString(claimsJson)
+	return unsigned + "." + encoding.EncodeToString(signWithHS256(unsigned, key))
+}
+
+func Test_ParseWithHS256(t *testing.T) {
+	now := time.Now()
+	validClaims := Claims{
+		ID:        "8a9c7b5e-3f2d-4e1a-9b6c-0d5e4f3a2b1c",
+		Issuer:    "DMS.SMS.v1.service.auth",
+		Subject:   "student-111111111111",
+		Role:      "student",
+		SessionID: "3f1d2c4b-5a6e-4f70-8b9c-1d2e3f4a5b6c",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+
+	validToken, err := GenerateWithHS256(validClaims, keyForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(validToken, ".")
+
+	// payload changed to other account without signing again
+	tamperedClaims := validClaims
+	tamperedClaims.Subject = "admin-111111111111"
+	tamperedClaims.Role = "admin"
+	tamperedClaimsJson, _ := json.Marshal(tamperedClaims)
+	tamperedToken := parts[0] + "." + encoding.EncodeToString(tamperedClaimsJson) + "." + parts[2]
+
+	noneHeaderJson, _ := json.Marshal(header{Alg: "none", Typ: "JWT"})
+	noneToken := encoding.EncodeToString(noneHeaderJson) + "." + parts[1] + "."
+
+	expiredClaims := validClaims
+	expiredClaims.IssuedAt, expiredClaims.ExpiresAt = now.Add(-2 * time.Hour).Unix(), now.Add(-time.Hour).Unix()
+	notYetValidClaims := validClaims
+	notYetValidClaims.NotBefore = now.Add(time.Hour).Unix()
+
+	tests := []struct {
+		Description    string
+		Token          string
+		Key            []byte
+		ExpectedClaims Claims
+		ExpectedError  error
+	}{
+		{
+			Description:    "valid token",
+			Token:          validToken,
+			Key:            keyForTest,
+			ExpectedClaims: validClaims,
+		}, {
+			Description:    "token signed with other key",
+			Token:          validToken,
+			Key:            []byte("other-secret-key"),
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrInvalidSignature,
+		}, {
+			Description:    "payload tampered after signed",
+			Token:          tamperedToken,
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrInvalidSignature,
+		}, {
+			Description:    "signature tampered",
+			Token:          parts[0] + "." + parts[1] + "." + encoding.EncodeToString([]byte("tampered signature")),
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrInvalidSignature,
+		}, {
+			Description:    "alg none without signature",
+			Token:          noneToken,
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrUnsupportedAlg,
+		}, {
+			Description:    "alg other than HS256 even if signed with HS256",
+			Token:          signedTokenForTest(t, header{Alg: "HS512", Typ: "JWT"}, validClaims, keyForTest),
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrUnsupportedAlg,
+		}, {
+			Description:    "alg of asymmetric key",
+			Token:          signedTokenForTest(t, header{Alg: "RS256", Typ: "JWT"}, validClaims, keyForTest),
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrUnsupportedAlg,
+		}, {
+			Description:    "expired token",
+			Token:          signedTokenForTest(t, header{Alg: "HS256", Typ: "JWT"}, expiredClaims, keyForTest),
+			Key:            keyForTest,
+			ExpectedClaims: expiredClaims,
+			ExpectedError:  ErrExpiredToken,
+		}, {
+			Description:    "token not valid yet",
+			Token:          signedTokenForTest(t, header{Alg: "HS256", Typ: "JWT"}, notYetValidClaims, keyForTest),
+			Key:            keyForTest,
+			ExpectedClaims: notYetValidClaims,
+			ExpectedError:  ErrTokenNotYetValid,
+		}, {
+			Description:    "token without signature part",
+			Token:          parts[0] + "." + parts[1],
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrInvalidTokenFormat,
+		}, {
+			Description:    "header not encoded in base64url",
+			Token:          "{\"alg\":\"HS256\"}." + parts[1] + "." + parts[2],
+			Key:            keyForTest,
+			ExpectedClaims: Claims{},
+			ExpectedError:  ErrInvalidTokenFormat,
+		},
+	}
+
+	for _, test := range tests {
+		claims, err := ParseWithHS256(test.Token, test.Key)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %s)", test.Description)
+		assert.Equalf(t, test.ExpectedClaims, claims, "claims assertion error (test case: %s)", test.Description)
+	}
+
+	_, err = ParseWithHS256(validToken, nil)
+	assert.NotNilf(t, err, "error assertion error while parsing with empty key")
+}
+
+func Test_GenerateWithHS256(t *testing.T) {
+	_, err := GenerateWithHS256(Claims{Subject: "student-111111111111"}, nil)
+	assert.NotNilf(t, err, "error assertion error while generating with empty key")
+
+	token, err := GenerateWithHS256(Claims{Subject: "student-111111111111"}, keyForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headerJson, err := encoding.DecodeString(strings.Split(token, ".")[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := header{}
+	assert.Equalf(t, nil, json.Unmarshal(headerJson, &h), "header unmarshal assertion error")
+	assert.Equalf(t, header{Alg: "HS256", Typ: "JWT"}, h, "header assertion error")
+}
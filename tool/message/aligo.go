@@ -0,0 +1,136 @@
+// add package in v.1.0.5
+// message package is used for sending sms or mms message from 'ALIGO 문자 서비스'
+// aligo.go is file that sending message with ALIGO HTTP API (renamed from send.go and changed to Sender implementation in v.1.2.0)
+
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type aligo struct {
+	apiKey    string
+	accountID string
+	sender    string
+	client    *http.Client
+}
+
+// function that return Sender sending message with ALIGO account, sender is phone number registered in ALIGO
+func Aligo(apiKey, accountID, sender string) Sender {
+	return &aligo{
+		apiKey:    apiKey,
+		accountID: accountID,
+		sender:    sender,
+		client:    &http.Client{},
+	}
+}
+
+// function that return ALIGO Sender with ALIGO_API_KEY, ALIGO_ACCOUNT_ID and ALIGO_SENDER environment variable
+func AligoFromEnv() (Sender, error) {
+	apiKey := os.Getenv("ALIGO_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("please set ALIGO_API_KEY in environment variable")
+	}
+	accountID := os.Getenv("ALIGO_ACCOUNT_ID")
+	if accountID == "" {
+		return nil, errors.New("please set ALIGO_ACCOUNT_ID in environment variable")
+	}
+	sender := os.Getenv("ALIGO_SENDER")
+	if sender == "" {
+		return nil, errors.New("please set ALIGO_SENDER in environment variable")
+	}
+	return Aligo(apiKey, accountID, sender), nil
+}
+
+func (a *aligo) SendMassToReceivers(receivers, contents []string, _type, title string) (jsonResp SendMassToReceiversResponse, err error) {
+	if err = validateMassRequest(receivers, contents, _type, title); err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", "https://apis.aligo.in/send_mass/", nil)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("some error occurs while creating request, err: %v", err))
+		return
+	}
+
+	q := req.URL.Query()
+	q.Add("key", a.apiKey)
+	q.Add("user_id", a.accountID)
+	q.Add("sender", a.sender)
+	if _type != "" {
+		q.Add("msg_type", _type)
+	}
+	if title != "" {
+		q.Add("title", title)
+	}
+	for i, receiver := range receivers {
+		q.Add(fmt.Sprintf("rec_%d", i+1), receiver)
+		q.Add(fmt.Sprintf("msg_%d", i+1), contents[i])
+	}
+	q.Add("cnt", strconv.Itoa(len(receivers)))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("some error occurs while sending request, err: %v", err))
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	jsonResp = SendMassToReceiversResponse{}
+	_ = json.NewDecoder(resp.Body).Decode(&jsonResp)
+	if resp.StatusCode != http.StatusOK || jsonResp.ResultCode != 0 {
+		err = errors.New(fmt.Sprintf("failed to send mass message, status: %d, json response: %v", resp.StatusCode, jsonResp))
+		return
+	}
+
+	return
+}
+
+func (a *aligo) SendToReceivers(receivers []string, content , _type, title string) (jsonResp SendToReceiversResponse, err error) {
+	if err = validateRequest(receivers, _type, title); err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", "https://apis.aligo.in/send/", nil)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("some error occurs while creating request, err: %v", err))
+		return
+	}
+
+	q := req.URL.Query()
+	q.Add("key", a.apiKey)
+	q.Add("user_id", a.accountID)
+	q.Add("sender", a.sender)
+	if _type != "" {
+		q.Add("msg_type", _type)
+	}
+	if title != "" {
+		q.Add("title", title)
+	}
+	q.Add("receiver", strings.Join(receivers, ","))
+	q.Add("msg", content)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("some error occurs while sending request, err: %v", err))
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	jsonResp = SendToReceiversResponse{}
+	_ = json.NewDecoder(resp.Body).Decode(&jsonResp)
+	if resp.StatusCode != http.StatusOK || jsonResp.ResultCode != 0 {
+		err = errors.New(fmt.Sprintf("failed to send message, status: %d, json response: %v", resp.StatusCode, jsonResp))
+		return
+	}
+
+	return
+}
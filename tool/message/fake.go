@@ -0,0 +1,132 @@
+// add file in v.1.2.0
+// fake.go is file that declare Sender which doesn't send message to anyone, used in test and local development
+// sent messages are kept in memory, and also appended to file as JSON lines if file path is set
+
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// message recorded in Fake sender instead of sending
+type SentMessage struct {
+	MsgID    string    `json:"msg_id"`
+	Receiver string    `json:"receiver"`
+	Content  string    `json:"content"`
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+type Fake struct {
+	mutex    sync.Mutex
+	sent     []SentMessage
+	filePath string
+	err      error
+	lastID   int
+}
+
+// function that return Fake sender keeping sent messages only in memory
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// function that return Fake sender appending sent messages to file at path, so that developer can read message (ex: auth code)
+func NewFileFake(path string) *Fake {
+	return &Fake{filePath: path}
+}
+
+// method that make every send after call fail with err, pass nil to make send succeed again
+func (f *Fake) FailWith(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.err = err
+}
+
+// method that return copy of messages recorded until now
+func (f *Fake) Sent() []SentMessage {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]SentMessage(nil), f.sent...)
+}
+
+func (f *Fake) SendMassToReceivers(receivers, contents []string, _type, title string) (jsonResp SendMassToReceiversResponse, err error) {
+	if err = validateMassRequest(receivers, contents, _type, title); err != nil {
+		return
+	}
+
+	messages := make([]SentMessage, len(receivers))
+	for i, receiver := range receivers {
+		messages[i] = SentMessage{Receiver: receiver, Content: contents[i], Type: _type, Title: title, SentAt: time.Now()}
+	}
+	msgID, err := f.record(messages)
+	if err != nil {
+		return
+	}
+
+	jsonResp = SendMassToReceiversResponse{Message: "success", MsgID: msgID, SuccessCnt: len(receivers), MsgType: _type}
+	return
+}
+
+func (f *Fake) SendToReceivers(receivers []string, content, _type, title string) (jsonResp SendToReceiversResponse, err error) {
+	if err = validateRequest(receivers, _type, title); err != nil {
+		return
+	}
+
+	messages := make([]SentMessage, len(receivers))
+	for i, receiver := range receivers {
+		messages[i] = SentMessage{Receiver: receiver, Content: content, Type: _type, Title: title, SentAt: time.Now()}
+	}
+	msgID, err := f.record(messages)
+	if err != nil {
+		return
+	}
+
+	jsonResp.Message = "success"
+	jsonResp.MsgID = msgID
+	jsonResp.SuccessCnt = len(receivers)
+	jsonResp.MsgType = _type
+	return
+}
+
+// method that store messages with new message id, one id is issued for messages sent in one request like ALIGO
+func (f *Fake) record(messages []SentMessage) (msgID string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.err != nil {
+		err = f.err
+		return
+	}
+
+	f.lastID++
+	msgID = strconv.Itoa(f.lastID)
+	for i := range messages {
+		messages[i].MsgID = msgID
+	}
+
+	if f.filePath != "" {
+		file, openErr := os.OpenFile(f.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if openErr != nil {
+			err = fmt.Errorf("unable to open file of fake sender, err: %v", openErr)
+			return
+		}
+		defer func() { _ = file.Close() }()
+
+		encoder := json.NewEncoder(file)
+		for _, message := range messages {
+			if err = encoder.Encode(message); err != nil {
+				err = fmt.Errorf("unable to write message to file of fake sender, err: %v", err)
+				return
+			}
+		}
+	}
+
+	f.sent = append(f.sent, messages...)
+	return
+}
@@ -0,0 +1,58 @@
+// add file in v.1.2.0
+// sender.go is file that declare Sender interface implemented by each message provider
+// handler send message through Sender injected in it, so that provider can be replaced without changing handler (ex: fake in test)
+
+package message
+
+import (
+	"errors"
+)
+
+// Sender is interface of message provider, _type is one of blank(SMS), SMS, LMS and MMS and title can be set only in LMS and MMS
+type Sender interface {
+	SendMassToReceivers(receivers, contents []string, _type, title string) (jsonResp SendMassToReceiversResponse, err error)
+	SendToReceivers(receivers []string, content, _type, title string) (jsonResp SendToReceiversResponse, err error)
+}
+
+type SendMassToReceiversResponse struct {
+	ResultCode int    `json:"result_code"`
+	Message    string `json:"message"`
+	MsgID      string `json:"msg_id"`
+	SuccessCnt int    `json:"success_cnt"`
+	ErrorCnt   int    `json:"error_cnt"`
+	MsgType    string `json:"msg_type"`
+}
+
+type SendToReceiversResponse struct {
+	SendMassToReceiversResponse
+}
+
+// function that check type & title of message, every Sender must call it before sending
+func validateTypeAndTitle(_type, title string) (err error) {
+	if _type != "" && _type != "SMS" && _type != "LMS" && _type != "MMS" {
+		err = errors.New("type value must be blank or SMS or LMS or MMS")
+		return
+	}
+
+	if (_type == "SMS" || _type == "") && title != "" {
+		err = errors.New("cannot set title when type is black or SMS")
+		return
+	}
+	return
+}
+
+func validateMassRequest(receivers, contents []string, _type, title string) (err error) {
+	if (len(receivers) != len(contents)) || (len(receivers) < 1 || len(contents) < 1) {
+		err = errors.New("receivers & contents must be same length bigger than 0")
+		return
+	}
+	return validateTypeAndTitle(_type, title)
+}
+
+func validateRequest(receivers []string, _type, title string) (err error) {
+	if len(receivers) < 1 {
+		err = errors.New("receivers must be longer than 0")
+		return
+	}
+	return validateTypeAndTitle(_type, title)
+}
@@ -34,7 +34,8 @@ func ParseDuplicateEntryErrorFrom(mysqlErr *mysql.MySQLError) (key, entry string
 		matched[i] = strings.Trim(matched[i], "'")
 	}
 
-	key = matched[indexKey]
+	// MySQL 8.0 prefix table name to key name (ex: student_auths.student_id), so it is removed to return key name only
+	key = matched[indexKey][strings.LastIndex(matched[indexKey], ".")+1:]
 	entry = matched[indexEntry]
 	return
 }
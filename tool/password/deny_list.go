@@ -0,0 +1,22 @@
+// add file in v.1.2.0
+// deny_list.go is file that declare common passwords that is not allowed regardless of length and character classes
+// passwords are stored in lower case, and compared with lower case of password
+
+package password
+
+var commonPasswords = map[string]struct{}{}
+
+func init() {
+	for _, pw := range []string{
+		"12345678", "123456789", "1234567890", "12341234", "11111111", "00000000", "87654321", "11223344",
+		"password", "password1", "password12", "password123", "password!", "passw0rd", "p@ssw0rd", "p@ssword",
+		"qwerty12", "qwerty123", "qwertyuiop", "qwer1234", "qwer1234!", "asdf1234", "asdfasdf", "zxcv1234",
+		"1q2w3e4r", "1q2w3e4r!", "1q2w3e4r5t", "q1w2e3r4", "1qaz2wsx", "abcd1234", "abcd1234!", "abc12345",
+		"a1234567", "a12345678", "aa123456", "iloveyou", "iloveyou1", "sunshine", "princess", "football",
+		"baseball", "superman", "computer", "welcome1", "letmein1", "dragon12", "monkey12", "master12",
+		"trustno1", "starwars", "whatever", "changeme", "admin123", "admin1234", "administrator", "test1234",
+		"student1", "student123", "teacher1", "teacher123", "parent123", "school123", "dsm12345", "dsmhs1234",
+	} {
+		commonPasswords[pw] = struct{}{}
+	}
+}
@@ -0,0 +1,88 @@
+// add package in v.1.2.0
+// password package is used for checking strength of password before it is hashed and stored
+// policy.go is file that declare password policy and validation of it
+
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy decide which password is allowed to set
+// character classes are lower case, upper case, digit and symbol
+type Policy struct {
+	MinLength           int
+	MaxLength           int
+	MinCharacterClasses int
+	DenyCommonPasswords bool
+	DenyAccountID       bool
+}
+
+var DefaultPolicy = Policy{
+	MinLength:           8,
+	MaxLength:           72, // bcrypt ignore bytes after 72
+	MinCharacterClasses: 2,
+	DenyCommonPasswords: true,
+	DenyAccountID:       true,
+}
+
+var (
+	ErrTooShort                  = errors.New("password is too short")
+	ErrTooLong                   = errors.New("password is too long")
+	ErrNotEnoughCharacterClasses = errors.New("password doesn't contain enough character classes")
+	ErrCommonPassword            = errors.New("password is too common")
+	ErrContainsAccountID         = errors.New("password contains account id")
+)
+
+// Validate return error describing why pw violate policy, accountID is id of account that pw will be set
+func (p Policy) Validate(pw, accountID string) (err error) {
+	switch length := utf8.RuneCountInString(pw); true {
+	case length < p.MinLength:
+		return fmt.Errorf("%w, minimum length is %d", ErrTooShort, p.MinLength)
+	case p.MaxLength > 0 && len(pw) > p.MaxLength:
+		return fmt.Errorf("%w, maximum length is %d bytes", ErrTooLong, p.MaxLength)
+	}
+
+	if classes := characterClassesOf(pw); classes < p.MinCharacterClasses {
+		return fmt.Errorf("%w, at least %d of lower case, upper case, digit and symbol are needed", ErrNotEnoughCharacterClasses, p.MinCharacterClasses)
+	}
+
+	if p.DenyCommonPasswords {
+		if _, ok := commonPasswords[strings.ToLower(pw)]; ok {
+			return ErrCommonPassword
+		}
+	}
+
+	if p.DenyAccountID && accountID != "" && strings.Contains(strings.ToLower(pw), strings.ToLower(accountID)) {
+		return ErrContainsAccountID
+	}
+
+	return
+}
+
+func characterClassesOf(pw string) (classes int) {
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range pw {
+		switch true {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	for _, has := range []bool{hasLower, hasUpper, hasDigit, hasSymbol} {
+		if has {
+			classes++
+		}
+	}
+	return
+}
@@ -1,19 +1,26 @@
 package random
 
 import (
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"math/big"
 	"math/rand"
 	"strconv"
 	"time"
 )
 
+const accountUUIDDigitLength = 12
+
 var (
 	intLetters = []rune("0123456789")
+	lowerAlnumLetters = []rune("abcdefghijklmnopqrstuvwxyz0123456789")
 )
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
+// not secure, don't use it for generating id or code (use Secure* function instead)
 func StringConsistOfIntWithLength(length int) string {
 	randomRuneArr := make([]rune, length)
 	for i := range randomRuneArr {
@@ -26,4 +33,52 @@ func Int64WithLength(length int) int64 {
 	randomString := StringConsistOfIntWithLength(length)
 	stringToInt, _ := strconv.Atoi(randomString)
 	return int64(stringToInt)
-}
\ No newline at end of file
+}
+// add in v.1.2.0, used for generating opaque token (ex. refresh token) that must not be guessed
+func URLSafeStringWithByteLength(length int) (string, error) {
+	randomBytes := make([]byte, length)
+	if _, err := cryptorand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// add in v.1.2.0, used for generating numeric code (ex. password reset code) that must not be guessed
+func SecureStringConsistOfIntWithLength(length int) (string, error) {
+	return secureStringConsistOf(intLetters, length)
+}
+
+// add in v.1.2.0, used for generating code that user type by hand (ex. TOTP recovery code) that must not be guessed
+func SecureStringConsistOfLowerAlnumWithLength(length int) (string, error) {
+	return secureStringConsistOf(lowerAlnumLetters, length)
+}
+
+// add in v.1.2.0, used for generating account uuid (ex. student-123456789012), uniqueness of it must be enforced by DB constraint
+func SecureAccountUUIDWithPrefix(prefix string) (string, error) {
+	digits, err := SecureStringConsistOfIntWithLength(accountUUIDDigitLength)
+	if err != nil {
+		return "", err
+	}
+	return prefix + "-" + digits, nil
+}
+
+// add in v.1.2.0, used for generating numeric code in range [min, max] (ex. signup auth code) that must not be guessed
+func SecureInt64InRange(min, max int64) (int64, error) {
+	offset, err := cryptorand.Int(cryptorand.Reader, big.NewInt(max - min + 1))
+	if err != nil {
+		return 0, err
+	}
+	return min + offset.Int64(), nil
+}
+
+func secureStringConsistOf(letters []rune, length int) (string, error) {
+	randomRuneArr := make([]rune, length)
+	for i := range randomRuneArr {
+		index, err := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(len(letters))))
+		if err != nil {
+			return "", err
+		}
+		randomRuneArr[i] = letters[index.Int64()]
+	}
+	return string(randomRuneArr), nil
+}
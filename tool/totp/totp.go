@@ -0,0 +1,106 @@
+// add package in v.1.2.0
+// totp package is used for generating and validating time-based one-time password (RFC 6238) without external library
+// code is 6 digits of HMAC-SHA1 over 30 seconds time step, which is default of most authenticator apps
+
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Digits     = 6
+	Period     = 30 * time.Second
+	secretSize = 20 // 160 bits, recommended key length of HMAC-SHA1 in RFC 4226
+	skewSteps  = 1  // number of time step allowed before and after current step, for clock drift of device
+)
+
+var (
+	ErrInvalidSecret  = errors.New("totp secret must be base32 encoded string")
+	ErrMismatchedCode = errors.New("totp code is not matched with secret")
+	encoding          = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// GenerateSecret return base32 encoded random secret to share with authenticator app
+func GenerateSecret() (secret string, err error) {
+	randomBytes := make([]byte, secretSize)
+	if _, err = rand.Read(randomBytes); err != nil {
+		return
+	}
+	secret = encoding.EncodeToString(randomBytes)
+	return
+}
+
+// StepAt return time step counter of t
+func StepAt(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// CodeAt return code of secret in time step
+func CodeAt(secret string, step int64) (code string, err error) {
+	return codeAt(secret, step, Digits)
+}
+
+// function that return code of secret in time step with digits, separated from CodeAt to be tested with 8 digits vectors of RFC 6238
+func codeAt(secret string, step int64, digits int) (code string, err error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		err = ErrInvalidSecret
+		return
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation of RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	code = fmt.Sprintf("%0*d", digits, truncated%modulo)
+	return
+}
+
+// MatchedStep return time step that code is matched with, among steps around now
+// caller should reject step that is not greater than last used step, to prevent replay of code
+func MatchedStep(secret, code string, now time.Time) (step int64, err error) {
+	current := StepAt(now)
+	for s := current - skewSteps; s <= current+skewSteps; s++ {
+		var expected string
+		if expected, err = CodeAt(secret, s); err != nil {
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			step = s
+			return
+		}
+	}
+	err = ErrMismatchedCode
+	return
+}
+
+// KeyURI return otpauth URI that authenticator app can register with (usually as QR code)
+func KeyURI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("digits", fmt.Sprintf("%d", Digits))
+	query.Set("period", fmt.Sprintf("%d", int(Period/time.Second)))
+	return fmt.Sprintf("otpauth://totp/%s:%s?%s", url.PathEscape(issuer), url.PathEscape(accountName), query.Encode())
+}
@@ -0,0 +1,133 @@
+package totp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// base32 encoded ASCII "12345678901234567890", secret of SHA-1 test vectors in RFC 6238 Appendix B
+const rfcSecretForTest = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func Test_codeAt(t *testing.T) {
+	tests := []struct {
+		Time         int64
+		ExpectedCode string
+	}{
+		{Time: 59, ExpectedCode: "94287082"},
+		{Time: 1111111109, ExpectedCode: "07081804"},
+		{Time: 1111111111, ExpectedCode: "14050471"},
+		{Time: 1234567890, ExpectedCode: "89005924"},
+		{Time: 2000000000, ExpectedCode: "69279037"},
+		{Time: 20000000000, ExpectedCode: "65353130"},
+	}
+
+	for _, test := range tests {
+		step := StepAt(time.Unix(test.Time, 0))
+		code, err := codeAt(rfcSecretForTest, step, 8)
+		assert.Equalf(t, nil, err, "error assertion error (test case: %v)", test)
+		assert.Equalf(t, test.ExpectedCode, code, "8 digits code assertion error (test case: %v)", test)
+
+		// code of Digits is the last Digits digits of 8 digits code
+		code, err = CodeAt(rfcSecretForTest, step)
+		assert.Equalf(t, nil, err, "error assertion error (test case: %v)", test)
+		assert.Equalf(t, test.ExpectedCode[8-Digits:], code, "code assertion error (test case: %v)", test)
+	}
+
+	_, err := CodeAt("not base32 secret!", 1)
+	assert.Equalf(t, ErrInvalidSecret, err, "invalid secret error assertion error")
+
+	code, err := CodeAt(strings.ToLower(rfcSecretForTest), StepAt(time.Unix(59, 0)))
+	assert.Equalf(t, nil, err, "error assertion error with lower case secret")
+	assert.Equalf(t, "287082", code, "code assertion error with lower case secret")
+}
+
+func Test_MatchedStep(t *testing.T) {
+	// step of T=1111111109 in RFC 6238, stepStart is unix time of the first second of the step
+	codeStep := StepAt(time.Unix(1111111109, 0))
+	period := int64(Period / time.Second)
+	stepStart := codeStep * period
+	code, err := CodeAt(rfcSecretForTest, codeStep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Description   string
+		Secret, Code  string
+		Now           time.Time
+		ExpectedStep  int64
+		ExpectedError error
+	}{
+		{
+			Description:  "code of current step",
+			Secret:       rfcSecretForTest,
+			Code:         code,
+			Now:          time.Unix(stepStart+period-1, 0),
+			ExpectedStep: codeStep,
+		}, {
+			Description:  "code of previous step, at first second of next step",
+			Secret:       rfcSecretForTest,
+			Code:         code,
+			Now:          time.Unix(stepStart+period, 0),
+			ExpectedStep: codeStep,
+		}, {
+			Description:  "code of previous step, at last second of next step",
+			Secret:       rfcSecretForTest,
+			Code:         code,
+			Now:          time.Unix(stepStart+2*period-1, 0),
+			ExpectedStep: codeStep,
+		}, {
+			Description:   "code of two steps before",
+			Secret:        rfcSecretForTest,
+			Code:          code,
+			Now:           time.Unix(stepStart+2*period, 0),
+			ExpectedError: ErrMismatchedCode,
+		}, {
+			Description:  "code of next step, at last second of previous step",
+			Secret:       rfcSecretForTest,
+			Code:         code,
+			Now:          time.Unix(stepStart-1, 0),
+			ExpectedStep: codeStep,
+		}, {
+			Description:  "code of next step, at first second of previous step",
+			Secret:       rfcSecretForTest,
+			Code:         code,
+			Now:          time.Unix(stepStart-period, 0),
+			ExpectedStep: codeStep,
+		}, {
+			Description:   "code of two steps after",
+			Secret:        rfcSecretForTest,
+			Code:          code,
+			Now:           time.Unix(stepStart-period-1, 0),
+			ExpectedError: ErrMismatchedCode,
+		}, {
+			Description:   "incorrect code",
+			Secret:        rfcSecretForTest,
+			Code:          "000000",
+			Now:           time.Unix(stepStart, 0),
+			ExpectedError: ErrMismatchedCode,
+		}, {
+			Description:   "code with 8 digits",
+			Secret:        rfcSecretForTest,
+			Code:          "07081804",
+			Now:           time.Unix(stepStart, 0),
+			ExpectedError: ErrMismatchedCode,
+		}, {
+			Description:   "invalid secret",
+			Secret:        "not base32 secret!",
+			Code:          code,
+			Now:           time.Unix(stepStart, 0),
+			ExpectedError: ErrInvalidSecret,
+		},
+	}
+
+	for _, test := range tests {
+		step, err := MatchedStep(test.Secret, test.Code, test.Now)
+		assert.Equalf(t, test.ExpectedError, err, "error assertion error (test case: %s)", test.Description)
+		if test.ExpectedError == nil {
+			assert.Equalf(t, test.ExpectedStep, step, "step assertion error (test case: %s)", test.Description)
+		}
+	}
+}